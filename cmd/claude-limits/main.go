@@ -1,15 +1,29 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/benjaminabbitt/claude-limits/internal/cli"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
 )
 
 func main() {
-	if err := cli.RootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Cancel in-flight requests and retries on Ctrl+C / SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := cli.RootCmd.ExecuteContext(ctx)
+	stop()
+	cli.FlushTelemetry()
+	cli.PrintUpdateNotice()
+
+	if err != nil {
+		var status *apierrors.StatusError
+		if !apierrors.As(err, &status) {
+			cli.PrintError(err)
+			cli.RecordError(err)
+		}
+		os.Exit(apierrors.ExitCode(err))
 	}
 }
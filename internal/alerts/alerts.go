@@ -0,0 +1,570 @@
+// Package alerts detects utilization threshold crossings and delivers notifications.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Default utilization thresholds, matching the table's color breakpoints
+const (
+	DefaultWarning  = 80.0
+	DefaultCritical = 95.0
+)
+
+// Level is the severity of a window's utilization
+type Level int
+
+// Levels in increasing severity
+const (
+	LevelOK Level = iota
+	LevelWarning
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWarning:
+		return "warning"
+	case LevelCritical:
+		return "critical"
+	default:
+		return "ok"
+	}
+}
+
+// MarshalText encodes the level by name
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name
+func (l *Level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ok":
+		*l = LevelOK
+	case "warning":
+		*l = LevelWarning
+	case "critical":
+		*l = LevelCritical
+	default:
+		return fmt.Errorf("unknown alert level %q", text)
+	}
+	return nil
+}
+
+// Thresholds are the utilization percentages at which each level begins
+type Thresholds struct {
+	Warning  float64
+	Critical float64
+}
+
+// DefaultThresholds returns the default 80%/95% thresholds
+func DefaultThresholds() Thresholds {
+	return Thresholds{Warning: DefaultWarning, Critical: DefaultCritical}
+}
+
+// LevelFor returns the level for a utilization percentage
+func (t Thresholds) LevelFor(utilization float64) Level {
+	switch {
+	case utilization >= t.Critical:
+		return LevelCritical
+	case utilization >= t.Warning:
+		return LevelWarning
+	default:
+		return LevelOK
+	}
+}
+
+// ResetMode selects which resets send a KindReset alert
+type ResetMode string
+
+// Reset modes
+const (
+	ResetConstrained ResetMode = "constrained" // windows that had reached warning (default)
+	ResetAlways      ResetMode = "always"      // every window whose reset time advances
+	ResetNever       ResetMode = "never"
+)
+
+// ParseResetMode parses a reset mode name; empty is ResetConstrained
+func ParseResetMode(s string) (ResetMode, error) {
+	switch m := ResetMode(s); m {
+	case "":
+		return ResetConstrained, nil
+	case ResetConstrained, ResetAlways, ResetNever:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown reset mode %q (use constrained, always, or never)", s)
+}
+
+// Policy decides when a window alerts
+type Policy struct {
+	Thresholds
+	// Cooldown is the least time between two alerts of the same level, or
+	// two resets, for a window, to quiet a window hovering at a threshold.
+	// Zero alerts on every crossing.
+	Cooldown time.Duration
+	// OnReset selects which resets alert; empty is ResetConstrained
+	OnReset ResetMode
+	// RemindBefore sends a KindReminder alert this long before the window
+	// resets. Zero sends none.
+	RemindBefore time.Duration
+}
+
+// Policies holds the policy for each window
+type Policies struct {
+	// Default applies to windows not in Windows
+	Default Policy
+	// Windows maps window names to their own policies
+	Windows map[string]Policy
+}
+
+// DefaultPolicies returns the default thresholds for every window, without
+// a cooldown
+func DefaultPolicies() Policies {
+	return Policies{Default: Policy{Thresholds: DefaultThresholds()}}
+}
+
+// For returns the policy for a window
+func (p Policies) For(window string) Policy {
+	if policy, ok := p.Windows[window]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// Kind distinguishes why an alert fired
+type Kind string
+
+// Alert kinds
+const (
+	KindThreshold Kind = "threshold" // utilization rose into a higher level
+	KindReset     Kind = "reset"     // a constrained window reset
+	KindReminder  Kind = "reminder"  // a window resets soon
+	KindExpiry    Kind = "expiry"    // the access token expires soon
+)
+
+// Alert describes a window crossing into a higher level, or resetting, or
+// the access token nearing expiry
+type Alert struct {
+	Kind        Kind       `json:"kind"`
+	Window      string     `json:"window"`
+	Level       Level      `json:"level"`
+	Utilization float64    `json:"utilization"`
+	ResetsAt    *time.Time `json:"resets_at,omitempty"`
+	// ExpiresAt is when the access token expires, for KindExpiry
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Title returns a short notification title
+func (a Alert) Title() string {
+	switch a.Kind {
+	case KindReset:
+		return "Claude usage reset"
+	case KindReminder:
+		return "Claude usage resets soon"
+	case KindExpiry:
+		return "Claude credentials expiring"
+	}
+	return fmt.Sprintf("Claude usage %s", a.Level)
+}
+
+// Message returns a human-readable notification body
+func (a Alert) Message() string {
+	switch a.Kind {
+	case KindReset:
+		return fmt.Sprintf("%s limit has reset (now %.0f%%)", format.FormatKey(a.Window), a.Utilization)
+	case KindReminder:
+		return fmt.Sprintf("%s limit resets %s (now %.0f%%)", format.FormatKey(a.Window), a.ResetsIn(), a.Utilization)
+	case KindExpiry:
+		if a.ExpiresAt == nil {
+			return "Access token expires soon; refresh your credentials"
+		}
+		if a.Level == LevelCritical {
+			return fmt.Sprintf("Access token expired %s; refresh your credentials", format.Relative(*a.ExpiresAt))
+		}
+		return fmt.Sprintf("Access token expires %s; refresh your credentials", format.Relative(*a.ExpiresAt))
+	}
+
+	msg := fmt.Sprintf("%s limit at %.0f%%", format.FormatKey(a.Window), a.Utilization)
+	if a.ResetsAt != nil {
+		msg += ", resets " + a.ResetTime()
+	}
+	return msg
+}
+
+// ResetTime returns the local reset time, e.g. "Tue 8:00 AM", or "" if unknown
+func (a Alert) ResetTime() string {
+	if a.ResetsAt == nil {
+		return ""
+	}
+	return a.ResetsAt.Local().Format("Mon 3:04 PM")
+}
+
+// ResetsIn returns the time until reset, e.g. "in 2h 14m", or "" if unknown
+func (a Alert) ResetsIn() string {
+	if a.ResetsAt == nil {
+		return ""
+	}
+	return format.Relative(*a.ResetsAt)
+}
+
+// Notifier delivers alerts to a destination
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// state is the persisted last-notified level and reset time per window,
+// when each window last alerted at each level (or reset), and the snooze
+// and acknowledgements set by the user
+type state struct {
+	Levels   map[string]Level     `json:"levels"`
+	ResetsAt map[string]time.Time `json:"resets_at"`
+	Notified map[string]time.Time `json:"notified,omitempty"`
+	// SnoozedUntil holds back every alert until then
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	// Acked maps acknowledged windows to their reset time when acknowledged
+	// (zero if unknown); they stay quiet until they reset
+	Acked map[string]time.Time `json:"acked,omitempty"`
+	// TokenExpiry is the expiry of the access token last alerted about
+	TokenExpiry *time.Time `json:"token_expiry,omitempty"`
+}
+
+// Tracker remembers the last level notified for each window so that an alert
+// fires once per crossing rather than on every poll. When utilization drops
+// (e.g., after a reset), the window re-arms and will alert again next time.
+// A window that was at warning or above and whose reset time advances
+// produces a single KindReset alert. Each window follows its Policy, which
+// can also hold back repeats within a cooldown, or remind of a reset ahead
+// of time.
+type Tracker struct {
+	path     string
+	policies Policies
+	state    state
+	// loaded is the state as last read or written, so Save can tell this
+	// tracker's changes from those other processes made meanwhile
+	loaded state
+	now    func() time.Time
+}
+
+// resetMargin is how far a window's reset time must advance to count as a
+// reset, since reset times jitter between polls
+const resetMargin = time.Minute
+
+// StatePath returns the default tracker state path for a profile
+func StatePath(profile string) string {
+	name := "alerts.json"
+	if profile != "" {
+		name = "alerts-" + profile + ".json"
+	}
+	return cache.StatePath(name)
+}
+
+// NewTracker loads tracker state from path. Missing or unreadable state
+// starts fresh rather than failing.
+func NewTracker(path string, policies Policies) *Tracker {
+	t := &Tracker{
+		path:     path,
+		policies: policies,
+		state:    readState(path),
+		now:      time.Now,
+	}
+	t.loaded = t.state.clone()
+	return t
+}
+
+// readState reads state from path, starting fresh if it's missing or
+// unreadable
+func readState(path string) state {
+	s := state{Levels: make(map[string]Level), ResetsAt: make(map[string]time.Time), Notified: make(map[string]time.Time)}
+	if data, err := os.ReadFile(path); err == nil {
+		var saved state
+		if json.Unmarshal(data, &saved) == nil {
+			if saved.Levels != nil {
+				s.Levels = saved.Levels
+			}
+			if saved.ResetsAt != nil {
+				s.ResetsAt = saved.ResetsAt
+			}
+			if saved.Notified != nil {
+				s.Notified = saved.Notified
+			}
+			s.SnoozedUntil = saved.SnoozedUntil
+			s.Acked = saved.Acked
+			s.TokenExpiry = saved.TokenExpiry
+		}
+	}
+	return s
+}
+
+// clone returns a copy of s that shares no maps with it
+func (s state) clone() state {
+	c := s
+	c.Levels = maps.Clone(s.Levels)
+	c.ResetsAt = maps.Clone(s.ResetsAt)
+	c.Notified = maps.Clone(s.Notified)
+	c.Acked = maps.Clone(s.Acked)
+	return c
+}
+
+// merge applies the changes from base to ours onto theirs, the state on
+// disk, so changes another process saved meanwhile (an 'alerts ack' during
+// a poll, say) survive unless this tracker changed the same thing
+func merge(base, ours, theirs state) state {
+	merged := state{
+		Levels:       mergeMap(base.Levels, ours.Levels, theirs.Levels),
+		ResetsAt:     mergeMap(base.ResetsAt, ours.ResetsAt, theirs.ResetsAt),
+		Notified:     mergeMap(base.Notified, ours.Notified, theirs.Notified),
+		SnoozedUntil: mergeTime(base.SnoozedUntil, ours.SnoozedUntil, theirs.SnoozedUntil),
+		Acked:        mergeMap(base.Acked, ours.Acked, theirs.Acked),
+		TokenExpiry:  mergeTime(base.TokenExpiry, ours.TokenExpiry, theirs.TokenExpiry),
+	}
+	if len(merged.Acked) == 0 {
+		merged.Acked = nil
+	}
+	return merged
+}
+
+// mergeMap applies the keys added, changed, or removed from base to ours
+// onto theirs
+func mergeMap[V comparable](base, ours, theirs map[string]V) map[string]V {
+	merged := maps.Clone(theirs)
+	if merged == nil {
+		merged = make(map[string]V)
+	}
+	for k, v := range ours {
+		if old, ok := base[k]; !ok || old != v {
+			merged[k] = v
+		}
+	}
+	for k := range base {
+		if _, ok := ours[k]; !ok {
+			delete(merged, k)
+		}
+	}
+	return merged
+}
+
+// mergeTime returns ours if it changed from base, otherwise theirs
+func mergeTime(base, ours, theirs *time.Time) *time.Time {
+	if (base == nil && ours == nil) || (base != nil && ours != nil && base.Equal(*ours)) {
+		return theirs
+	}
+	return ours
+}
+
+// Check compares usage against each window's policy and returns alerts for
+// every window whose level rose, or that reset, since the last check, and
+// reminders of resets coming up. State is updated in memory; call Save to
+// persist it.
+func (t *Tracker) Check(usage *models.Usage) []Alert {
+	now := t.now()
+	var alerts []Alert
+	for _, w := range usage.Windows() {
+		policy := t.policies.For(w.Name)
+		level := policy.LevelFor(w.Utilization)
+		prevLevel := t.state.Levels[w.Name]
+		prevReset, hadReset := t.state.ResetsAt[w.Name]
+		reset := hadReset && w.ResetsAt != nil && w.ResetsAt.Sub(prevReset) > resetMargin
+
+		alert := Alert{
+			Window:      w.Name,
+			Level:       level,
+			Utilization: w.Utilization,
+			ResetsAt:    w.ResetsAt,
+		}
+
+		switch {
+		case level > prevLevel:
+			alert.Kind = KindThreshold
+		case reset && policy.OnReset == ResetAlways,
+			reset && policy.OnReset != ResetNever && prevLevel > LevelOK && level < prevLevel:
+			alert.Kind = KindReset
+		}
+		if ackedReset, ok := t.state.Acked[w.Name]; ok {
+			// Without a known reset time, dropping below warning stands in
+			// for the reset. A zero ackedReset is always more than the
+			// margin behind.
+			if (ackedReset.IsZero() && level == LevelOK) || (w.ResetsAt != nil && w.ResetsAt.Sub(ackedReset) > resetMargin) {
+				delete(t.state.Acked, w.Name)
+			} else if alert.Kind == KindThreshold {
+				alert.Kind = ""
+			}
+		}
+		if alert.Kind != "" && !t.Snoozed(now) && t.cooledDown(alert, policy.Cooldown, now) {
+			alerts = append(alerts, alert)
+		}
+		if t.remindDue(w, policy.RemindBefore, now) {
+			reminder := alert
+			reminder.Kind = KindReminder
+			alerts = append(alerts, reminder)
+		}
+
+		t.state.Levels[w.Name] = level
+		if w.ResetsAt != nil {
+			t.state.ResetsAt[w.Name] = *w.ResetsAt
+		}
+	}
+	return alerts
+}
+
+// CheckExpiry returns a KindExpiry alert when an access token expiring at
+// expiresAt is within before of expiring, or already expired: a warning,
+// or critical once expired. Each token alerts once, so a refreshed token
+// re-arms it. Zero before disables the check.
+func (t *Tracker) CheckExpiry(expiresAt time.Time, before time.Duration) []Alert {
+	now := t.now()
+	if before <= 0 || expiresAt.IsZero() || expiresAt.Sub(now) > before || t.Snoozed(now) {
+		return nil
+	}
+	if t.state.TokenExpiry != nil && t.state.TokenExpiry.Equal(expiresAt) {
+		return nil
+	}
+	t.state.TokenExpiry = &expiresAt
+
+	level := LevelWarning
+	if !now.Before(expiresAt) {
+		level = LevelCritical
+	}
+	return []Alert{{Kind: KindExpiry, Level: level, ExpiresAt: &expiresAt}}
+}
+
+// Snooze holds back every alert until the given time. Levels are still
+// tracked, so a crossing during the snooze doesn't alert afterwards.
+func (t *Tracker) Snooze(until time.Time) {
+	t.state.SnoozedUntil = &until
+}
+
+// Snoozed reports whether alerts are snoozed at now
+func (t *Tracker) Snoozed(now time.Time) bool {
+	return t.state.SnoozedUntil != nil && now.Before(*t.state.SnoozedUntil)
+}
+
+// SnoozedUntil returns when the snooze ends, or the zero time if none is
+// set
+func (t *Tracker) SnoozedUntil() time.Time {
+	if t.state.SnoozedUntil == nil {
+		return time.Time{}
+	}
+	return *t.state.SnoozedUntil
+}
+
+// Ack silences threshold alerts for windows until they reset (or, if their
+// reset time isn't known, drop back below warning). With no windows, every
+// window last seen at warning or above is acknowledged. Returns the windows
+// acknowledged, sorted.
+func (t *Tracker) Ack(windows ...string) []string {
+	if len(windows) == 0 {
+		for name, level := range t.state.Levels {
+			if level > LevelOK {
+				windows = append(windows, name)
+			}
+		}
+	}
+	if t.state.Acked == nil && len(windows) > 0 {
+		t.state.Acked = make(map[string]time.Time)
+	}
+	for _, name := range windows {
+		t.state.Acked[name] = t.state.ResetsAt[name]
+	}
+	sort.Strings(windows)
+	return windows
+}
+
+// Acked returns the acknowledged windows, sorted
+func (t *Tracker) Acked() []string {
+	names := make([]string, 0, len(t.state.Acked))
+	for name := range t.state.Acked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resume clears the snooze and every acknowledgement
+func (t *Tracker) Resume() {
+	t.state.SnoozedUntil = nil
+	t.state.Acked = nil
+}
+
+// remindDue reports whether a reminder of w's reset is due, recording it
+// if so. A window gets one reminder per approach to its reset, even if the
+// reset time shifts slightly between polls.
+func (t *Tracker) remindDue(w models.NamedWindow, before time.Duration, now time.Time) bool {
+	if before <= 0 || w.ResetsAt == nil || t.Snoozed(now) {
+		return false
+	}
+	if until := w.ResetsAt.Sub(now); until <= 0 || until > before {
+		return false
+	}
+	key := w.Name + "/" + string(KindReminder)
+	if last, ok := t.state.Notified[key]; ok && now.Sub(last) < before {
+		return false
+	}
+	t.state.Notified[key] = now
+	return true
+}
+
+// cooledDown reports whether the cooldown since the window's last alert of
+// the same level (or reset) has passed, and if so notes this alert
+func (t *Tracker) cooledDown(a Alert, cooldown time.Duration, now time.Time) bool {
+	key := a.Window + "/" + a.Level.String()
+	if a.Kind == KindReset {
+		key = a.Window + "/" + string(KindReset)
+	}
+	if last, ok := t.state.Notified[key]; ok && cooldown > 0 && now.Sub(last) < cooldown {
+		return false
+	}
+	t.state.Notified[key] = now
+	return true
+}
+
+// Save persists tracker state. It merges this tracker's changes into the
+// state on disk under a lock, so a poll and an 'alerts snooze' or 'alerts
+// ack' saving around the same time don't lose each other's changes.
+func (t *Tracker) Save() error {
+	if err := cache.EnsureDir(filepath.Dir(t.path)); err != nil {
+		return fmt.Errorf("failed to create alert state directory: %w", err)
+	}
+	unlock, err := cache.Lock(t.path, true)
+	if err != nil {
+		return fmt.Errorf("failed to lock alert state: %w", err)
+	}
+	defer unlock()
+
+	merged := merge(t.loaded, t.state, readState(t.path))
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	if err := cache.WriteAtomic(t.path, data, cache.FileMode); err != nil {
+		return fmt.Errorf("failed to write alert state: %w", err)
+	}
+	t.state = merged
+	t.loaded = merged.clone()
+	return nil
+}
+
+// Dispatch sends each alert to every notifier, returning the first error
+// encountered after attempting all deliveries
+func Dispatch(ctx context.Context, notifiers []Notifier, alerts []Alert) error {
+	var firstErr error
+	for _, alert := range alerts {
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, alert); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,414 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func usageOf(t *testing.T, raw string) *models.Usage {
+	t.Helper()
+	var u models.Usage
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return &u
+}
+
+func TestLevelFor(t *testing.T) {
+	th := DefaultThresholds()
+	tests := []struct {
+		value    float64
+		expected Level
+	}{
+		{0, LevelOK},
+		{79.9, LevelOK},
+		{80, LevelWarning},
+		{94.9, LevelWarning},
+		{95, LevelCritical},
+		{100, LevelCritical},
+	}
+
+	for _, tt := range tests {
+		if got := th.LevelFor(tt.value); got != tt.expected {
+			t.Errorf("LevelFor(%v) = %s, want %s", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestTrackerDeduplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	tr := NewTracker(path, DefaultPolicies())
+
+	alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}, "seven_day": {"utilization": 10}}`))
+	if len(alerts) != 1 || alerts[0].Window != "five_hour" || alerts[0].Level != LevelWarning {
+		t.Fatalf("first Check = %+v, want one five_hour warning", alerts)
+	}
+
+	// Same level again: no alert
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 88}}`)); len(alerts) != 0 {
+		t.Errorf("repeat Check = %+v, want none", alerts)
+	}
+
+	// Escalation: alert
+	alerts = tr.Check(usageOf(t, `{"five_hour": {"utilization": 96}}`))
+	if len(alerts) != 1 || alerts[0].Level != LevelCritical {
+		t.Errorf("escalation Check = %+v, want one critical", alerts)
+	}
+}
+
+func TestTrackerRearmsAfterDrop(t *testing.T) {
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), DefaultPolicies())
+
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 90}}`))
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 5}}`))
+
+	alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 90}}`))
+	if len(alerts) != 1 {
+		t.Errorf("Check after reset = %+v, want one alert", alerts)
+	}
+}
+
+func TestTrackerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+
+	tr := NewTracker(path, DefaultPolicies())
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 90}}`))
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded := NewTracker(path, DefaultPolicies())
+	if alerts := reloaded.Check(usageOf(t, `{"five_hour": {"utilization": 90}}`)); len(alerts) != 0 {
+		t.Errorf("Check after reload = %+v, want none", alerts)
+	}
+}
+
+type recordingNotifier struct {
+	alerts []Alert
+	err    error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return r.err
+}
+
+func TestDispatch(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("boom")}
+	ok := &recordingNotifier{}
+	alerts := []Alert{{Window: "five_hour", Level: LevelWarning, Utilization: 81}}
+
+	err := Dispatch(context.Background(), []Notifier{failing, ok}, alerts)
+	if err == nil {
+		t.Error("Dispatch should return the delivery error")
+	}
+	if len(ok.alerts) != 1 {
+		t.Error("Dispatch should still deliver to remaining notifiers after a failure")
+	}
+}
+
+func TestAlertMessage(t *testing.T) {
+	a := Alert{Window: "five_hour", Level: LevelCritical, Utilization: 96.4}
+	if a.Title() != "Claude usage critical" {
+		t.Errorf("Title() = %q", a.Title())
+	}
+	if a.Message() != "Five Hour limit at 96%" {
+		t.Errorf("Message() = %q", a.Message())
+	}
+}
+
+func TestTrackerResetAlert(t *testing.T) {
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), DefaultPolicies())
+
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 90, "resets_at": "2025-01-15T14:00:00Z"}}`))
+	alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 3, "resets_at": "2025-01-15T19:00:00Z"}}`))
+
+	if len(alerts) != 1 || alerts[0].Kind != KindReset {
+		t.Fatalf("Check after reset = %+v, want one reset alert", alerts)
+	}
+
+	// An unconstrained window resetting is not worth an alert
+	alerts = tr.Check(usageOf(t, `{"five_hour": {"utilization": 1, "resets_at": "2025-01-16T00:00:00Z"}}`))
+	if len(alerts) != 0 {
+		t.Errorf("Check after unconstrained reset = %+v, want none", alerts)
+	}
+}
+
+func TestTrackerWindowPolicies(t *testing.T) {
+	policies := DefaultPolicies()
+	policies.Windows = map[string]Policy{
+		"seven_day_opus": {Thresholds: Thresholds{Warning: 60, Critical: 85}},
+	}
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), policies)
+
+	alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 70}, "seven_day_opus": {"utilization": 70}}`))
+	if len(alerts) != 1 || alerts[0].Window != "seven_day_opus" || alerts[0].Level != LevelWarning {
+		t.Errorf("Check = %+v, want only an opus warning", alerts)
+	}
+}
+
+func TestTrackerCooldown(t *testing.T) {
+	policies := DefaultPolicies()
+	policies.Default.Cooldown = time.Hour
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), policies)
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now }
+
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}}`)); len(alerts) != 1 {
+		t.Fatalf("first crossing = %+v, want one alert", alerts)
+	}
+
+	// Hovering at the threshold re-arms, but repeats wait out the cooldown
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 79}}`))
+	now = now.Add(10 * time.Minute)
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 81}}`)); len(alerts) != 0 {
+		t.Errorf("repeat within cooldown = %+v, want none", alerts)
+	}
+
+	// Escalation isn't held back by a lower level's cooldown
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 96}}`)); len(alerts) != 1 || alerts[0].Level != LevelCritical {
+		t.Errorf("escalation = %+v, want one critical", alerts)
+	}
+
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 10}}`))
+	now = now.Add(time.Hour)
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}}`)); len(alerts) != 1 {
+		t.Errorf("crossing after cooldown = %+v, want one alert", alerts)
+	}
+}
+
+func TestTrackerResetModes(t *testing.T) {
+	before := `{"five_hour": {"utilization": 20, "resets_at": "2025-01-15T14:00:00Z"}, "seven_day": {"utilization": 90, "resets_at": "2025-01-20T00:00:00Z"}}`
+	after := `{"five_hour": {"utilization": 0, "resets_at": "2025-01-15T19:00:00Z"}, "seven_day": {"utilization": 1, "resets_at": "2025-01-27T00:00:00Z"}}`
+
+	policies := DefaultPolicies()
+	policies.Windows = map[string]Policy{
+		"five_hour": {Thresholds: DefaultThresholds(), OnReset: ResetAlways},
+		"seven_day": {Thresholds: DefaultThresholds(), OnReset: ResetNever},
+	}
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), policies)
+
+	tr.Check(usageOf(t, before))
+	alerts := tr.Check(usageOf(t, after))
+	if len(alerts) != 1 || alerts[0].Window != "five_hour" || alerts[0].Kind != KindReset {
+		t.Errorf("Check = %+v, want only a five_hour reset", alerts)
+	}
+}
+
+func TestTrackerResetJitter(t *testing.T) {
+	policies := Policies{Default: Policy{Thresholds: DefaultThresholds(), OnReset: ResetAlways}}
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), policies)
+
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 20, "resets_at": "2025-01-15T14:00:00Z"}}`))
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 20, "resets_at": "2025-01-15T14:00:04Z"}}`)); len(alerts) != 0 {
+		t.Errorf("Check after jitter = %+v, want none", alerts)
+	}
+}
+
+func TestParseResetMode(t *testing.T) {
+	if m, err := ParseResetMode(""); err != nil || m != ResetConstrained {
+		t.Errorf(`ParseResetMode("") = %q, %v`, m, err)
+	}
+	if _, err := ParseResetMode("sometimes"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestTrackerSnooze(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tr := NewTracker(path, DefaultPolicies())
+	tr.Snooze(now.Add(time.Hour))
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The daemon loads the snooze from the state file
+	tr = NewTracker(path, DefaultPolicies())
+	tr.now = func() time.Time { return now }
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}}`)); len(alerts) != 0 {
+		t.Errorf("Check while snoozed = %+v, want none", alerts)
+	}
+
+	// A crossing during the snooze doesn't alert afterwards, a new one does
+	now = now.Add(2 * time.Hour)
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}}`)); len(alerts) != 0 {
+		t.Errorf("Check after snooze = %+v, want none", alerts)
+	}
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 96}}`)); len(alerts) != 1 {
+		t.Errorf("escalation after snooze = %+v, want one alert", alerts)
+	}
+}
+
+func TestTrackerAck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	tr := NewTracker(path, DefaultPolicies())
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 85, "resets_at": "2025-01-15T14:00:00Z"}, "seven_day": {"utilization": 10}}`))
+
+	if acked := tr.Ack(); len(acked) != 1 || acked[0] != "five_hour" {
+		t.Fatalf("Ack() = %v, want [five_hour]", acked)
+	}
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	tr = NewTracker(path, DefaultPolicies())
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 96, "resets_at": "2025-01-15T14:00:00Z"}}`)); len(alerts) != 0 {
+		t.Errorf("escalation while acknowledged = %+v, want none", alerts)
+	}
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 79, "resets_at": "2025-01-15T14:00:00Z"}}`))
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85, "resets_at": "2025-01-15T14:00:00Z"}}`)); len(alerts) != 0 {
+		t.Errorf("hovering while acknowledged = %+v, want none", alerts)
+	}
+
+	// The reset still alerts, and clears the acknowledgement
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 96, "resets_at": "2025-01-15T14:00:00Z"}}`))
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 2, "resets_at": "2025-01-15T19:00:00Z"}}`)); len(alerts) != 1 || alerts[0].Kind != KindReset {
+		t.Errorf("reset = %+v, want one reset alert", alerts)
+	}
+	if acked := tr.Acked(); len(acked) != 0 {
+		t.Errorf("Acked() after reset = %v, want none", acked)
+	}
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85, "resets_at": "2025-01-15T19:00:00Z"}}`)); len(alerts) != 1 {
+		t.Errorf("crossing after reset = %+v, want one alert", alerts)
+	}
+}
+
+func TestTrackerAckSurvivesResetJitter(t *testing.T) {
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), DefaultPolicies())
+	tr.Check(usageOf(t, `{"five_hour": {"utilization": 85, "resets_at": "2025-01-15T14:00:00Z"}}`))
+	tr.Ack("five_hour")
+
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 96, "resets_at": "2025-01-15T14:00:01.5Z"}}`)); len(alerts) != 0 {
+		t.Errorf("escalation after jitter = %+v, want none", alerts)
+	}
+	if acked := tr.Acked(); len(acked) != 1 {
+		t.Errorf("Acked() after jitter = %v, want [five_hour]", acked)
+	}
+}
+
+func TestTrackerSaveMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	seed := NewTracker(path, DefaultPolicies())
+	seed.Check(usageOf(t, `{"five_hour": {"utilization": 85}, "seven_day": {"utilization": 90}}`))
+	if err := seed.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A poll loads state, then 'alerts ack' and 'alerts snooze' save
+	// before the poll does
+	poll := NewTracker(path, DefaultPolicies())
+	poll.Check(usageOf(t, `{"five_hour": {"utilization": 96}, "seven_day": {"utilization": 90}}`))
+
+	cmd := NewTracker(path, DefaultPolicies())
+	cmd.Ack("seven_day")
+	until := time.Now().Add(time.Hour)
+	cmd.Snooze(until)
+	if err := cmd.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := poll.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	tr := NewTracker(path, DefaultPolicies())
+	if acked := tr.Acked(); len(acked) != 1 || acked[0] != "seven_day" {
+		t.Errorf("Acked() = %v, want [seven_day]", acked)
+	}
+	if !tr.SnoozedUntil().Equal(until) {
+		t.Errorf("SnoozedUntil() = %v, want %v", tr.SnoozedUntil(), until)
+	}
+	if level := tr.state.Levels["five_hour"]; level != LevelCritical {
+		t.Errorf("five_hour level = %v, want the poll's critical", level)
+	}
+}
+
+func TestTrackerResume(t *testing.T) {
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), DefaultPolicies())
+	tr.Snooze(time.Now().Add(time.Hour))
+	tr.Ack("five_hour")
+	tr.Resume()
+
+	if alerts := tr.Check(usageOf(t, `{"five_hour": {"utilization": 85}}`)); len(alerts) != 1 {
+		t.Errorf("Check after Resume = %+v, want one alert", alerts)
+	}
+}
+
+func TestTrackerReminder(t *testing.T) {
+	policies := DefaultPolicies()
+	policies.Windows = map[string]Policy{
+		"seven_day": {Thresholds: DefaultThresholds(), RemindBefore: time.Hour},
+	}
+	tr := NewTracker(filepath.Join(t.TempDir(), "alerts.json"), policies)
+	now := time.Date(2025, 1, 20, 22, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now }
+	usage := `{"five_hour": {"utilization": 10, "resets_at": "2025-01-20T22:30:00Z"}, "seven_day": {"utilization": 60, "resets_at": "2025-01-21T00:00:00Z"}}`
+
+	if alerts := tr.Check(usageOf(t, usage)); len(alerts) != 0 {
+		t.Errorf("Check two hours out = %+v, want none", alerts)
+	}
+
+	now = now.Add(70 * time.Minute)
+	alerts := tr.Check(usageOf(t, usage))
+	if len(alerts) != 1 || alerts[0].Window != "seven_day" || alerts[0].Kind != KindReminder {
+		t.Fatalf("Check 50m out = %+v, want one seven_day reminder", alerts)
+	}
+
+	// Once per approach, even if the reset time drifts
+	now = now.Add(10 * time.Minute)
+	drifted := `{"seven_day": {"utilization": 61, "resets_at": "2025-01-21T00:00:30Z"}}`
+	if alerts := tr.Check(usageOf(t, drifted)); len(alerts) != 0 {
+		t.Errorf("Check 40m out = %+v, want none", alerts)
+	}
+}
+
+func TestTrackerExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	tr := NewTracker(path, DefaultPolicies())
+	now := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now }
+	expires := now.Add(3 * time.Hour)
+
+	if alerts := tr.CheckExpiry(expires, 2*time.Hour); len(alerts) != 0 {
+		t.Errorf("CheckExpiry 3h out = %+v, want none", alerts)
+	}
+	if alerts := tr.CheckExpiry(expires, 0); len(alerts) != 0 {
+		t.Errorf("CheckExpiry disabled = %+v, want none", alerts)
+	}
+
+	now = now.Add(90 * time.Minute)
+	alerts := tr.CheckExpiry(expires, 2*time.Hour)
+	if len(alerts) != 1 || alerts[0].Kind != KindExpiry || alerts[0].Level != LevelWarning {
+		t.Fatalf("CheckExpiry 90m out = %+v, want one expiry warning", alerts)
+	}
+	// The hint suits every credential source, not just Claude Code
+	if msg := alerts[0].Message(); strings.Contains(msg, "claude") {
+		t.Errorf("Message() = %q, want a hint that doesn't assume Claude Code", msg)
+	}
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Once per token, across restarts
+	tr = NewTracker(path, DefaultPolicies())
+	now = now.Add(2 * time.Hour)
+	tr.now = func() time.Time { return now }
+	if alerts := tr.CheckExpiry(expires, 2*time.Hour); len(alerts) != 0 {
+		t.Errorf("CheckExpiry of an alerted token = %+v, want none", alerts)
+	}
+
+	// A token that's already expired when first seen is critical
+	refreshed := now.Add(-time.Minute)
+	alerts = tr.CheckExpiry(refreshed, 2*time.Hour)
+	if len(alerts) != 1 || alerts[0].Level != LevelCritical {
+		t.Errorf("CheckExpiry of an expired token = %+v, want one critical alert", alerts)
+	}
+}
@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Monitoring plugin exit codes (Nagios, Icinga, Sensu, Zabbix agents).
+// OK, WARNING, and CRITICAL share their values with the Levels.
+const (
+	PluginOK       = 0
+	PluginWarning  = 1
+	PluginCritical = 2
+	PluginUnknown  = 3
+)
+
+// pluginName prefixes plugin output, as check plugins conventionally do
+const pluginName = "CLAUDE LIMITS"
+
+// CheckResult is the outcome of a monitoring check
+type CheckResult struct {
+	// Level is the most severe level across windows
+	Level Level
+	// Output is the plugin output line, with performance data after the "|"
+	Output string
+}
+
+// Code returns the plugin exit code for the result
+func (r CheckResult) Code() int {
+	return int(r.Level)
+}
+
+// Check evaluates every window against the thresholds and renders the
+// standard check plugin output, e.g.
+//
+//	CLAUDE LIMITS WARNING - 5h 85%, wk 23% | five_hour=85%;80;95;0;100 seven_day=23%;80;95;0;100
+//
+// Windows at the worst level are listed first in the summary.
+func Check(usage *models.Usage, t Thresholds) CheckResult {
+	windows := usage.Windows()
+	result := CheckResult{Level: LevelOK}
+	for _, w := range windows {
+		if level := t.LevelFor(w.Utilization); level > result.Level {
+			result.Level = level
+		}
+	}
+
+	var summary, perfdata []string
+	for level := result.Level; level >= LevelOK; level-- {
+		for _, w := range windows {
+			if t.LevelFor(w.Utilization) == level {
+				summary = append(summary, fmt.Sprintf("%s %.0f%%", format.WindowLabel(w.Name), w.Utilization))
+			}
+		}
+	}
+	for _, w := range windows {
+		perfdata = append(perfdata, fmt.Sprintf("%s=%s%%;%s;%s;0;100", w.Name,
+			perfValue(w.Utilization), perfValue(t.Warning), perfValue(t.Critical)))
+	}
+
+	if len(windows) == 0 {
+		result.Output = fmt.Sprintf("%s %s - no usage windows reported", pluginName, PluginState(result.Code()))
+		return result
+	}
+	result.Output = fmt.Sprintf("%s %s - %s | %s", pluginName, PluginState(result.Code()),
+		strings.Join(summary, ", "), strings.Join(perfdata, " "))
+	return result
+}
+
+// CheckUnknown renders the plugin output for a check that couldn't run
+func CheckUnknown(err error) string {
+	return fmt.Sprintf("%s %s - %v", pluginName, PluginState(PluginUnknown), err)
+}
+
+// PluginState returns the state name for a plugin exit code
+func PluginState(code int) string {
+	switch code {
+	case PluginOK:
+		return "OK"
+	case PluginWarning:
+		return "WARNING"
+	case PluginCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// perfValue formats a perfdata number without trailing zeros
+func perfValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		code   int
+		output string
+	}{
+		{
+			"ok",
+			`{"five_hour": {"utilization": 45}, "seven_day": {"utilization": 23.5}}`,
+			PluginOK,
+			"CLAUDE LIMITS OK - 5h 45%, wk 24% | five_hour=45%;80;95;0;100 seven_day=23.5%;80;95;0;100",
+		},
+		{
+			"warning listed first",
+			`{"five_hour": {"utilization": 10}, "seven_day": {"utilization": 85}}`,
+			PluginWarning,
+			"CLAUDE LIMITS WARNING - wk 85%, 5h 10% | five_hour=10%;80;95;0;100 seven_day=85%;80;95;0;100",
+		},
+		{
+			"critical",
+			`{"five_hour": {"utilization": 100}, "seven_day": {"utilization": 85}}`,
+			PluginCritical,
+			"CLAUDE LIMITS CRITICAL - 5h 100%, wk 85% | five_hour=100%;80;95;0;100 seven_day=85%;80;95;0;100",
+		},
+		{
+			"no windows",
+			`{}`,
+			PluginOK,
+			"CLAUDE LIMITS OK - no usage windows reported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(usageOf(t, tt.raw), DefaultThresholds())
+			if result.Code() != tt.code {
+				t.Errorf("Code() = %d, want %d", result.Code(), tt.code)
+			}
+			if result.Output != tt.output {
+				t.Errorf("Output =\n%s\nwant\n%s", result.Output, tt.output)
+			}
+		})
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	got := CheckUnknown(errors.New("request failed"))
+	if got != "CLAUDE LIMITS UNKNOWN - request failed" {
+		t.Errorf("CheckUnknown = %q", got)
+	}
+	if PluginState(PluginUnknown) != "UNKNOWN" || PluginState(int(LevelCritical)) != "CRITICAL" {
+		t.Error("plugin states should match the levels")
+	}
+}
@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// appleScript displays a notification using text passed via environment
+// variables, which avoids quoting untrusted text into the script
+const appleScript = `display notification (system attribute "CLAUDE_LIMITS_BODY") with title (system attribute "CLAUDE_LIMITS_TITLE")`
+
+// toastScript shows a Windows toast notification using the built-in WinRT
+// APIs, reading text from environment variables for the same reason
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:CLAUDE_LIMITS_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:CLAUDE_LIMITS_BODY)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('claude-limits').Show($toast)
+`
+
+// Desktop delivers alerts as native desktop notifications:
+// notify-send on Linux, osascript on macOS, and toast notifications on Windows
+type Desktop struct{}
+
+// Notify shows a desktop notification for the alert
+func (Desktop) Notify(ctx context.Context, alert Alert) error {
+	cmd, err := desktopCommand(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+func desktopCommand(ctx context.Context, alert Alert) (*exec.Cmd, error) {
+	title, body := alert.Title(), alert.Message()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		urgency := "normal"
+		if alert.Level == LevelCritical {
+			urgency = "critical"
+		}
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=claude-limits", "--urgency="+urgency, title, body)
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", appleScript)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	default:
+		return nil, fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	cmd.Env = append(os.Environ(), "CLAUDE_LIMITS_TITLE="+title, "CLAUDE_LIMITS_BODY="+body)
+	return cmd, nil
+}
@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Webhook payload types
+const (
+	WebhookGeneric = "generic"
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+)
+
+// webhookTimeout bounds each delivery so a slow endpoint can't stall polling
+const webhookTimeout = 10 * time.Second
+
+// Webhook delivers alerts by POSTing JSON to a URL.
+//
+// Slack and Discord webhooks receive {"text": ...} and {"content": ...}
+// respectively. Generic webhooks receive the alert fields plus "message".
+type Webhook struct {
+	url        string
+	kind       string
+	template   *template.Template
+	httpClient *http.Client
+}
+
+// NewWebhook creates a webhook notifier. kind is one of "generic" (default),
+// "slack", or "discord". tmpl is an optional text/template for the message,
+// evaluated against the Alert (e.g., "{{.Window}} at {{.Utilization}}%",
+// "resets {{.ResetTime}} ({{.ResetsIn}})").
+func NewWebhook(url, kind, tmpl string) (*Webhook, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+
+	switch strings.ToLower(kind) {
+	case "", WebhookGeneric:
+		kind = WebhookGeneric
+	case WebhookSlack, WebhookDiscord:
+		kind = strings.ToLower(kind)
+	default:
+		return nil, fmt.Errorf("unknown webhook type %q (use generic, slack, or discord)", kind)
+	}
+
+	w := &Webhook{
+		url:        url,
+		kind:       kind,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+	}
+
+	if tmpl != "" {
+		t, err := template.New("webhook").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		w.template = t
+	}
+
+	return w, nil
+}
+
+// Notify POSTs the alert to the webhook URL
+func (w *Webhook) Notify(ctx context.Context, alert Alert) error {
+	message, err := w.message(alert)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(w.payload(alert, message))
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		// The URL is the webhook's secret, so leave it out
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *Webhook) message(alert Alert) (string, error) {
+	if w.template == nil {
+		return alert.Message(), nil
+	}
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (w *Webhook) payload(alert Alert, message string) interface{} {
+	switch w.kind {
+	case WebhookSlack:
+		return map[string]string{"text": message}
+	case WebhookDiscord:
+		return map[string]string{"content": message}
+	default:
+		return struct {
+			Alert
+			Message string `json:"message"`
+		}{alert, message}
+	}
+}
@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureServer(t *testing.T, status int, got *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, got); err != nil {
+			t.Errorf("payload is not JSON: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	alert := Alert{Kind: KindThreshold, Window: "five_hour", Level: LevelWarning, Utilization: 82}
+
+	tests := []struct {
+		kind  string
+		field string
+	}{
+		{WebhookSlack, "text"},
+		{WebhookDiscord, "content"},
+		{WebhookGeneric, "message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			var got map[string]interface{}
+			server := captureServer(t, http.StatusOK, &got)
+
+			w, err := NewWebhook(server.URL, tt.kind, "")
+			if err != nil {
+				t.Fatalf("NewWebhook failed: %v", err)
+			}
+			if err := w.Notify(context.Background(), alert); err != nil {
+				t.Fatalf("Notify failed: %v", err)
+			}
+			if got[tt.field] != alert.Message() {
+				t.Errorf("payload[%q] = %v, want %q", tt.field, got[tt.field], alert.Message())
+			}
+		})
+	}
+}
+
+func TestWebhookGenericFields(t *testing.T) {
+	var got map[string]interface{}
+	server := captureServer(t, http.StatusOK, &got)
+
+	w, _ := NewWebhook(server.URL, "", "")
+	alert := Alert{Kind: KindThreshold, Window: "seven_day", Level: LevelCritical, Utilization: 97}
+	if err := w.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if got["window"] != "seven_day" || got["level"] != "critical" || got["kind"] != "threshold" {
+		t.Errorf("generic payload = %v", got)
+	}
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	var got map[string]interface{}
+	server := captureServer(t, http.StatusOK, &got)
+
+	w, err := NewWebhook(server.URL, "slack", "{{.Window}} is {{.Level}} at {{.Utilization}}%")
+	if err != nil {
+		t.Fatalf("NewWebhook failed: %v", err)
+	}
+	_ = w.Notify(context.Background(), Alert{Window: "five_hour", Level: LevelWarning, Utilization: 81})
+
+	if got["text"] != "five_hour is warning at 81%" {
+		t.Errorf("templated text = %v", got["text"])
+	}
+}
+
+func TestWebhookTemplateResetTimes(t *testing.T) {
+	var got map[string]interface{}
+	server := captureServer(t, http.StatusOK, &got)
+
+	w, err := NewWebhook(server.URL, "slack", "resets {{.ResetTime}} ({{.ResetsIn}})")
+	if err != nil {
+		t.Fatalf("NewWebhook failed: %v", err)
+	}
+	resets := time.Now().Add(2*time.Hour + 14*time.Minute + 20*time.Second)
+	_ = w.Notify(context.Background(), Alert{Window: "five_hour", Level: LevelWarning, Utilization: 81, ResetsAt: &resets})
+
+	want := "resets " + resets.Local().Format("Mon 3:04 PM") + " (in 2h 14m)"
+	if got["text"] != want {
+		t.Errorf("templated text = %v, want %q", got["text"], want)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	if _, err := NewWebhook("", "", ""); err == nil {
+		t.Error("NewWebhook should require a URL")
+	}
+	if _, err := NewWebhook("http://x", "teams", ""); err == nil {
+		t.Error("NewWebhook should reject unknown types")
+	}
+	if _, err := NewWebhook("http://x", "", "{{.Broken"); err == nil {
+		t.Error("NewWebhook should reject invalid templates")
+	}
+
+	var got map[string]interface{}
+	server := captureServer(t, http.StatusInternalServerError, &got)
+	w, _ := NewWebhook(server.URL, "", "")
+	if err := w.Notify(context.Background(), Alert{Window: "five_hour"}); err == nil {
+		t.Error("Notify should fail on non-2xx status")
+	}
+
+	// Delivery errors leave out the URL, which holds the webhook's secret
+	w, _ = NewWebhook("http://127.0.0.1:1/services/T000/B000/SECRETTOKEN", WebhookSlack, "")
+	err := w.Notify(context.Background(), Alert{Window: "five_hour"})
+	if err == nil || strings.Contains(err.Error(), "SECRETTOKEN") {
+		t.Errorf("Notify error = %v, want a failure without the URL", err)
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
+)
+
+// Admin API endpoints, which take an admin API key rather than OAuth
+const (
+	usageReportPath      = "/v1/organizations/usage_report/messages"
+	costReportPath       = "/v1/organizations/cost_report"
+	claudeCodeReportPath = "/v1/organizations/usage_report/claude_code"
+
+	// anthropicVersion is the API version sent with API key requests
+	anthropicVersion = "2023-06-01"
+)
+
+// reportPageLimits are the most buckets the Admin API returns per page for
+// each bucket width
+var reportPageLimits = map[string]int{"1m": 1440, "1h": 168, "1d": 31}
+
+// WithAPIKey authenticates with an Anthropic API key (x-api-key) instead of
+// an OAuth token, for the Admin API of API-platform organizations
+func WithAPIKey(key string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// UsageReportQuery selects the buckets of a usage report
+type UsageReportQuery struct {
+	// Start is the start of the first bucket
+	Start time.Time
+	// End is the end of the last bucket; zero is now
+	End time.Time
+	// BucketWidth is "1m", "1h", or "1d"; empty is "1d"
+	BucketWidth string
+	// GroupBy splits each bucket's results, e.g. by "model" or "workspace_id"
+	GroupBy []string
+}
+
+// reportPage is one page of an Admin API report
+type reportPage[T any] struct {
+	Data     []T    `json:"data"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+}
+
+// getReport fetches path with params, then each following page
+func getReport[T any](ctx context.Context, c *Client, path string, params url.Values) ([]T, error) {
+	var data []T
+	for {
+		body, _, err := c.get(ctx, path+"?"+params.Encode(), Validators{})
+		if err != nil {
+			return nil, err
+		}
+		var page reportPage[T]
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("%w: %w", apierrors.ErrResponseParse, err)
+		}
+		data = append(data, page.Data...)
+		if !page.HasMore || page.NextPage == "" {
+			return data, nil
+		}
+		params.Set("page", page.NextPage)
+	}
+}
+
+// reportParams returns the range parameters shared by the reports
+func reportParams(start, end time.Time, groupBy []string) url.Values {
+	params := url.Values{}
+	params.Set("starting_at", start.UTC().Format(time.RFC3339))
+	if !end.IsZero() {
+		params.Set("ending_at", end.UTC().Format(time.RFC3339))
+	}
+	for _, g := range groupBy {
+		params.Add("group_by[]", g)
+	}
+	return params
+}
+
+// GetUsageReport fetches the organization's messages usage report, following
+// pages until every bucket in the range is returned. It needs an admin key;
+// see WithAPIKey.
+func (c *Client) GetUsageReport(ctx context.Context, q UsageReportQuery) (buckets []models.UsageReportBucket, err error) {
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.get_usage_report", telemetry.Attr{Key: "claude_limits.bucket_width", Value: q.BucketWidth})
+	defer func() { span.End(err) }()
+
+	width := q.BucketWidth
+	if width == "" {
+		width = "1d"
+	}
+	limit, ok := reportPageLimits[width]
+	if !ok {
+		return nil, fmt.Errorf("invalid bucket width %q (use 1m, 1h, or 1d)", width)
+	}
+
+	params := reportParams(q.Start, q.End, q.GroupBy)
+	params.Set("bucket_width", width)
+	params.Set("limit", strconv.Itoa(limit))
+	return getReport[models.UsageReportBucket](ctx, c, usageReportPath, params)
+}
+
+// GetCostReport fetches the organization's daily costs from start to end
+// (now if zero), split by groupBy: "workspace_id" and/or "description".
+// It needs an admin key; see WithAPIKey.
+func (c *Client) GetCostReport(ctx context.Context, start, end time.Time, groupBy []string) (buckets []models.CostReportBucket, err error) {
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.get_cost_report")
+	defer func() { span.End(err) }()
+
+	params := reportParams(start, end, groupBy)
+	params.Set("bucket_width", "1d")
+	params.Set("limit", strconv.Itoa(reportPageLimits["1d"]))
+	return getReport[models.CostReportBucket](ctx, c, costReportPath, params)
+}
+
+// GetClaudeCodeReport fetches per-user Claude Code usage for each UTC day
+// from start's through end's. The report covers one day per request, so the
+// days are fetched concurrently. It needs an admin key; see WithAPIKey.
+func (c *Client) GetClaudeCodeReport(ctx context.Context, start, end time.Time) (records []models.ClaudeCodeRecord, err error) {
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.get_claude_code_report")
+	defer func() { span.End(err) }()
+
+	var endpoints []Endpoint
+	for day := start.UTC().Truncate(24 * time.Hour); !day.After(end.UTC()); day = day.AddDate(0, 0, 1) {
+		params := url.Values{}
+		params.Set("starting_at", day.Format(time.DateOnly))
+		params.Set("limit", "1000")
+		endpoints = append(endpoints, Endpoint{Name: day.Format(time.DateOnly), Path: claudeCodeReportPath + "?" + params.Encode()})
+	}
+
+	pages, err := c.FetchAll(ctx, endpoints, DefaultWorkers)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range endpoints {
+		var page reportPage[models.ClaudeCodeRecord]
+		if err := json.Unmarshal(pages[e.Name], &page); err != nil {
+			return nil, fmt.Errorf("%w: %w", apierrors.ErrResponseParse, err)
+		}
+		records = append(records, page.Data...)
+		if page.HasMore && page.NextPage != "" {
+			params := url.Values{}
+			params.Set("starting_at", e.Name)
+			params.Set("limit", "1000")
+			params.Set("page", page.NextPage)
+			rest, err := getReport[models.ClaudeCodeRecord](ctx, c, claudeCodeReportPath, params)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rest...)
+		}
+	}
+	return records, nil
+}
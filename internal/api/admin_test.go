@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+func TestGetUsageReport(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		if r.URL.Path != "/v1/organizations/usage_report/messages" {
+			t.Errorf("Path = %s, want the usage report", r.URL.Path)
+		}
+		if key := r.Header.Get("x-api-key"); key != "sk-ant-admin-test" {
+			t.Errorf("x-api-key = %q, want the admin key", key)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none with an API key", auth)
+		}
+		if v := r.Header.Get("anthropic-version"); v == "" {
+			t.Error("anthropic-version not set")
+		}
+		q := r.URL.Query()
+		if q.Get("bucket_width") != "1h" || q.Get("limit") != "168" {
+			t.Errorf("bucket_width = %q, limit = %q, want 1h and 168", q.Get("bucket_width"), q.Get("limit"))
+		}
+		if got := q["group_by[]"]; len(got) != 1 || got[0] != "model" {
+			t.Errorf("group_by[] = %v, want [model]", got)
+		}
+
+		if q.Get("page") == "" {
+			_, _ = w.Write([]byte(`{"data": [{"starting_at": "2026-01-01T00:00:00Z", "ending_at": "2026-01-01T01:00:00Z",
+				"results": [{"uncached_input_tokens": 10, "output_tokens": 5, "model": "claude-opus-4",
+				"cache_creation": {"ephemeral_5m_input_tokens": 3, "ephemeral_1h_input_tokens": 4}}]}],
+				"has_more": true, "next_page": "page_2"}`))
+			return
+		}
+		if q.Get("page") != "page_2" {
+			t.Errorf("page = %q, want page_2", q.Get("page"))
+		}
+		_, _ = w.Write([]byte(`{"data": [{"starting_at": "2026-01-01T01:00:00Z", "ending_at": "2026-01-01T02:00:00Z",
+			"results": [{"uncached_input_tokens": 1}]}], "has_more": false}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("", WithBaseURL(server.URL), WithAPIKey("sk-ant-admin-test"))
+	buckets, err := c.GetUsageReport(context.Background(), UsageReportQuery{
+		Start:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		BucketWidth: "1h",
+		GroupBy:     []string{"model"},
+	})
+	if err != nil {
+		t.Fatalf("GetUsageReport failed: %v", err)
+	}
+	if pages != 2 || len(buckets) != 2 {
+		t.Fatalf("got %d buckets over %d pages, want 2 over 2", len(buckets), pages)
+	}
+	r := buckets[0].Results[0]
+	if r.UncachedInputTokens != 10 || r.OutputTokens != 5 || r.CacheCreationInputTokens() != 7 {
+		t.Errorf("result = %+v, want 10 input, 5 output, 7 cache creation", r)
+	}
+	if r.Model == nil || *r.Model != "claude-opus-4" {
+		t.Errorf("Model = %v, want claude-opus-4", r.Model)
+	}
+}
+
+func TestGetUsageReportInvalidWidth(t *testing.T) {
+	c := NewClient("", WithAPIKey("key"))
+	if _, err := c.GetUsageReport(context.Background(), UsageReportQuery{BucketWidth: "5m"}); err == nil {
+		t.Error("expected an error for an unknown bucket width")
+	}
+}
+
+func TestAPIErrorMessageObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("", WithBaseURL(server.URL), WithAPIKey("bad"))
+	_, err := c.GetUsageReport(context.Background(), UsageReportQuery{Start: time.Now()})
+	var apiErr *apierrors.APIError
+	if !apierrors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an APIError", err)
+	}
+	if !strings.Contains(apiErr.Error(), "invalid x-api-key") {
+		t.Errorf("error = %q, want the API's message", apiErr.Error())
+	}
+}
+
+func TestGetCostReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/organizations/cost_report" {
+			t.Errorf("Path = %s, want the cost report", r.URL.Path)
+		}
+		if got := r.URL.Query()["group_by[]"]; len(got) != 1 || got[0] != "workspace_id" {
+			t.Errorf("group_by[] = %v, want [workspace_id]", got)
+		}
+		_, _ = w.Write([]byte(`{"data": [{"starting_at": "2026-01-01T00:00:00Z", "ending_at": "2026-01-02T00:00:00Z",
+			"results": [{"currency": "USD", "amount": "1234.5", "workspace_id": "wrk_1"}, {"currency": "USD", "amount": 66}]}],
+			"has_more": false}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("", WithBaseURL(server.URL), WithAPIKey("key"))
+	buckets, err := c.GetCostReport(context.Background(), time.Now().Add(-24*time.Hour), time.Time{}, []string{"workspace_id"})
+	if err != nil {
+		t.Fatalf("GetCostReport failed: %v", err)
+	}
+	results := buckets[0].Results
+	if results[0].Amount != 1234.5 || results[1].Amount != 66 {
+		t.Errorf("amounts = %v, %v, want 1234.5 and 66 from a string and a number", results[0].Amount, results[1].Amount)
+	}
+	if results[0].WorkspaceID == nil || *results[0].WorkspaceID != "wrk_1" {
+		t.Errorf("WorkspaceID = %v, want wrk_1", results[0].WorkspaceID)
+	}
+}
+
+func TestGetClaudeCodeReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		day := q.Get("starting_at")
+		if q.Get("page") == "" && day == "2026-01-02" {
+			_, _ = w.Write([]byte(`{"data": [{"date": "2026-01-02T00:00:00Z", "actor": {"type": "user_actor", "email_address": "ada@example.com"}}],
+				"has_more": true, "next_page": "p2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"date": "` + day + `T00:00:00Z", "actor": {"type": "api_actor", "api_key_name": "ci"},
+			"model_breakdown": [{"model": "claude-sonnet-4", "tokens": {"input": 7}, "estimated_cost": {"currency": "USD", "amount": 12}}]}],
+			"has_more": false}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("", WithBaseURL(server.URL), WithAPIKey("key"))
+	start := time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC)
+	records, err := c.GetClaudeCodeReport(context.Background(), start, start.Add(36*time.Hour))
+	if err != nil {
+		t.Fatalf("GetClaudeCodeReport failed: %v", err)
+	}
+
+	// One record for Jan 1 and 3, and two pages for Jan 2
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	if records[0].ActorName() != "ci" || records[1].ActorName() != "ada@example.com" {
+		t.Errorf("actors = %s, %s, want ci then ada@example.com", records[0].ActorName(), records[1].ActorName())
+	}
+	if m := records[0].ModelBreakdown[0]; m.Tokens.Input != 7 || m.EstimatedCost.Amount != 12 {
+		t.Errorf("model breakdown = %+v, want 7 input tokens costing 12", m)
+	}
+}
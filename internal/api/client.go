@@ -1,28 +1,44 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
 	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
 	"github.com/benjaminabbitt/claude-limits/internal/version"
 )
 
 // DefaultBaseURL is the default Anthropic API endpoint
 const DefaultBaseURL = "https://api.anthropic.com"
 
-// Retry configuration
+// OAuth endpoints
 const (
-	maxRetries     = 3
-	initialBackoff = 500 * time.Millisecond
-	maxBackoff     = 5 * time.Second
+	usagePath   = "/api/oauth/usage"
+	profilePath = "/api/oauth/profile"
+)
+
+// Request and retry defaults
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultMaxRetries     = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+
+	// maxRetryAfter is the longest Retry-After we'll wait out; longer
+	// requests fail immediately rather than stalling the caller
+	maxRetryAfter = 60 * time.Second
 )
 
 // userAgent returns a User-Agent string matching Claude Code format
@@ -31,11 +47,49 @@ func userAgent() string {
 		version.Version, runtime.GOOS, runtime.GOARCH, runtime.Version()[2:])
 }
 
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is how many times a failed request is retried; 0 disables retries
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, doubling for each one after
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// Validators are the response headers that identify a version of the usage
+// data, sent back on the next request so the server can answer 304 Not
+// Modified instead of the full body
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether no validators are set
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
 // Client is the Anthropic OAuth API client
 type Client struct {
 	accessToken string
+	apiKey      string
 	baseURL     string
 	httpClient  *http.Client
+	transport   http.RoundTripper
+	timeout     time.Duration
+	retry       RetryPolicy
+	telemetry   *telemetry.Exporter
+	trace       *tracer
 }
 
 // ClientOption configures a Client
@@ -55,6 +109,58 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithTransport sets the transport used for requests, e.g. one built by
+// NewTransport. It replaces the transport of a WithHTTPClient client.
+func WithTransport(t http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried.
+// Zero disables retries; negative values are ignored.
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) {
+		if n >= 0 {
+			c.retry.MaxRetries = n
+		}
+	}
+}
+
+// WithRetryPolicy replaces the retry policy. Zero or negative backoffs keep
+// their defaults; a negative MaxRetries keeps the current count.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if p.MaxRetries >= 0 {
+			c.retry.MaxRetries = p.MaxRetries
+		}
+		if p.InitialBackoff > 0 {
+			c.retry.InitialBackoff = p.InitialBackoff
+		}
+		if p.MaxBackoff > 0 {
+			c.retry.MaxBackoff = p.MaxBackoff
+		}
+	}
+}
+
+// WithTimeout sets the time limit for each request attempt, including
+// reading the response. Zero or negative values are ignored.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithTelemetry records a span for each usage fetch, with a child span per
+// request attempt, to an OpenTelemetry exporter
+func WithTelemetry(e *telemetry.Exporter) ClientOption {
+	return func(c *Client) {
+		c.telemetry = e
+	}
+}
+
 // NewClient creates a new API client with the given OAuth access token.
 // The base URL can be overridden via CLAUDE_API_BASE_URL environment variable
 // or WithBaseURL option.
@@ -62,9 +168,8 @@ func NewClient(accessToken string, opts ...ClientOption) *Client {
 	c := &Client{
 		accessToken: accessToken,
 		baseURL:     DefaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:  &http.Client{},
+		retry:       DefaultRetryPolicy(),
 	}
 
 	// Check environment variable for base URL override
@@ -77,6 +182,28 @@ func NewClient(accessToken string, opts ...ClientOption) *Client {
 		opt(c)
 	}
 
+	// Apply settings to a copy, so a caller's WithHTTPClient is never
+	// modified. A custom client keeps its own timeout unless WithTimeout is set.
+	if c.transport != nil || c.timeout > 0 || c.httpClient.Timeout == 0 || c.trace != nil {
+		hc := *c.httpClient
+		if c.transport != nil {
+			hc.Transport = c.transport
+		}
+		if c.trace != nil {
+			next := hc.Transport
+			if next == nil {
+				next = http.DefaultTransport
+			}
+			hc.Transport = &traceTransport{next: next, tracer: c.trace}
+		}
+		if c.timeout > 0 {
+			hc.Timeout = c.timeout
+		} else if hc.Timeout == 0 {
+			hc.Timeout = DefaultTimeout
+		}
+		c.httpClient = &hc
+	}
+
 	return c
 }
 
@@ -93,82 +220,278 @@ func isRetriable(statusCode int) bool {
 	}
 }
 
-// backoffDuration calculates exponential backoff with jitter
-func backoffDuration(attempt int) time.Duration {
-	backoff := float64(initialBackoff) * math.Pow(2, float64(attempt))
-	if backoff > float64(maxBackoff) {
-		backoff = float64(maxBackoff)
+// backoff calculates the capped exponential backoff for an attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(2, float64(attempt))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
 	}
 	return time.Duration(backoff)
 }
 
+// withJitter randomizes d to between half and all of it, so clients that
+// failed together don't retry in lockstep
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter reads a Retry-After header in either delay-seconds or
+// HTTP-date form. Returns 0 if absent or unparseable.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// retryDelay returns how long to wait before retrying after err: the
+// server's Retry-After when given, otherwise jittered exponential backoff.
+// Returns false if the server asked for a wait longer than maxRetryAfter.
+func (p RetryPolicy) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var apiErr *apierrors.APIError
+	if apierrors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > maxRetryAfter {
+			return 0, false
+		}
+		return apiErr.RetryAfter, true
+	}
+	return withJitter(p.backoff(attempt)), true
+}
+
 // GetUsage fetches the current usage from Anthropic API with automatic retry
 func (c *Client) GetUsage() (*models.Usage, error) {
-	reqURL := fmt.Sprintf("%s/api/oauth/usage", c.baseURL)
+	return c.GetUsageContext(context.Background())
+}
+
+// GetUsageContext is like GetUsage but aborts the request and any pending
+// retries when ctx is cancelled or its deadline passes.
+func (c *Client) GetUsageContext(ctx context.Context) (*models.Usage, error) {
+	usage, _, err := c.GetUsageIfModified(ctx, Validators{})
+	return usage, err
+}
+
+// GetUsageIfModified makes a conditional request using validators from an
+// earlier response. If the data hasn't changed it returns ErrNotModified,
+// and the caller should keep using its copy. Otherwise it returns the new
+// usage with its validators, which are zero if the server sent none.
+func (c *Client) GetUsageIfModified(ctx context.Context, v Validators) (usage *models.Usage, validators Validators, err error) {
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.get_usage", telemetry.Attr{Key: "claude_limits.conditional", Value: !v.IsZero()})
+	defer func() {
+		if apierrors.Is(err, apierrors.ErrNotModified) {
+			span.End(nil)
+			return
+		}
+		span.End(err)
+	}()
+
+	return c.getUsage(ctx, v)
+}
+
+// GetAccountProfile fetches the profile of the account the token belongs to
+func (c *Client) GetAccountProfile(ctx context.Context) (profile *models.AccountProfile, err error) {
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.get_account_profile")
+	defer func() { span.End(err) }()
+
+	body, _, err := c.get(ctx, profilePath, Validators{})
+	if err != nil {
+		return nil, err
+	}
+	profile = &models.AccountProfile{}
+	if err := json.Unmarshal(body, profile); err != nil {
+		return nil, fmt.Errorf("%w: %w", apierrors.ErrResponseParse, err)
+	}
+	return profile, nil
+}
+
+func (c *Client) getUsage(ctx context.Context, v Validators) (*models.Usage, Validators, error) {
+	body, header, err := c.get(ctx, usagePath, v)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+
+	var usage models.Usage
+	if err := json.Unmarshal(body, &usage); err != nil {
+		return nil, Validators{}, fmt.Errorf("%w: %w", apierrors.ErrResponseParse, err)
+	}
+	// Keep the body byte for byte, including any surrounding whitespace
+	usage.Raw = body
+	usage.RateLimits = parseRateLimits(header)
+
+	validators := Validators{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+	return &usage, validators, nil
+}
+
+// get requests path under the base URL, retrying per the client's policy,
+// and returns the response body and headers
+func (c *Client) get(ctx context.Context, path string, v Validators) ([]byte, http.Header, error) {
+	reqURL := c.baseURL + path
 
 	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
 		if attempt > 0 {
-			time.Sleep(backoffDuration(attempt - 1))
+			delay, ok := c.retry.retryDelay(lastErr, attempt-1)
+			if !ok {
+				c.trace.printf("not retrying: Retry-After exceeds %s", maxRetryAfter)
+				return nil, nil, lastErr
+			}
+			c.trace.printf("retry %d of %d in %s: %v", attempt, c.retry.MaxRetries, delay.Round(time.Millisecond), lastErr)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, nil, err
+			}
 		}
 
-		usage, err, retry := c.doRequest(reqURL)
+		attemptCtx, span := c.telemetry.StartClient(ctx, "GET",
+			telemetry.Attr{Key: "http.request.method", Value: "GET"},
+			telemetry.Attr{Key: "url.full", Value: reqURL},
+			telemetry.Attr{Key: "http.request.resend_count", Value: attempt},
+		)
+		body, header, err, retry := c.doRequest(attemptCtx, reqURL, v)
+		endAttemptSpan(span, err)
 		if err == nil {
-			return usage, nil
+			return body, header, nil
 		}
 		lastErr = err
 		if !retry {
-			return nil, err
+			if !apierrors.Is(err, apierrors.ErrNotModified) {
+				c.trace.printf("not retrying: %v", err)
+			}
+			return nil, nil, err
 		}
 	}
 
-	return nil, fmt.Errorf("request failed after %d retries: %w", maxRetries, lastErr)
+	if c.retry.MaxRetries == 0 {
+		return nil, nil, lastErr
+	}
+	return nil, nil, fmt.Errorf("request failed after %d retries: %w", c.retry.MaxRetries, lastErr)
+}
+
+// endAttemptSpan finishes a request attempt's span with its response status
+func endAttemptSpan(span *telemetry.Span, err error) {
+	var apiErr *apierrors.APIError
+	switch {
+	case err == nil:
+		span.SetAttr(telemetry.Attr{Key: "http.response.status_code", Value: http.StatusOK})
+	case apierrors.Is(err, apierrors.ErrNotModified):
+		span.SetAttr(telemetry.Attr{Key: "http.response.status_code", Value: http.StatusNotModified})
+		err = nil
+	case apierrors.As(err, &apiErr):
+		span.SetAttr(telemetry.Attr{Key: "http.response.status_code", Value: apiErr.StatusCode})
+	}
+	span.End(err)
+}
+
+// sleepContext waits for d or until ctx is done, returning ctx's error in the latter case
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
-// doRequest performs a single HTTP request and returns whether it should be retried
-func (c *Client) doRequest(reqURL string) (*models.Usage, error, bool) {
-	req, err := http.NewRequest("GET", reqURL, nil)
+// doRequest performs a single HTTP request, conditional when v is set, and
+// returns the response body and headers, and whether the request should be
+// retried
+func (c *Client) doRequest(ctx context.Context, reqURL string, v Validators) ([]byte, http.Header, error, bool) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err), false
+		return nil, nil, fmt.Errorf("failed to create request: %w", err), false
 	}
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", userAgent())
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
-	req.Header.Set("anthropic-beta", "oauth-2025-04-20")
+	if c.apiKey != "" {
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("anthropic-beta", "oauth-2025-04-20")
+	}
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		// Network errors are retriable
-		return nil, fmt.Errorf("failed to make request: %w", err), true
+		// Cancellation is final; other network errors are retriable
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err(), false
+		}
+		return nil, nil, fmt.Errorf("%w: %w", apierrors.ErrRequestFailed, err), true
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && !v.IsZero() {
+		return nil, nil, apierrors.ErrNotModified, false
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		retriable := isRetriable(resp.StatusCode)
-		msg := http.StatusText(resp.StatusCode)
-		if len(body) > 0 {
-			var errResp struct {
-				Error string `json:"error"`
-			}
-			if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-				msg = errResp.Error
-			}
+		errType, msg := errorDetail(body)
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
 		}
-		return nil, apierrors.NewAPIError(resp.StatusCode, msg, retriable), retriable
+		apiErr := apierrors.NewAPIError(resp.StatusCode, msg, retriable)
+		apiErr.Type = errType
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, nil, apiErr, retriable
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err), true
+		return nil, nil, fmt.Errorf("%w: reading response: %w", apierrors.ErrRequestFailed, err), true
 	}
 
-	var usage models.Usage
-	if err := json.Unmarshal(body, &usage); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err), false
-	}
+	return body, resp.Header, nil, false
+}
 
-	return &usage, nil, false
+// errorDetail extracts the error type and message from an error response
+// body, which is either {"error": "message"} or, from the public API,
+// {"error": {"type": "...", "message": "..."}}
+func errorDetail(body []byte) (errType, msg string) {
+	var errResp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &errResp) != nil {
+		return "", ""
+	}
+	if json.Unmarshal(errResp.Error, &msg) == nil {
+		return "", msg
+	}
+	var detail struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(errResp.Error, &detail) == nil {
+		return detail.Type, detail.Message
+	}
+	return "", ""
 }
@@ -1,10 +1,16 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
 )
 
 func TestNewClient(t *testing.T) {
@@ -81,23 +87,49 @@ func TestIsRetriable(t *testing.T) {
 	}
 }
 
-func TestBackoffDuration(t *testing.T) {
+func TestBackoff(t *testing.T) {
+	p := DefaultRetryPolicy()
+
 	// First attempt: 500ms
-	d0 := backoffDuration(0)
+	d0 := p.backoff(0)
 	if d0 != 500*time.Millisecond {
-		t.Errorf("backoffDuration(0) = %v, want 500ms", d0)
+		t.Errorf("backoff(0) = %v, want 500ms", d0)
 	}
 
 	// Second attempt: 1000ms
-	d1 := backoffDuration(1)
+	d1 := p.backoff(1)
 	if d1 != 1000*time.Millisecond {
-		t.Errorf("backoffDuration(1) = %v, want 1000ms", d1)
+		t.Errorf("backoff(1) = %v, want 1000ms", d1)
+	}
+
+	// Should cap at MaxBackoff
+	d10 := p.backoff(10)
+	if d10 > p.MaxBackoff {
+		t.Errorf("backoff(10) = %v, should not exceed %v", d10, p.MaxBackoff)
 	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	c := NewClient("token", WithRetryPolicy(RetryPolicy{MaxRetries: 5, InitialBackoff: time.Second}))
 
-	// Should cap at maxBackoff
-	d10 := backoffDuration(10)
-	if d10 > maxBackoff {
-		t.Errorf("backoffDuration(10) = %v, should not exceed %v", d10, maxBackoff)
+	want := RetryPolicy{MaxRetries: 5, InitialBackoff: time.Second, MaxBackoff: DefaultMaxBackoff}
+	if c.retry != want {
+		t.Errorf("retry = %+v, want %+v", c.retry, want)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	if c := NewClient("token"); c.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("default Timeout = %v, want %v", c.httpClient.Timeout, DefaultTimeout)
+	}
+
+	custom := &http.Client{Timeout: time.Minute}
+	c := NewClient("token", WithHTTPClient(custom), WithTimeout(5*time.Second))
+	if c.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", c.httpClient.Timeout)
+	}
+	if custom.Timeout != time.Minute {
+		t.Error("WithTimeout modified the caller's http.Client")
 	}
 }
 
@@ -140,6 +172,55 @@ func TestGetUsageSuccess(t *testing.T) {
 	}
 }
 
+func TestGetUsageKeepsRawBody(t *testing.T) {
+	body := "{\n  \"seven_day\": {\"utilization\": 1.50},\n  \"five_hour\": null\n}\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	usage, err := NewClient("token", WithBaseURL(server.URL)).GetUsage()
+	if err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if string(usage.Raw) != body {
+		t.Errorf("Raw = %q, want the body unchanged: %q", usage.Raw, body)
+	}
+}
+
+func TestGetAccountProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/oauth/profile" {
+			t.Errorf("Path = %s, want /api/oauth/profile", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want 'Bearer test-token'", auth)
+		}
+		_, _ = w.Write([]byte(`{
+			"account": {"email_address": "ada@example.com", "full_name": "Ada Lovelace", "has_claude_max": true},
+			"organization": {"name": "Analytical Engines", "rate_limit_tier": "default_claude_max_20x"}
+		}`))
+	}))
+	defer server.Close()
+
+	profile, err := NewClient("test-token", WithBaseURL(server.URL)).GetAccountProfile(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccountProfile failed: %v", err)
+	}
+	if profile.Account.Email != "ada@example.com" {
+		t.Errorf("Email = %q, want ada@example.com", profile.Account.Email)
+	}
+	if profile.Name() != "Ada Lovelace" {
+		t.Errorf("Name() = %q, want the full name without a display name", profile.Name())
+	}
+	if !profile.Account.HasClaudeMax {
+		t.Error("HasClaudeMax = false, want true")
+	}
+	if profile.Organization.RateLimitTier != "default_claude_max_20x" {
+		t.Errorf("RateLimitTier = %q, want default_claude_max_20x", profile.Organization.RateLimitTier)
+	}
+}
+
 func TestGetUsageRetry(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -182,6 +263,203 @@ func TestGetUsageNonRetriableError(t *testing.T) {
 	}
 }
 
+func TestGetUsageErrorType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "error", "error": {"type": "permission_error", "message": "OAuth token lacks scope"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL))
+	_, err := c.GetUsage()
+
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an APIError", err)
+	}
+	if apiErr.Type != "permission_error" || apiErr.Message != "OAuth token lacks scope" {
+		t.Errorf("APIError = %+v, want the body's type and message", apiErr)
+	}
+	if !errors.Is(err, apierrors.ErrPermissionDenied) {
+		t.Error("a permission_error should match ErrPermissionDenied")
+	}
+	if code := apierrors.ExitCode(err); code != apierrors.ExitAuth {
+		t.Errorf("ExitCode = %d, want %d", code, apierrors.ExitAuth)
+	}
+}
+
+func TestGetUsageContextCancelledDuringRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	c := NewClient("token", WithBaseURL(server.URL))
+	start := time.Now()
+	_, err := c.GetUsageContext(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetUsageContext took %v, should stop retrying once ctx is done", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt before the backoff was cancelled, got %d", attempts)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	d := 1000 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		j := withJitter(d)
+		if j < d/2 || j > d {
+			t.Fatalf("withJitter(%v) = %v, want within [%v, %v]", d, j, d/2, d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"-1", 0},
+		{"soon", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGetUsageHonorsRetryAfter(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 1}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL))
+	if _, err := c.GetUsage(); err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if len(times) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(times))
+	}
+	if wait := times[1].Sub(times[0]); wait < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", wait)
+	}
+}
+
+func TestGetUsageLongRetryAfterFailsFast(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL))
+	_, err := c.GetUsage()
+
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Hour {
+		t.Fatalf("err = %v, want APIError with 1h RetryAfter", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestWithMaxRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL), WithMaxRetries(0))
+	if _, err := c.GetUsage(); err == nil {
+		t.Error("GetUsage should fail on 503")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt with retries disabled, got %d", attempts)
+	}
+}
+
+func TestGetUsageIfModified(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Wed, 01 Jan 2030 12:00:00 GMT"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 40}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL))
+
+	usage, v, err := c.GetUsageIfModified(context.Background(), Validators{})
+	if err != nil {
+		t.Fatalf("GetUsageIfModified failed: %v", err)
+	}
+	if usage == nil || usage.FiveHour == nil {
+		t.Fatal("expected usage on first request")
+	}
+	if v.ETag != etag || v.LastModified != lastModified {
+		t.Errorf("validators = %+v", v)
+	}
+
+	usage, _, err = c.GetUsageIfModified(context.Background(), v)
+	if !errors.Is(err, apierrors.ErrNotModified) {
+		t.Fatalf("err = %v, want ErrNotModified", err)
+	}
+	if usage != nil {
+		t.Error("expected nil usage when not modified")
+	}
+}
+
+func TestGetUsageUnconditional304(t *testing.T) {
+	// A 304 to a request we didn't make conditional is an error, not a cache hit
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL), WithMaxRetries(0))
+	_, err := c.GetUsage()
+	if err == nil || errors.Is(err, apierrors.ErrNotModified) {
+		t.Errorf("err = %v, want API error", err)
+	}
+}
+
 func TestUserAgent(t *testing.T) {
 	ua := userAgent()
 	if ua == "" {
@@ -192,3 +470,64 @@ func TestUserAgent(t *testing.T) {
 		t.Error("userAgent too short")
 	}
 }
+
+func TestGetUsageTelemetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 45}}`))
+	}))
+	defer server.Close()
+
+	var traces struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					Name         string `json:"name"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Status       struct {
+						Code int `json:"code"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&traces); err != nil {
+			t.Errorf("invalid traces: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	exporter := telemetry.New(collector.URL)
+	c := NewClient("token", WithBaseURL(server.URL), WithTelemetry(exporter),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond}))
+	if _, err := c.GetUsage(); err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if err := exporter.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 2 attempts and the fetch", len(spans))
+	}
+	fetch := spans[2]
+	if fetch.Name != "claude_limits.get_usage" || fetch.Status.Code != 1 {
+		t.Errorf("fetch span = %+v", fetch)
+	}
+	for i, attempt := range spans[:2] {
+		if attempt.ParentSpanID != fetch.SpanID {
+			t.Errorf("attempt %d isn't a child of the fetch span", i)
+		}
+	}
+	if spans[0].Status.Code != 2 {
+		t.Errorf("failed attempt status = %d, want error", spans[0].Status.Code)
+	}
+}
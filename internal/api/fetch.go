@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
+)
+
+// DefaultWorkers bounds how many requests FetchAll has in flight
+const DefaultWorkers = 4
+
+// Endpoint is an API path fetched by FetchAll
+type Endpoint struct {
+	// Name keys the endpoint's response in the merged document
+	Name string
+	// Path is appended to the base URL, e.g. "/api/oauth/usage"
+	Path string
+}
+
+// UsageEndpoint is the subscription usage endpoint
+var UsageEndpoint = Endpoint{Name: "usage", Path: usagePath}
+
+// FetchAll requests endpoints concurrently, at most workers at a time
+// (DefaultWorkers if workers < 1), each with the client's retry policy, and
+// merges the responses into one document keyed by endpoint name.
+//
+// Endpoints that fail are left out of the document and their errors joined
+// into the returned error, so callers can still use a partial result. Total
+// latency is roughly the slowest endpoint's rather than the sum; give ctx a
+// deadline to bound it, e.g. for the status line.
+func (c *Client) FetchAll(ctx context.Context, endpoints []Endpoint, workers int) (map[string]json.RawMessage, error) {
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	workers = min(workers, len(endpoints))
+
+	ctx, span := c.telemetry.Start(ctx, "claude_limits.fetch_all", telemetry.Attr{Key: "claude_limits.endpoints", Value: len(endpoints)})
+
+	type result struct {
+		endpoint Endpoint
+		body     []byte
+		err      error
+	}
+	jobs := make(chan Endpoint)
+	results := make(chan result, len(endpoints))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range jobs {
+				body, _, err := c.get(ctx, e.Path, Validators{})
+				if err == nil && !json.Valid(body) {
+					err = fmt.Errorf("%w: invalid JSON", apierrors.ErrResponseParse)
+				}
+				results <- result{endpoint: e, body: body, err: err}
+			}
+		}()
+	}
+	for _, e := range endpoints {
+		jobs <- e
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	doc := make(map[string]json.RawMessage, len(endpoints))
+	var errs []error
+	for r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.endpoint.Name, r.err))
+			continue
+		}
+		doc[r.endpoint.Name] = r.body
+	}
+
+	err := errors.Join(errs...)
+	span.End(err)
+	return doc, err
+}
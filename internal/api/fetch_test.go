@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchAll(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/broken":
+			w.WriteHeader(http.StatusForbidden)
+		case "/garbled":
+			w.Write([]byte("not json"))
+		default:
+			w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL), WithMaxRetries(0))
+	endpoints := []Endpoint{
+		{Name: "a", Path: "/a"},
+		{Name: "b", Path: "/b"},
+		{Name: "c", Path: "/c"},
+		{Name: "d", Path: "/d"},
+		{Name: "broken", Path: "/broken"},
+		{Name: "garbled", Path: "/garbled"},
+	}
+	doc, err := c.FetchAll(context.Background(), endpoints, 2)
+
+	if err == nil {
+		t.Fatal("FetchAll should report failed endpoints")
+	}
+	for _, name := range []string{"broken", "garbled"} {
+		if !strings.Contains(err.Error(), name+":") {
+			t.Errorf("error %q doesn't name endpoint %s", err, name)
+		}
+		if _, ok := doc[name]; ok {
+			t.Errorf("failed endpoint %s is in the document", name)
+		}
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if got, want := string(doc[name]), `{"path":"/`+name+`"}`; got != want {
+			t.Errorf("doc[%s] = %s, want %s", name, got, want)
+		}
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d requests in flight, want at most 2", p)
+	}
+}
+
+func TestFetchAllConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("token", WithBaseURL(server.URL))
+	endpoints := []Endpoint{UsageEndpoint, {Name: "a", Path: "/a"}, {Name: "b", Path: "/b"}}
+
+	start := time.Now()
+	doc, err := c.FetchAll(context.Background(), endpoints, 0)
+	if err != nil {
+		t.Fatalf("FetchAll failed: %v", err)
+	}
+	if len(doc) != len(endpoints) {
+		t.Errorf("doc has %d endpoints, want %d", len(doc), len(endpoints))
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("FetchAll took %s, want requests to overlap", elapsed)
+	}
+}
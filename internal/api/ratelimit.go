@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// rateLimitPrefix starts every rate-limit header name, in canonical form
+const rateLimitPrefix = "Anthropic-Ratelimit-"
+
+// parseRateLimits collects anthropic-ratelimit-<name>-<field> headers into
+// one RateLimit per name, sorted by name. Limit, remaining, and reset are
+// typed when they parse; every other field is kept as sent.
+func parseRateLimits(h http.Header) []models.RateLimit {
+	byName := make(map[string]*models.RateLimit)
+	for key, values := range h {
+		key = http.CanonicalHeaderKey(key)
+		if !strings.HasPrefix(key, rateLimitPrefix) || len(values) == 0 {
+			continue
+		}
+		rest := strings.ToLower(strings.TrimPrefix(key, rateLimitPrefix))
+		name, field, ok := cutLast(rest, "-")
+		if !ok {
+			name, field = rest, "value"
+		}
+
+		limit := byName[name]
+		if limit == nil {
+			limit = &models.RateLimit{Name: name}
+			byName[name] = limit
+		}
+		setRateLimitField(limit, field, values[0])
+	}
+
+	limits := make([]models.RateLimit, 0, len(byName))
+	for _, limit := range byName {
+		limits = append(limits, *limit)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Name < limits[j].Name })
+	if len(limits) == 0 {
+		return nil
+	}
+	return limits
+}
+
+func setRateLimitField(limit *models.RateLimit, field, value string) {
+	switch field {
+	case "limit", "remaining":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if field == "limit" {
+				limit.Limit = &n
+			} else {
+				limit.Remaining = &n
+			}
+			return
+		}
+	case "reset":
+		if t, ok := parseReset(value); ok {
+			limit.Reset = &t
+			return
+		}
+	}
+	if limit.Other == nil {
+		limit.Other = make(map[string]string)
+	}
+	limit.Other[field] = value
+}
+
+// parseReset reads a reset time sent as RFC 3339 or Unix seconds
+func parseReset(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
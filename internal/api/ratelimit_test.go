@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	h := http.Header{}
+	h.Set("anthropic-ratelimit-requests-limit", "50")
+	h.Set("anthropic-ratelimit-requests-remaining", "49")
+	h.Set("anthropic-ratelimit-requests-reset", "2030-01-01T12:00:00Z")
+	h.Set("anthropic-ratelimit-unified-5h-utilization", "0.42")
+	h.Set("anthropic-ratelimit-unified-5h-reset", "1893499200")
+	h.Set("anthropic-ratelimit-unified-status", "allowed")
+	h.Set("anthropic-ratelimit-tokens-limit", "lots")
+	h.Set("Content-Type", "application/json")
+
+	limits := parseRateLimits(h)
+	if len(limits) != 4 {
+		t.Fatalf("got %d limits, want 4: %+v", len(limits), limits)
+	}
+
+	names := []string{"requests", "tokens", "unified", "unified-5h"}
+	for i, name := range names {
+		if limits[i].Name != name {
+			t.Errorf("limits[%d].Name = %q, want %q", i, limits[i].Name, name)
+		}
+	}
+
+	requests := limits[0]
+	if requests.Limit == nil || *requests.Limit != 50 || requests.Remaining == nil || *requests.Remaining != 49 {
+		t.Errorf("requests = %+v, want limit 50, remaining 49", requests)
+	}
+	if want := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC); requests.Reset == nil || !requests.Reset.Equal(want) {
+		t.Errorf("requests reset = %v, want %v", requests.Reset, want)
+	}
+
+	if tokens := limits[1]; tokens.Limit != nil || tokens.Other["limit"] != "lots" {
+		t.Errorf("unparseable limit should be kept as sent: %+v", tokens)
+	}
+	if unified := limits[2]; unified.Other["status"] != "allowed" {
+		t.Errorf("unified = %+v, want status allowed", unified)
+	}
+
+	fiveHour := limits[3]
+	if fiveHour.Other["utilization"] != "0.42" {
+		t.Errorf("unified-5h utilization = %q", fiveHour.Other["utilization"])
+	}
+	if want := time.Unix(1893499200, 0); fiveHour.Reset == nil || !fiveHour.Reset.Equal(want) {
+		t.Errorf("unified-5h reset = %v, want %v", fiveHour.Reset, want)
+	}
+}
+
+func TestParseRateLimitsNone(t *testing.T) {
+	if limits := parseRateLimits(http.Header{"Content-Type": {"application/json"}}); limits != nil {
+		t.Errorf("limits = %+v, want nil", limits)
+	}
+}
+
+func TestGetUsageRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("anthropic-ratelimit-requests-remaining", "7")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	usage, err := NewClient("token", WithBaseURL(server.URL)).GetUsage()
+	if err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if len(usage.RateLimits) != 1 || usage.RateLimits[0].Name != "requests" || *usage.RateLimits[0].Remaining != 7 {
+		t.Errorf("RateLimits = %+v", usage.RateLimits)
+	}
+}
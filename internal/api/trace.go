@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/redact"
+)
+
+// redactedHeaders are never written to a trace, since they carry credentials
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// WithTrace writes each request's URL, headers, status, and timing, and
+// every retry decision, to w. Credentials in headers are redacted.
+func WithTrace(w io.Writer) ClientOption {
+	return func(c *Client) {
+		if w != nil {
+			c.trace = &tracer{w: w}
+		}
+	}
+}
+
+// tracer serializes trace output, so each entry is written in one piece
+type tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *tracer) printf(format string, args ...any) {
+	if t == nil {
+		return
+	}
+	t.write([]byte(fmt.Sprintf("* "+format+"\n", args...)))
+}
+
+func (t *tracer) write(p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(p)
+}
+
+// traceTransport traces requests made through next
+type traceTransport struct {
+	next   http.RoundTripper
+	tracer *tracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "* %s\n", time.Now().Format(time.RFC3339Nano))
+	if proxy := t.proxyFor(req); proxy != "" {
+		fmt.Fprintf(&buf, "* via proxy %s\n", proxy)
+	}
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, req.URL.Redacted())
+	writeHeaders(&buf, ">", req.Header)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&buf, "< error after %s: %v\n", elapsed, err)
+	} else {
+		fmt.Fprintf(&buf, "< %s (%s)\n", resp.Status, elapsed)
+		writeHeaders(&buf, "<", resp.Header)
+	}
+
+	// Headers are masked above; this catches IDs and tokens in URLs and errors
+	t.tracer.write([]byte(redact.String(buf.String())))
+	return resp, err
+}
+
+// proxyFor returns the proxy a request goes through, without credentials
+func (t *traceTransport) proxyFor(req *http.Request) string {
+	transport, ok := t.next.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		return ""
+	}
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil {
+		return ""
+	}
+	return proxy.Redacted()
+}
+
+// writeHeaders writes h sorted by name, with credentials redacted
+func writeHeaders(w io.Writer, prefix string, h http.Header) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range h[name] {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] {
+				value = redactHeader(name, value)
+			}
+			fmt.Fprintf(w, "%s %s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+// redactHeader masks a header value, keeping the scheme of an authorization
+// header, such as "Bearer"
+func redactHeader(name, value string) string {
+	if strings.HasSuffix(http.CanonicalHeaderKey(name), "Authorization") {
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " [REDACTED]"
+		}
+	}
+	return "[REDACTED]"
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c := NewClient("secret-token",
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		WithTrace(&buf),
+	)
+	if _, err := c.GetUsage(); err == nil {
+		t.Fatal("GetUsage should fail on 403")
+	}
+	trace := buf.String()
+
+	for _, secret := range []string{"secret-token", "secret-cookie"} {
+		if strings.Contains(trace, secret) {
+			t.Errorf("trace leaks %q:\n%s", secret, trace)
+		}
+	}
+	for _, want := range []string{
+		"> GET " + server.URL + "/api/oauth/usage",
+		"> Authorization: Bearer [REDACTED]",
+		"< 503 Service Unavailable (",
+		"< Set-Cookie: [REDACTED]",
+		"* retry 1 of 3 in ",
+		"< 403 Forbidden (",
+		"* not retrying: ",
+	} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+}
+
+func TestTraceTransportError(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewClient("token", WithBaseURL("http://127.0.0.1:1"), WithMaxRetries(0), WithTrace(&buf))
+	if _, err := c.GetUsage(); err == nil {
+		t.Fatal("GetUsage should fail without a server")
+	}
+	if !strings.Contains(buf.String(), "< error after ") {
+		t.Errorf("trace missing the connection error:\n%s", buf.String())
+	}
+}
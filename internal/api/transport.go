@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportConfig configures how the client connects to the API
+type TransportConfig struct {
+	// Proxy is an http, https, socks5, or socks5h proxy URL. Empty uses
+	// HTTPS_PROXY, HTTP_PROXY, and NO_PROXY from the environment.
+	Proxy string
+	// CACert is a PEM file of extra root certificates to trust, e.g. for a
+	// corporate proxy that re-signs TLS traffic
+	CACert string
+	// InsecureSkipVerify disables TLS certificate verification. It exposes the
+	// access token to anyone on the network path; use only for debugging.
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether cfg leaves the default transport unchanged
+func (cfg TransportConfig) IsZero() bool {
+	return cfg == TransportConfig{}
+}
+
+// NewTransport builds an HTTP transport from cfg, starting from the settings
+// of http.DefaultTransport
+func NewTransport(cfg TransportConfig) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := ParseProxyURL(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CACert != "" || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if t.TLSClientConfig != nil {
+			tlsConfig = t.TLSClientConfig.Clone()
+		}
+		if cfg.CACert != "" {
+			pool, err := loadCACert(cfg.CACert)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+		t.TLSClientConfig = tlsConfig
+	}
+
+	return t, nil
+}
+
+// ParseProxyURL parses a proxy address. A bare host:port is treated as an
+// http proxy, matching how HTTPS_PROXY is read.
+func ParseProxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https, socks5, or socks5h)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxy)
+	}
+	return u, nil
+}
+
+// loadCACert returns the system roots plus the certificates in path
+func loadCACert(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
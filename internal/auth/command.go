@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+// CommandTimeout bounds a credential command, leaving time for a password
+// manager to prompt for unlock
+const CommandTimeout = 30 * time.Second
+
+// commandTTL is how long a bare token from a credential command is reused.
+// Credentials JSON is reused until its token expires.
+const commandTTL = time.Hour
+
+// commandCache holds each credential command's last result, so pollers
+// don't run it on every fetch. The mutex only guards the map; each entry
+// has its own lock, so one slow command doesn't hold up the others.
+var commandCache = struct {
+	sync.Mutex
+	entries map[string]*commandEntry
+}{entries: make(map[string]*commandEntry)}
+
+// commandEntry is a command's cached result. Its lock is held while the
+// command runs, so concurrent fetches share one run rather than prompting
+// twice.
+type commandEntry struct {
+	sync.Mutex
+	creds   *Credentials
+	expires time.Time
+}
+
+// commandEntryFor returns command's cache entry, creating it if needed
+func commandEntryFor(command string) *commandEntry {
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	entry, ok := commandCache.entries[command]
+	if !ok {
+		entry = &commandEntry{}
+		commandCache.entries[command] = entry
+	}
+	return entry
+}
+
+// FromCommand runs command through the shell, like a git credential
+// helper, and reads credentials from its standard output: either Claude
+// Code's credentials JSON, or a bare access token. Results are cached in
+// memory until the token expires, or for an hour when that's unknown.
+func FromCommand(ctx context.Context, command string) (*Credentials, error) {
+	entry := commandEntryFor(command)
+	entry.Lock()
+	defer entry.Unlock()
+	if entry.creds != nil && time.Now().Before(entry.expires) {
+		return entry.creds, nil
+	}
+
+	out, err := runCommand(ctx, command)
+	if err != nil {
+		return nil, apierrors.NewAuthError("credential command", err)
+	}
+	creds, err := parseCommandOutput(out)
+	if err != nil {
+		return nil, apierrors.NewAuthError("credential command", err)
+	}
+
+	expires := time.Now().Add(commandTTL)
+	if creds.HasExpiry() {
+		expires = creds.ExpiresAt
+	}
+	entry.creds, entry.expires = creds, expires
+	return creds, nil
+}
+
+// runCommand runs command through the platform shell, returning its
+// standard output
+func runCommand(ctx context.Context, command string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, CommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("credential command timed out after %s", CommandTimeout)
+	case err != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential command failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("credential command failed: %w", err)
+	}
+	return out, nil
+}
+
+// parseCommandOutput reads credentials JSON, or else a bare access token
+// on the first line, as 'pass show' prints it
+func parseCommandOutput(out []byte) (*Credentials, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: credential command printed nothing", apierrors.ErrAuthRequired)
+	}
+	if out[0] == '{' {
+		return parse(out)
+	}
+	token, _, _ := bytes.Cut(out, []byte("\n"))
+	return &Credentials{AccessToken: string(bytes.TrimSpace(token))}, nil
+}
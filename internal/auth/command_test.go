@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+func TestFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	ctx := context.Background()
+
+	creds, err := FromCommand(ctx, `printf 'sk-ant-oat01-abc\nusername: alice\n'`)
+	if err != nil {
+		t.Fatalf("bare token: %v", err)
+	}
+	if creds.AccessToken != "sk-ant-oat01-abc" {
+		t.Errorf("AccessToken = %q, want the first line", creds.AccessToken)
+	}
+
+	creds, err = FromCommand(ctx, `echo '{"claudeAiOauth": {"accessToken": "tok", "expiresAt": 4102444800000, "subscriptionType": "max"}}'`)
+	if err != nil {
+		t.Fatalf("credentials JSON: %v", err)
+	}
+	if creds.AccessToken != "tok" || creds.SubscriptionType != "max" || creds.IsExpired() {
+		t.Errorf("credentials JSON = %+v", creds)
+	}
+
+	if _, err := FromCommand(ctx, "true"); !errors.Is(err, apierrors.ErrAuthRequired) {
+		t.Errorf("no output: got %v, want ErrAuthRequired", err)
+	}
+	_, err = FromCommand(ctx, "echo locked >&2; exit 1")
+	var authErr *apierrors.AuthError
+	if !errors.As(err, &authErr) || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("failure: got %v, want an AuthError with stderr", err)
+	}
+}
+
+func TestFromCommandCaches(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	count := filepath.Join(t.TempDir(), "count")
+	command := "echo run >> " + count + "; echo token"
+	for range 3 {
+		if _, err := FromCommand(context.Background(), command); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := len(data) / len("run\n"); runs != 1 {
+		t.Errorf("command ran %d times, want 1", runs)
+	}
+}
+
+func TestFromCommandRunsCommandsConcurrently(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	started, release := filepath.Join(dir, "started"), filepath.Join(dir, "release")
+	slow := "touch " + started + "; while [ ! -f " + release + " ]; do sleep 0.05; done; echo slow"
+
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := FromCommand(context.Background(), slow)
+		slowDone <- err
+	}()
+	defer func() {
+		_ = os.WriteFile(release, nil, 0600)
+		if err := <-slowDone; err != nil {
+			t.Errorf("slow command: %v", err)
+		}
+	}()
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Another profile's command isn't held up by the slow one
+	fastDone := make(chan error, 1)
+	go func() {
+		_, err := FromCommand(context.Background(), "echo fast")
+		fastDone <- err
+	}()
+	select {
+	case err := <-fastDone:
+		if err != nil {
+			t.Errorf("fast command: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast command waited for the slow one")
+	}
+}
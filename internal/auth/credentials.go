@@ -7,6 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
 )
 
 // Credentials represents the OAuth credentials from Claude Code.
@@ -14,6 +17,7 @@ type Credentials struct {
 	AccessToken      string
 	RefreshToken     string
 	ExpiresAt        time.Time
+	Scopes           []string
 	SubscriptionType string
 	RateLimitTier    string
 }
@@ -30,13 +34,14 @@ type credentialsFile struct {
 	} `json:"claudeAiOauth"`
 }
 
-// DefaultCredentialsPath returns the default path to Claude Code credentials.
+// DefaultCredentialsPath returns the default path to Claude Code credentials,
+// in its config directory (see claudecode.Dir).
 func DefaultCredentialsPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	dir := claudecode.Dir()
+	if dir == "" {
 		return ""
 	}
-	return filepath.Join(home, ".claude", ".credentials.json")
+	return filepath.Join(dir, ".credentials.json")
 }
 
 // Load reads OAuth credentials from the specified path.
@@ -45,34 +50,55 @@ func Load(path string) (*Credentials, error) {
 	if path == "" {
 		path = DefaultCredentialsPath()
 	}
+	// Headless, e.g. in a container without HOME
+	if path == "" {
+		return nil, apierrors.NewAuthError("credentials", fmt.Errorf("%w: no home directory to look in; set %s or a credentials path", apierrors.ErrCredentialsNotFound, claudecode.ConfigDirEnv))
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("Claude Code credentials not found at %s - please authenticate with Claude Code first", path)
+			return nil, apierrors.NewAuthError("credentials", fmt.Errorf("%w at %s - please authenticate with Claude Code first", apierrors.ErrCredentialsNotFound, path))
 		}
-		return nil, fmt.Errorf("failed to read credentials: %w", err)
+		return nil, apierrors.NewAuthError("credentials", fmt.Errorf("failed to read credentials: %w", err))
+	}
+
+	creds, err := parse(data)
+	if err != nil {
+		return nil, apierrors.NewAuthError("credentials", err)
 	}
+	return creds, nil
+}
 
+// parse decodes Claude Code's credentials JSON
+func parse(data []byte) (*Credentials, error) {
 	var cf credentialsFile
 	if err := json.Unmarshal(data, &cf); err != nil {
 		return nil, fmt.Errorf("failed to parse credentials: %w", err)
 	}
 
 	if cf.ClaudeAiOauth.AccessToken == "" {
-		return nil, fmt.Errorf("no OAuth access token found in credentials file")
+		return nil, fmt.Errorf("%w: no OAuth access token found in credentials", apierrors.ErrAuthRequired)
 	}
 
 	return &Credentials{
 		AccessToken:      cf.ClaudeAiOauth.AccessToken,
 		RefreshToken:     cf.ClaudeAiOauth.RefreshToken,
 		ExpiresAt:        time.UnixMilli(cf.ClaudeAiOauth.ExpiresAt),
+		Scopes:           cf.ClaudeAiOauth.Scopes,
 		SubscriptionType: cf.ClaudeAiOauth.SubscriptionType,
 		RateLimitTier:    cf.ClaudeAiOauth.RateLimitTier,
 	}, nil
 }
 
-// IsExpired returns true if the access token has expired.
+// IsExpired returns true if the access token has expired. Tokens without
+// a known expiry never have.
 func (c *Credentials) IsExpired() bool {
-	return time.Now().After(c.ExpiresAt)
+	return c.HasExpiry() && time.Now().After(c.ExpiresAt)
+}
+
+// HasExpiry reports whether the token's expiry is known. Bare tokens from a
+// credential command, and credentials without expiresAt, have none.
+func (c *Credentials) HasExpiry() bool {
+	return c.ExpiresAt.Unix() > 0
 }
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".credentials.json")
+	content := `{"claudeAiOauth": {"accessToken": "token", "expiresAt": 0, "subscriptionType": "max"}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if creds.AccessToken != "token" || creds.SubscriptionType != "max" || creds.HasExpiry() {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestLoadWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("USERPROFILE", "")
+	t.Setenv(claudecode.ConfigDirEnv, "")
+
+	_, err := Load("")
+	if !apierrors.Is(err, apierrors.ErrCredentialsNotFound) {
+		t.Fatalf("err = %v, want ErrCredentialsNotFound", err)
+	}
+	if !strings.Contains(err.Error(), claudecode.ConfigDirEnv) {
+		t.Errorf("err = %v, want it to name %s", err, claudecode.ConfigDirEnv)
+	}
+}
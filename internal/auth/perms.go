@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+)
+
+// ErrInsecurePermissions means a file holding secrets is readable by other
+// users
+var ErrInsecurePermissions = errors.New("readable by other users")
+
+// sharedBits are the permission bits granting access to the group and others
+const sharedBits os.FileMode = 0o077
+
+// CheckPermissions returns an error wrapping ErrInsecurePermissions if the
+// file at path grants access to its group or other users. Missing files
+// pass, as do all files on platforms without Unix permissions and on
+// Windows drives under WSL.
+func CheckPermissions(path string) error {
+	if claudecode.OnWindowsDrive(path) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if mode := info.Mode().Perm(); unixPermissions && mode&sharedBits != 0 {
+		return fmt.Errorf("%s is %w (mode %04o)", path, ErrInsecurePermissions, mode)
+	}
+	return nil
+}
+
+// FixPermissions removes group and other access from the file at path
+func FixPermissions(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(path, info.Mode().Perm()&^sharedBits)
+}
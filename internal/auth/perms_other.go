@@ -0,0 +1,7 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package auth
+
+// unixPermissions is false here: Windows file modes don't reflect ACLs, and
+// the user profile is already private
+const unixPermissions = false
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix permissions")
+	}
+	path := filepath.Join(t.TempDir(), ".credentials.json")
+	if err := CheckPermissions(path); err != nil {
+		t.Errorf("missing file: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckPermissions(path); err != nil {
+		t.Errorf("0600: %v", err)
+	}
+
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckPermissions(path); !errors.Is(err, ErrInsecurePermissions) {
+		t.Errorf("0644: got %v, want ErrInsecurePermissions", err)
+	}
+
+	if err := FixPermissions(path); err != nil {
+		t.Fatalf("FixPermissions: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("mode after fix = %04o, want 0600", mode)
+	}
+}
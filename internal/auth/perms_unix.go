@@ -0,0 +1,6 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package auth
+
+// unixPermissions reports whether file modes control who can read a file
+const unixPermissions = true
@@ -0,0 +1,72 @@
+// Package budget divides a window's remaining capacity into per-task budgets.
+package budget
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// SessionWindow is the window a working session runs in
+const SessionWindow = models.WindowFiveHour
+
+// Plan splits a window's remaining capacity evenly across tasks. Figures
+// are percentages of the window's limit unless noted; the burn-rate based
+// ones are zero without enough history.
+type Plan struct {
+	Window    string     `json:"window"`
+	Tasks     int        `json:"tasks"`
+	Used      float64    `json:"used"`
+	Remaining float64    `json:"remaining"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+	// PerTask is each task's share of Remaining
+	PerTask float64 `json:"per_task"`
+	// BurnRate is the window's recent consumption per active hour
+	BurnRate float64 `json:"burn_rate,omitempty"`
+	// TaskHours is how many active hours a task's share lasts at BurnRate
+	TaskHours float64 `json:"task_hours,omitempty"`
+	// SessionLimit is the SessionWindow utilization at which a task's share
+	// is spent, capped at 100
+	SessionLimit float64 `json:"session_limit,omitempty"`
+	// Sessions is how many full sessions a task's share lasts; above 1,
+	// the session limit rather than the budget ends each session
+	Sessions float64 `json:"sessions,omitempty"`
+}
+
+// New plans tasks against the window's remaining capacity in usage, with
+// recent burns (see history.Burns) for the pace and for converting a task's
+// share into session terms
+func New(usage *models.Usage, window string, tasks int, burns map[string]history.Burn) (Plan, error) {
+	if tasks < 1 {
+		return Plan{}, fmt.Errorf("tasks must be at least 1, got %d", tasks)
+	}
+	w := usage.Window(window)
+	if w == nil {
+		return Plan{}, fmt.Errorf("usage has no %s window", window)
+	}
+
+	plan := Plan{
+		Window:    window,
+		Tasks:     tasks,
+		Used:      w.Utilization,
+		Remaining: w.Remaining(),
+		ResetsAt:  w.ResetsAt,
+	}
+	plan.PerTask = plan.Remaining / float64(tasks)
+
+	burn := burns[window]
+	if rate := burn.PerHour(); rate > 0 {
+		plan.BurnRate = rate
+		plan.TaskHours = plan.PerTask / rate
+	}
+
+	// The session window moves this much for each point of the window
+	if session := burns[SessionWindow]; window != SessionWindow && session.Used > 0 && burn.Used > 0 {
+		spent := plan.PerTask * session.Used / burn.Used
+		plan.Sessions = spent / 100
+		plan.SessionLimit = min(spent, 100)
+	}
+	return plan, nil
+}
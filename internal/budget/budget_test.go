@@ -0,0 +1,71 @@
+package budget
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func usageOf(t *testing.T, raw string) *models.Usage {
+	t.Helper()
+	var u models.Usage
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return &u
+}
+
+func TestNew(t *testing.T) {
+	usage := usageOf(t, `{"five_hour": {"utilization": 10}, "seven_day": {"utilization": 60, "resets_at": "2025-01-21T00:00:00Z"}}`)
+	burns := map[string]history.Burn{
+		models.WindowSevenDay: {Used: 10, Active: 5 * time.Hour},
+		models.WindowFiveHour: {Used: 50, Active: 5 * time.Hour},
+	}
+
+	plan, err := New(usage, models.WindowSevenDay, 8, burns)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if plan.Remaining != 40 || plan.PerTask != 5 {
+		t.Errorf("remaining/per task = %v/%v, want 40/5", plan.Remaining, plan.PerTask)
+	}
+	if plan.BurnRate != 2 || plan.TaskHours != 2.5 {
+		t.Errorf("burn rate/task hours = %v/%v, want 2/2.5", plan.BurnRate, plan.TaskHours)
+	}
+	// 5 weekly points at 5 session points each
+	if plan.SessionLimit != 25 || plan.Sessions != 0.25 {
+		t.Errorf("session limit/sessions = %v/%v, want 25/0.25", plan.SessionLimit, plan.Sessions)
+	}
+
+	plan, err = New(usage, models.WindowSevenDay, 1, burns)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if plan.SessionLimit != 100 || plan.Sessions != 2 {
+		t.Errorf("one task: session limit/sessions = %v/%v, want 100/2", plan.SessionLimit, plan.Sessions)
+	}
+}
+
+func TestNewWithoutHistory(t *testing.T) {
+	usage := usageOf(t, `{"seven_day": {"utilization": 100}}`)
+	plan, err := New(usage, models.WindowSevenDay, 3, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if plan.PerTask != 0 || plan.BurnRate != 0 || plan.SessionLimit != 0 {
+		t.Errorf("plan = %+v, want no budget and no pace", plan)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	usage := usageOf(t, `{"five_hour": {"utilization": 10}}`)
+	if _, err := New(usage, models.WindowSevenDay, 2, nil); err == nil {
+		t.Error("New without the window should fail")
+	}
+	if _, err := New(usage, models.WindowFiveHour, 0, nil); err == nil {
+		t.Error("New with no tasks should fail")
+	}
+}
@@ -3,14 +3,23 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
 	"github.com/benjaminabbitt/claude-limits/internal/models"
 )
 
+// ErrUnsafeDir means a directory other users could read or replace
+var ErrUnsafeDir = errors.New("directory is not private")
+
 // File permission constants
 const (
 	DirMode  = 0700 // rwx------ for cache directory (private)
@@ -21,6 +30,13 @@ const (
 type Data struct {
 	Timestamp time.Time       `json:"timestamp"`
 	Usage     json.RawMessage `json:"usage"`
+
+	// Response validators for revalidating with a conditional request
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// Rate limits from the response's anthropic-ratelimit-* headers
+	RateLimits []models.RateLimit `json:"rate_limits,omitempty"`
 }
 
 // Cache manages the usage cache
@@ -30,31 +46,189 @@ type Cache struct {
 	verbose bool
 }
 
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithProfile keys the cache file by profile name so that switching
+// accounts never serves another account's data. An empty name uses the
+// default cache file.
+func WithProfile(profile string) Option {
+	return func(c *Cache) {
+		c.file = filepath.Join(c.dir, FileName(profile))
+	}
+}
+
+// WithDir stores the cache under dir instead of the platform default
+func WithDir(dir string) Option {
+	return func(c *Cache) {
+		c.file = filepath.Join(dir, filepath.Base(c.file))
+		c.dir = dir
+	}
+}
+
 // New creates a new Cache instance
-func New(verbose bool) *Cache {
-	dir := getCacheDir()
-	return &Cache{
+func New(verbose bool, opts ...Option) *Cache {
+	dir := DefaultDir()
+	c := &Cache{
 		dir:     dir,
-		file:    filepath.Join(dir, "usage.json"),
+		file:    filepath.Join(dir, FileName("")),
 		verbose: verbose,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// FileName returns the cache file name for a profile
+func FileName(profile string) string {
+	if profile == "" {
+		return "usage.json"
+	}
+	return "usage-" + profile + ".json"
 }
 
-// getCacheDir returns the platform-appropriate cache directory
-func getCacheDir() string {
+// Environment variables overriding the default directories
+const (
+	DirEnv      = "CLAUDE_LIMITS_CACHE_DIR"
+	StateDirEnv = "CLAUDE_LIMITS_STATE_DIR"
+)
+
+// DefaultDir returns the platform-appropriate cache directory, or
+// CLAUDE_LIMITS_CACHE_DIR if set
+func DefaultDir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir
+	}
+
 	// Use os.UserCacheDir for cross-platform cache location:
 	// - Linux: $XDG_CACHE_HOME or ~/.cache
 	// - macOS: ~/Library/Caches
 	// - Windows: %LocalAppData%
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
-		return os.TempDir()
+		dir := fallbackDir()
+		warnFallback.Do(func() {
+			slog.Warn("no user cache directory; using a private directory under the temp directory",
+				"dir", dir, "error", err)
+		})
+		return dir
 	}
 	return filepath.Join(cacheDir, "claudelimits")
 }
 
-// Read attempts to read cached data if it's still valid
-func (c *Cache) Read(ttlSeconds int) (*models.Usage, error) {
+// warnFallback warns about the temp directory fallback once per process
+var warnFallback sync.Once
+
+// fallbackDir is the cache directory used when the platform has none: a
+// per-user directory in the shared temp directory, which EnsureDir keeps
+// private
+func fallbackDir() string {
+	name := "claudelimits"
+	if uid := os.Getuid(); uid >= 0 {
+		name += "-" + strconv.Itoa(uid)
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+// EnsureDir creates dir with DirMode if needed. The temp directory fallback
+// is shared with other users, so there dir must also be a real directory
+// owned by the current user; looser permissions are tightened to DirMode.
+func EnsureDir(dir string) error {
+	if err := os.MkdirAll(dir, DirMode); err != nil {
+		return err
+	}
+	if dir != fallbackDir() {
+		return nil
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrUnsafeDir, dir)
+	}
+	if !ownedByCurrentUser(info) {
+		return fmt.Errorf("%w: %s is owned by another user", ErrUnsafeDir, dir)
+	}
+	if info.Mode().Perm()&^DirMode != 0 {
+		return os.Chmod(dir, DirMode)
+	}
+	return nil
+}
+
+// StateDir returns the platform-appropriate directory for state that should
+// outlive the cache, such as history and alert state, or
+// CLAUDE_LIMITS_STATE_DIR if set:
+//   - Linux and other Unix: $XDG_STATE_HOME or ~/.local/state
+//   - macOS: ~/Library/Application Support
+//   - Windows: %LocalAppData%
+func StateDir() string {
+	if dir := os.Getenv(StateDirEnv); dir != "" {
+		return dir
+	}
+
+	var base string
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		base, err = os.UserCacheDir()
+	case "darwin", "ios":
+		base, err = os.UserConfigDir()
+	default:
+		base = os.Getenv("XDG_STATE_HOME")
+		if base == "" {
+			var home string
+			home, err = os.UserHomeDir()
+			base = filepath.Join(home, ".local", "state")
+		}
+	}
+	if err != nil {
+		return DefaultDir()
+	}
+	return filepath.Join(base, "claudelimits")
+}
+
+// StatePath returns the path of the named state file. Files written by
+// versions that kept state in the cache directory are used where they are
+// until one exists in the state directory.
+func StatePath(name string) string {
+	path := filepath.Join(StateDir(), name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		legacy := filepath.Join(DefaultDir(), name)
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return path
+}
+
+// Read attempts to read cached data if it's no older than ttl
+func (c *Cache) Read(ttl time.Duration) (*models.Usage, error) {
+	cache, err := c.ReadStale()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if cache is still valid
+	if time.Since(cache.Timestamp) > ttl {
+		return nil, apierrors.ErrCacheExpired
+	}
+
+	return c.Usage(cache)
+}
+
+// ReadStale reads the cached entry regardless of age, for revalidating an
+// expired entry with a conditional request
+func (c *Cache) ReadStale() (*Data, error) {
+	// Locking is best-effort: writes are atomic regardless
+	if unlock, err := c.lock(false); err == nil {
+		defer unlock()
+	}
+
 	data, err := os.ReadFile(c.file)
 	if err != nil {
 		return nil, apierrors.NewCacheError("read", c.file, err)
@@ -65,24 +239,34 @@ func (c *Cache) Read(ttlSeconds int) (*models.Usage, error) {
 		return nil, apierrors.NewCacheError("parse", c.file, err)
 	}
 
-	// Check if cache is still valid
-	if time.Since(cache.Timestamp) > time.Duration(ttlSeconds)*time.Second {
-		return nil, apierrors.ErrCacheExpired
-	}
+	return &cache, nil
+}
 
+// Usage decodes the usage stored in a cache entry
+func (c *Cache) Usage(d *Data) (*models.Usage, error) {
 	var usage models.Usage
-	if err := json.Unmarshal(cache.Usage, &usage); err != nil {
+	if err := json.Unmarshal(d.Usage, &usage); err != nil {
 		return nil, apierrors.NewCacheError("parse", c.file, err)
 	}
-
+	usage.RateLimits = d.RateLimits
 	return &usage, nil
 }
 
 // Write saves usage data to the cache
 func (c *Cache) Write(usage *models.Usage) error {
+	return c.WriteValidated(usage, "", "")
+}
+
+// WriteValidated saves usage data along with the ETag and Last-Modified
+// headers of the response it came from. Rewriting an entry after a 304
+// refreshes its timestamp.
+func (c *Cache) WriteValidated(usage *models.Usage, etag, lastModified string) error {
 	cache := Data{
-		Timestamp: time.Now(),
-		Usage:     usage.Raw,
+		Timestamp:    time.Now(),
+		Usage:        usage.Raw,
+		ETag:         etag,
+		LastModified: lastModified,
+		RateLimits:   usage.RateLimits,
 	}
 
 	data, err := json.Marshal(cache)
@@ -91,17 +275,85 @@ func (c *Cache) Write(usage *models.Usage) error {
 	}
 
 	// Create cache directory if needed
-	if err := os.MkdirAll(c.dir, DirMode); err != nil {
+	if err := EnsureDir(c.dir); err != nil {
 		return apierrors.NewCacheError("mkdir", c.dir, err)
 	}
 
-	if err := os.WriteFile(c.file, data, FileMode); err != nil {
+	if unlock, err := c.lock(true); err == nil {
+		defer unlock()
+	}
+
+	if err := WriteAtomic(c.file, data, FileMode); err != nil {
 		return apierrors.NewCacheError("write", c.file, err)
 	}
 
 	return nil
 }
 
+// Clear deletes the cache file and its lock file. A missing file is not an error.
+func (c *Cache) Clear() error {
+	for _, path := range []string{c.file, c.file + ".lock"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return apierrors.NewCacheError("remove", path, err)
+		}
+	}
+	return nil
+}
+
+// lock takes an advisory lock on the cache's lock file, serializing writers
+// and keeping readers from racing a replace (which fails on Windows while
+// the file is open). Returns a function that releases the lock.
+func (c *Cache) lock(exclusive bool) (func(), error) {
+	return Lock(c.file, exclusive)
+}
+
+// Lock takes an advisory lock on path's lock file (path + ".lock"), shared
+// or exclusive, blocking until granted. Returns a function that releases
+// the lock.
+func Lock(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, FileMode)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// WriteAtomic writes data to a temp file in the same directory and renames it
+// over path with permissions perm, so readers see either the old or the new
+// contents, never a mix
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Clean up on any failure; after a successful rename this is a no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // Dir returns the cache directory path
 func (c *Cache) Dir() string {
 	return c.dir
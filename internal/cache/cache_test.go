@@ -2,8 +2,12 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,7 +46,7 @@ func TestCacheReadWrite(t *testing.T) {
 	}
 
 	// Read from cache with valid TTL
-	cached, err := c.Read(60)
+	cached, err := c.Read(time.Minute)
 	if err != nil {
 		t.Fatalf("Read failed: %v", err)
 	}
@@ -82,7 +86,7 @@ func TestCacheReadNonexistent(t *testing.T) {
 		verbose: false,
 	}
 
-	_, err := c.Read(60)
+	_, err := c.Read(time.Minute)
 	if err == nil {
 		t.Error("Read of nonexistent file should return error")
 	}
@@ -104,7 +108,7 @@ func TestCacheReadInvalidJSON(t *testing.T) {
 		verbose: false,
 	}
 
-	_, err = c.Read(60)
+	_, err = c.Read(time.Minute)
 	if err == nil {
 		t.Error("Read of invalid JSON should return error")
 	}
@@ -160,7 +164,7 @@ func TestCacheDataIntegrity(t *testing.T) {
 	// Small sleep to ensure timestamp difference
 	time.Sleep(10 * time.Millisecond)
 
-	cached, err := c.Read(60)
+	cached, err := c.Read(time.Minute)
 	if err != nil {
 		t.Fatalf("Read failed: %v", err)
 	}
@@ -192,3 +196,312 @@ func TestNew(t *testing.T) {
 		t.Error("Cache file is empty")
 	}
 }
+
+func TestNewWithProfile(t *testing.T) {
+	def := New(false)
+	work := New(false, WithProfile("work"))
+
+	if def.file == work.file {
+		t.Errorf("profile cache file should differ from default, both %s", def.file)
+	}
+	if filepath.Base(work.file) != "usage-work.json" {
+		t.Errorf("profile cache file = %s, want usage-work.json", filepath.Base(work.file))
+	}
+	if filepath.Dir(work.file) != def.dir {
+		t.Errorf("profile cache file should live in cache dir %s, got %s", def.dir, work.file)
+	}
+}
+
+func TestNewWithDir(t *testing.T) {
+	dir := t.TempDir()
+	c := New(false, WithDir(dir), WithProfile("work"))
+
+	if c.Dir() != dir {
+		t.Errorf("Dir() = %s, want %s", c.Dir(), dir)
+	}
+	if c.File() != filepath.Join(dir, "usage-work.json") {
+		t.Errorf("File() = %s, want %s", c.File(), filepath.Join(dir, "usage-work.json"))
+	}
+}
+
+func TestCacheConcurrentReadWrite(t *testing.T) {
+	c := New(false, WithDir(t.TempDir()))
+
+	// Payloads of different sizes make a torn write detectable as invalid JSON
+	small := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour":{"utilization":1}}`), small)
+	large := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour":{"utilization":99},"padding":"`+strings.Repeat("x", 64*1024)+`"}`), large)
+
+	if err := c.Write(small); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				usage := small
+				if (i+j)%2 == 0 {
+					usage = large
+				}
+				if err := c.Write(usage); err != nil {
+					t.Errorf("Write failed: %v", err)
+					return
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				if _, err := c.Read(time.Minute); err != nil {
+					t.Errorf("Read failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCacheWriteLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := New(false, WithDir(dir))
+
+	usage := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour":{"utilization":1}}`), usage)
+	for i := 0; i < 3; i++ {
+		if err := c.Write(usage); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestWriteAtomicPerm(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits aren't enforced on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "badge.svg")
+	if err := WriteAtomic(path, []byte("<svg/>"), 0644); err != nil {
+		t.Fatalf("WriteAtomic failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("perm = %o, want 644", perm)
+	}
+}
+
+func TestCacheRateLimits(t *testing.T) {
+	c := New(false, WithDir(t.TempDir()))
+
+	remaining := int64(3)
+	usage := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour": {"utilization": 40}}`), usage)
+	usage.RateLimits = []models.RateLimit{{Name: "requests", Remaining: &remaining}}
+	if err := c.Write(usage); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	cached, err := c.Read(time.Minute)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(cached.RateLimits) != 1 || cached.RateLimits[0].Name != "requests" || *cached.RateLimits[0].Remaining != 3 {
+		t.Errorf("RateLimits = %+v", cached.RateLimits)
+	}
+}
+
+func TestCacheReadStaleValidators(t *testing.T) {
+	c := New(false, WithDir(t.TempDir()))
+
+	usage := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour": {"utilization": 40}}`), usage)
+	if err := c.WriteValidated(usage, `"v1"`, "Wed, 01 Jan 2030 12:00:00 GMT"); err != nil {
+		t.Fatalf("WriteValidated failed: %v", err)
+	}
+
+	// Backdate the entry so Read treats it as expired
+	entry, err := c.ReadStale()
+	if err != nil {
+		t.Fatalf("ReadStale failed: %v", err)
+	}
+	entry.Timestamp = time.Now().Add(-time.Hour)
+	data, _ := json.Marshal(entry)
+	if err := os.WriteFile(c.File(), data, FileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Read(time.Minute); err == nil {
+		t.Fatal("expected expired entry")
+	}
+
+	stale, err := c.ReadStale()
+	if err != nil {
+		t.Fatalf("ReadStale failed: %v", err)
+	}
+	if stale.ETag != `"v1"` || stale.LastModified != "Wed, 01 Jan 2030 12:00:00 GMT" {
+		t.Errorf("validators = %q, %q", stale.ETag, stale.LastModified)
+	}
+	got, err := c.Usage(stale)
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if got.FiveHour == nil || got.FiveHour.Utilization != 40 {
+		t.Errorf("FiveHour = %+v, want utilization 40", got.FiveHour)
+	}
+}
+
+func TestCacheClear(t *testing.T) {
+	c := New(false, WithDir(t.TempDir()), WithProfile("work"))
+
+	// Clearing an empty cache is fine
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear on empty cache failed: %v", err)
+	}
+
+	usage := &models.Usage{}
+	_ = json.Unmarshal([]byte(`{"five_hour": {"utilization": 40}}`), usage)
+	if err := c.Write(usage); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(c.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("leftover file after Clear: %s", e.Name())
+	}
+}
+
+func TestDefaultDirEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DirEnv, dir)
+
+	if got := DefaultDir(); got != dir {
+		t.Errorf("DefaultDir() = %s, want %s", got, dir)
+	}
+	if got := New(false).Dir(); got != dir {
+		t.Errorf("New().Dir() = %s, want %s", got, dir)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(StateDirEnv, dir)
+	if got := StateDir(); got != dir {
+		t.Errorf("StateDir() = %s, want %s", got, dir)
+	}
+
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return
+	}
+	t.Setenv(StateDirEnv, "")
+	t.Setenv("XDG_STATE_HOME", dir)
+	if got, want := StateDir(), filepath.Join(dir, "claudelimits"); got != want {
+		t.Errorf("StateDir() = %s, want %s", got, want)
+	}
+}
+
+func TestStatePathLegacy(t *testing.T) {
+	cacheDir, stateDir := t.TempDir(), t.TempDir()
+	t.Setenv(DirEnv, cacheDir)
+	t.Setenv(StateDirEnv, stateDir)
+
+	if got, want := StatePath("history.db"), filepath.Join(stateDir, "history.db"); got != want {
+		t.Errorf("StatePath() = %s, want %s for a new file", got, want)
+	}
+
+	legacy := filepath.Join(cacheDir, "history.db")
+	if err := os.WriteFile(legacy, nil, FileMode); err != nil {
+		t.Fatal(err)
+	}
+	if got := StatePath("history.db"); got != legacy {
+		t.Errorf("StatePath() = %s, want legacy %s", got, legacy)
+	}
+
+	current := filepath.Join(stateDir, "history.db")
+	if err := os.WriteFile(current, nil, FileMode); err != nil {
+		t.Fatal(err)
+	}
+	if got := StatePath("history.db"); got != current {
+		t.Errorf("StatePath() = %s, want %s once it exists", got, current)
+	}
+}
+
+func TestEnsureDirFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits aren't enforced on Windows")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+	dir := fallbackDir()
+	if filepath.Dir(dir) != os.TempDir() {
+		t.Fatalf("fallbackDir() = %s, want it under %s", dir, os.TempDir())
+	}
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureDir(dir); err != nil {
+		t.Fatalf("EnsureDir failed: %v", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != DirMode {
+		t.Errorf("fallback dir mode = %o, want %o", info.Mode().Perm(), DirMode)
+	}
+
+	// A symlink planted by another user must not be followed
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureDir(dir); !errors.Is(err, ErrUnsafeDir) {
+		t.Errorf("EnsureDir on a symlink = %v, want ErrUnsafeDir", err)
+	}
+}
+
+func TestEnsureDirLeavesOtherDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits aren't enforced on Windows")
+	}
+	dir := filepath.Join(t.TempDir(), "shared")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureDir(dir); err != nil {
+		t.Fatalf("EnsureDir failed: %v", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("EnsureDir changed the mode of a chosen directory to %o", info.Mode().Perm())
+	}
+}
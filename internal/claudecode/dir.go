@@ -0,0 +1,99 @@
+package claudecode
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ConfigDirEnv relocates Claude Code's config directory, as it does for
+// Claude Code itself
+const ConfigDirEnv = "CLAUDE_CONFIG_DIR"
+
+// Location says where Claude Code's config directory was found
+type Location string
+
+const (
+	// LocationEnv is CLAUDE_CONFIG_DIR
+	LocationEnv Location = "env"
+	// LocationHome is ~/.claude
+	LocationHome Location = "home"
+	// LocationWindows is .claude in the Windows user profile, seen from WSL
+	LocationWindows Location = "windows"
+)
+
+// Overridden in tests
+var (
+	osReleasePath   = "/proc/sys/kernel/osrelease"
+	windowsUsersDir = "/mnt/c/Users"
+)
+
+// Dir returns Claude Code's config directory, holding its credentials,
+// settings, and transcripts (see Locate). Returns "" if the home directory
+// can't be determined.
+func Dir() string {
+	dir, _ := Locate()
+	return dir
+}
+
+// Locate finds Claude Code's config directory: CLAUDE_CONFIG_DIR if set,
+// else ~/.claude. Under WSL, when ~/.claude doesn't exist but the Windows
+// user profile has one, that's used, since Claude Code often runs on the
+// Windows side.
+func Locate() (string, Location) {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return dir, LocationEnv
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", LocationHome
+	}
+	dir := filepath.Join(home, ".claude")
+	if _, err := os.Stat(dir); os.IsNotExist(err) && IsWSL() {
+		if win := windowsDir(); win != "" {
+			return win, LocationWindows
+		}
+	}
+	return dir, LocationHome
+}
+
+// IsWSL reports whether this is Linux running under Windows Subsystem for
+// Linux
+func IsWSL() bool {
+	data, err := os.ReadFile(osReleasePath)
+	return err == nil && strings.Contains(strings.ToLower(string(data)), "microsoft")
+}
+
+// windowsDrivePattern matches paths on Windows drives mounted into WSL
+var windowsDrivePattern = regexp.MustCompile(`^/mnt/[a-z](/|$)`)
+
+// OnWindowsDrive reports whether path is on a Windows drive mounted into
+// WSL, where file modes don't say who can read a file
+func OnWindowsDrive(path string) bool {
+	return windowsDrivePattern.MatchString(path) && IsWSL()
+}
+
+// windowsDir returns the .claude directory in the Windows user profile:
+// USERPROFILE's if shared through WSLENV, else the one under the same user
+// name, else the only one. Returns "" if none is found.
+func windowsDir() string {
+	var candidates []string
+	if profile := os.Getenv("USERPROFILE"); strings.HasPrefix(profile, "/") {
+		candidates = append(candidates, filepath.Join(profile, ".claude"))
+	}
+	if user := os.Getenv("USER"); user != "" {
+		candidates = append(candidates, filepath.Join(windowsUsersDir, user, ".claude"))
+	}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(windowsUsersDir, "*", ".claude"))
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return ""
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ErrStatusLineExists indicates the statusLine field already exists in settings
@@ -21,13 +22,14 @@ type StatusLine struct {
 // Uses map to preserve unknown fields
 type Settings map[string]interface{}
 
-// DefaultUserSettingsPath returns the default path to user-level Claude Code settings
+// DefaultUserSettingsPath returns the default path to user-level Claude Code
+// settings, in Dir
 func DefaultUserSettingsPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	dir := Dir()
+	if dir == "" {
 		return ""
 	}
-	return filepath.Join(home, ".claude", "settings.json")
+	return filepath.Join(dir, "settings.json")
 }
 
 // DefaultProjectSettingsPath returns the path to project-level Claude Code settings
@@ -74,6 +76,40 @@ func (s Settings) SetStatusLine(command string, force bool) error {
 	return nil
 }
 
+// StatusLineCommand returns the configured statusLine command, or "" if none
+func (s Settings) StatusLineCommand() string {
+	switch sl := s["statusLine"].(type) {
+	case map[string]interface{}:
+		cmd, _ := sl["command"].(string)
+		return cmd
+	case StatusLine:
+		return sl.Command
+	default:
+		return ""
+	}
+}
+
+// RemoveStatusLine deletes the statusLine configuration, reporting whether one existed
+func (s Settings) RemoveStatusLine() bool {
+	if !s.HasStatusLine() {
+		return false
+	}
+	delete(s, "statusLine")
+	return true
+}
+
+// Marshal returns the settings as indented JSON with a trailing newline,
+// exactly as SaveSettings writes them
+func (s Settings) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	// Add trailing newline
+	return append(data, '\n'), nil
+}
+
 // SaveSettings writes the settings to the given path
 // Creates parent directories if they don't exist
 func SaveSettings(path string, settings Settings) error {
@@ -82,17 +118,79 @@ func SaveSettings(path string, settings Settings) error {
 		return fmt.Errorf("failed to create settings directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	data, err := settings.Marshal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
+		return err
 	}
 
-	// Add trailing newline
-	data = append(data, '\n')
-
 	if err := os.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write settings: %w", err)
 	}
 
 	return nil
 }
+
+// DiffSettings returns a line diff between the settings file at path and the
+// given settings as SaveSettings would write them. Removed lines are prefixed
+// with "-", added lines with "+", and unchanged lines with a space.
+// Returns an empty string if nothing would change.
+func DiffSettings(path string, updated Settings) (string, error) {
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	after, err := updated.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	if string(before) == string(after) {
+		return "", nil
+	}
+
+	return diffLines(splitLines(string(before)), splitLines(string(after))), nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines renders a minimal line diff using the longest common subsequence
+func diffLines(a, b []string) string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return sb.String()
+}
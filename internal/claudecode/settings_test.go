@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +18,69 @@ func TestDefaultUserSettingsPath(t *testing.T) {
 	}
 }
 
+func TestDirFromEnv(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "claude-work")
+	t.Setenv(ConfigDirEnv, dir)
+	if got := Dir(); got != dir {
+		t.Errorf("Dir() = %q, want %q", got, dir)
+	}
+	if got, want := DefaultUserSettingsPath(), filepath.Join(dir, "settings.json"); got != want {
+		t.Errorf("DefaultUserSettingsPath() = %q, want %q", got, want)
+	}
+
+	t.Setenv(ConfigDirEnv, "")
+	if got := filepath.Base(Dir()); got != ".claude" {
+		t.Errorf("Dir() without %s ends in %q, want .claude", ConfigDirEnv, got)
+	}
+}
+
+func TestLocateWSL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USER", "alice")
+	t.Setenv("USERPROFILE", "")
+	t.Setenv(ConfigDirEnv, "")
+
+	root := t.TempDir()
+	osReleasePath = filepath.Join(root, "osrelease")
+	windowsUsersDir = filepath.Join(root, "Users")
+	t.Cleanup(func() {
+		osReleasePath = "/proc/sys/kernel/osrelease"
+		windowsUsersDir = "/mnt/c/Users"
+	})
+	win := filepath.Join(windowsUsersDir, "alice", ".claude")
+	if err := os.MkdirAll(win, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not WSL: always the Linux home
+	if dir, loc := Locate(); loc != LocationHome || dir != filepath.Join(home, ".claude") {
+		t.Errorf("outside WSL: Locate() = %q, %q", dir, loc)
+	}
+
+	if err := os.WriteFile(osReleasePath, []byte("5.15.153.1-microsoft-standard-WSL2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if dir, loc := Locate(); loc != LocationWindows || dir != win {
+		t.Errorf("WSL without ~/.claude: Locate() = %q, %q, want %q, windows", dir, loc, win)
+	}
+
+	// A Linux-side directory takes precedence
+	if err := os.Mkdir(filepath.Join(home, ".claude"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, loc := Locate(); loc != LocationHome {
+		t.Errorf("WSL with ~/.claude: location = %q, want home", loc)
+	}
+
+	if !OnWindowsDrive("/mnt/c/Users/alice/.claude/.credentials.json") {
+		t.Error("/mnt/c path should be on a Windows drive")
+	}
+	if OnWindowsDrive("/mnt/data/.claude") {
+		t.Error("/mnt/data isn't a Windows drive")
+	}
+}
+
 func TestDefaultProjectSettingsPath(t *testing.T) {
 	path := DefaultProjectSettingsPath()
 	if path != filepath.Join(".claude", "settings.json") {
@@ -250,3 +314,94 @@ func TestSaveSettings_CreatesDirectories(t *testing.T) {
 		t.Errorf("Settings file was not created: %v", err)
 	}
 }
+
+func TestDiffSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+
+	original := Settings{"theme": "dark"}
+	if err := SaveSettings(settingsPath, original); err != nil {
+		t.Fatalf("SaveSettings failed: %v", err)
+	}
+
+	// Unchanged settings produce no diff
+	diff, err := DiffSettings(settingsPath, original)
+	if err != nil {
+		t.Fatalf("DiffSettings failed: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("DiffSettings for unchanged settings = %q, want empty", diff)
+	}
+
+	updated := Settings{"theme": "dark"}
+	_ = updated.SetStatusLine("claude-limits statusline", false)
+
+	diff, err = DiffSettings(settingsPath, updated)
+	if err != nil {
+		t.Fatalf("DiffSettings failed: %v", err)
+	}
+	if !strings.Contains(diff, `+   "statusLine": {`) {
+		t.Errorf("diff should add statusLine, got:\n%s", diff)
+	}
+	if !strings.Contains(diff, "\n    \"theme\": \"dark\"\n") {
+		t.Errorf("diff should keep the theme line as context, got:\n%s", diff)
+	}
+}
+
+func TestDiffSettings_NewFile(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "missing.json")
+
+	diff, err := DiffSettings(settingsPath, Settings{"theme": "dark"})
+	if err != nil {
+		t.Fatalf("DiffSettings failed: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(diff), "\n") {
+		if !strings.HasPrefix(line, "+") {
+			t.Errorf("every line of a new file should be an addition, got %q", line)
+		}
+	}
+}
+
+func TestSettings_StatusLineCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+
+	settings := make(Settings)
+	if settings.StatusLineCommand() != "" {
+		t.Error("StatusLineCommand should be empty without statusLine")
+	}
+
+	_ = settings.SetStatusLine("/usr/bin/claude-limits statusline", false)
+	if got := settings.StatusLineCommand(); got != "/usr/bin/claude-limits statusline" {
+		t.Errorf("StatusLineCommand before save = %q", got)
+	}
+
+	// After a round trip the statusLine is a generic map
+	if err := SaveSettings(settingsPath, settings); err != nil {
+		t.Fatalf("SaveSettings failed: %v", err)
+	}
+	loaded, err := LoadSettings(settingsPath)
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if got := loaded.StatusLineCommand(); got != "/usr/bin/claude-limits statusline" {
+		t.Errorf("StatusLineCommand after load = %q", got)
+	}
+}
+
+func TestSettings_RemoveStatusLine(t *testing.T) {
+	settings := Settings{"statusLine": map[string]interface{}{"type": "command"}, "theme": "dark"}
+
+	if !settings.RemoveStatusLine() {
+		t.Error("RemoveStatusLine should report removal")
+	}
+	if settings.HasStatusLine() {
+		t.Error("statusLine should be gone")
+	}
+	if _, ok := settings["theme"]; !ok {
+		t.Error("RemoveStatusLine should preserve other fields")
+	}
+	if settings.RemoveStatusLine() {
+		t.Error("RemoveStatusLine on missing statusLine should report false")
+	}
+}
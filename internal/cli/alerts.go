@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/alerts"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Snooze or acknowledge threshold alerts",
+	Long: `Quiet the alerts sent by 'notify', 'watch --notify', and the daemon.
+
+The state is saved per profile next to the alert de-duplication state, so a
+running daemon respects it from its next poll.`,
+}
+
+var alertsSnoozeCmd = &cobra.Command{
+	Use:   "snooze <duration>",
+	Short: "Hold back every alert for a while",
+	Long: `Send no alerts, of any window or kind, for the given duration. Levels are
+still tracked while snoozed, so a threshold crossed during the snooze doesn't
+alert once it ends.
+
+Examples:
+  claude-limits alerts snooze 1h
+  claude-limits alerts snooze 90m --profile work`,
+	RunE: runAlertsSnooze,
+	Args: cobra.ExactArgs(1),
+}
+
+var alertsAckCmd = &cobra.Command{
+	Use:   "ack [window...]",
+	Short: "Acknowledge windows over a threshold until they reset",
+	Long: `Acknowledge windows (by name or short label) so they send no more threshold
+alerts until they reset, even if usage hovers around a threshold. Reset
+alerts are still sent. With no windows, every window last seen at warning or
+critical is acknowledged.
+
+Examples:
+  claude-limits alerts ack
+  claude-limits alerts ack 5h opus`,
+	RunE: runAlertsAck,
+}
+
+var alertsResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Clear the snooze and every acknowledgement",
+	RunE:  runAlertsResume,
+	Args:  cobra.NoArgs,
+}
+
+var alertsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether alerts are snoozed or acknowledged",
+	Long: `Show the snooze and acknowledged windows for the profile.
+
+Examples:
+  claude-limits alerts status
+  claude-limits alerts status --format json`,
+	RunE: runAlertsStatus,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	alertsCmd.AddCommand(alertsSnoozeCmd)
+	alertsCmd.AddCommand(alertsAckCmd)
+	alertsCmd.AddCommand(alertsResumeCmd)
+	alertsCmd.AddCommand(alertsStatusCmd)
+}
+
+// alertTracker loads the profile's alert state. Policies only matter to
+// Check, so the defaults do.
+func alertTracker() (*alerts.Tracker, error) {
+	profile, _, err := GetProfile()
+	if err != nil {
+		return nil, err
+	}
+	return alerts.NewTracker(alerts.StatePath(profile), alerts.DefaultPolicies()), nil
+}
+
+func runAlertsSnooze(cmd *cobra.Command, args []string) error {
+	d, err := time.ParseDuration(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", args[0], err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", d)
+	}
+
+	tracker, err := alertTracker()
+	if err != nil {
+		return err
+	}
+	until := time.Now().Add(d)
+	tracker.Snooze(until)
+	if err := tracker.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Alerts snoozed until %s\n", until.Local().Format("Jan 2 15:04"))
+	return nil
+}
+
+func runAlertsAck(cmd *cobra.Command, args []string) error {
+	tracker, err := alertTracker()
+	if err != nil {
+		return err
+	}
+	windows := make([]string, len(args))
+	for i, arg := range args {
+		windows[i] = format.WindowName(arg)
+	}
+
+	acked := tracker.Ack(windows...)
+	if len(acked) == 0 {
+		fmt.Fprintln(stdout, "No windows are over a threshold")
+		return nil
+	}
+	if err := tracker.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Acknowledged %s until reset\n", strings.Join(acked, ", "))
+	return nil
+}
+
+func runAlertsResume(cmd *cobra.Command, args []string) error {
+	tracker, err := alertTracker()
+	if err != nil {
+		return err
+	}
+	tracker.Resume()
+	if err := tracker.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "Alerts resumed")
+	return nil
+}
+
+// alertsStatus is the JSON shape of 'alerts status'
+type alertsStatus struct {
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	Acked        []string   `json:"acked"`
+}
+
+func runAlertsStatus(cmd *cobra.Command, args []string) error {
+	tracker, err := alertTracker()
+	if err != nil {
+		return err
+	}
+	var status alertsStatus
+	if tracker.Snoozed(time.Now()) {
+		until := tracker.SnoozedUntil()
+		status.SnoozedUntil = &until
+	}
+	status.Acked = tracker.Acked()
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	if status.SnoozedUntil != nil {
+		fmt.Fprintf(stdout, "Snoozed:      until %s (%s left)\n", status.SnoozedUntil.Local().Format("Jan 2 15:04"), format.Duration(time.Until(*status.SnoozedUntil)))
+	} else {
+		fmt.Fprintln(stdout, "Snoozed:      no")
+	}
+	if len(status.Acked) > 0 {
+		fmt.Fprintf(stdout, "Acknowledged: %s\n", strings.Join(status.Acked, ", "))
+	} else {
+		fmt.Fprintln(stdout, "Acknowledged: none")
+	}
+	return nil
+}
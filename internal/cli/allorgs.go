@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// allOrgs fetches every configured profile, one per organization
+var allOrgs bool
+
+// profileUsage is one profile's usage, or why it couldn't be fetched
+type profileUsage struct {
+	Profile      string
+	Organization string
+	Usage        *models.Usage
+	Err          error
+}
+
+// Label names the section, e.g. "Acme Corp (work)"
+func (o profileUsage) Label() string {
+	if o.Organization == "" {
+		return o.Profile
+	}
+	return fmt.Sprintf("%s (%s)", o.Organization, o.Profile)
+}
+
+// orgJSON is one profile's entry in the merged --all-orgs JSON document
+type orgJSON struct {
+	Organization string          `json:"organization,omitempty"`
+	Usage        json.RawMessage `json:"usage,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// fetchAllOrgs fetches every configured profile's usage concurrently,
+// in profile name order
+func fetchAllOrgs(ctx context.Context) ([]profileUsage, error) {
+	if len(cfg.Profiles) == 0 {
+		return nil, fmt.Errorf("--all-orgs needs profiles in the config file, one per organization (see 'claude-limits config init')")
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return fetchProfiles(ctx, names), nil
+}
+
+// fetchProfiles fetches the named profiles' usage concurrently, in the
+// order given
+func fetchProfiles(ctx context.Context, names []string) []profileUsage {
+	results := make([]profileUsage, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := profileUsage{Profile: name}
+			name, settings, err := cfg.ResolveProfile(name)
+			if err == nil {
+				result.Organization = settings.Organization
+				result.Usage, err = fetchProfileUsage(ctx, name, settings, false)
+			}
+			result.Err = err
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runAllOrgs renders every organization's usage: a section each in table
+// and compact output, or one JSON document keyed by profile. --fields,
+// --exclude, and --fail-at apply to each organization.
+func runAllOrgs(ctx context.Context) error {
+	outputFormat := GetOutputFormat()
+	switch outputFormat {
+	case "table", "json", "compact":
+	default:
+		return fmt.Errorf("--all-orgs supports table, json, and compact output")
+	}
+
+	limits, err := failAtLimits()
+	if err != nil {
+		return err
+	}
+	orgs, err := fetchAllOrgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range orgs {
+		org := &orgs[i]
+		if org.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", org.Label(), org.Err))
+			continue
+		}
+		if org.Usage, err = org.Usage.Select(fieldPaths(fields), fieldPaths(excludeFields)); err != nil {
+			return err
+		}
+	}
+
+	switch outputFormat {
+	case "json":
+		doc := make(map[string]orgJSON, len(orgs))
+		for _, org := range orgs {
+			entry := orgJSON{Organization: org.Organization}
+			if org.Err != nil {
+				entry.Error = org.Err.Error()
+			} else {
+				entry.Usage = org.Usage.Raw
+			}
+			doc[org.Profile] = entry
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	case "compact":
+		for _, org := range orgs {
+			fmt.Fprintf(stdout, "%s: ", org.Label())
+			if org.Err != nil {
+				fmt.Fprintf(stdout, "error: %v\n", org.Err)
+				continue
+			}
+			if err := printCompact(org.Usage); err != nil {
+				return err
+			}
+		}
+	default:
+		colors := outputColors()
+		for i, org := range orgs {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprintf(stdout, "%s%s%s\n", colors.Bold, org.Label(), colors.Reset)
+			if org.Err != nil {
+				fmt.Fprintf(stdout, "error: %v\n", org.Err)
+				continue
+			}
+			if err := printTable(org.Usage); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, org := range orgs {
+		if err := checkFailAt(org.Usage, limits); err != nil {
+			return fmt.Errorf("%s: %w", org.Label(), err)
+		}
+	}
+	return nil
+}
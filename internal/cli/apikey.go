@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/transcripts"
+)
+
+// Authentication modes for --auth-mode
+const (
+	authModeOAuth  = "oauth"
+	authModeAPIKey = "api-key"
+)
+
+// Environment variables for API key authentication
+const (
+	authModeEnv = "CLAUDE_LIMITS_AUTH_MODE"
+	apiKeyEnv   = "ANTHROPIC_API_KEY"
+)
+
+var authModeFlag string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&authModeFlag, "auth-mode", "", "Authentication: oauth (Claude.ai subscription) or api-key (Admin API, from "+apiKeyEnv+") (env: "+authModeEnv+")")
+}
+
+// authMode resolves --auth-mode, then CLAUDE_LIMITS_AUTH_MODE, defaulting
+// to OAuth
+func authMode() (string, error) {
+	mode := authModeFlag
+	if mode == "" {
+		mode = os.Getenv(authModeEnv)
+	}
+	switch mode {
+	case "", authModeOAuth:
+		return authModeOAuth, nil
+	case authModeAPIKey:
+		return authModeAPIKey, nil
+	}
+	return "", fmt.Errorf("invalid auth mode %q (use %s or %s)", mode, authModeOAuth, authModeAPIKey)
+}
+
+// newAPIKeyClient returns a client authenticated with ANTHROPIC_API_KEY,
+// which must be an admin key for the usage report
+func newAPIKeyClient() (*api.Client, error) {
+	key := os.Getenv(apiKeyEnv)
+	if key == "" && !replaying() {
+		return nil, fmt.Errorf("--auth-mode %s needs an admin API key in %s", authModeAPIKey, apiKeyEnv)
+	}
+	opts, err := apiClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient("", append(opts, api.WithAPIKey(key))...), nil
+}
+
+// apiKeyUsage is the JSON shape of limits in API key mode: the
+// organization's token usage over the same windows as subscription limits
+type apiKeyUsage struct {
+	FiveHour transcripts.Tokens `json:"five_hour"`
+	SevenDay transcripts.Tokens `json:"seven_day"`
+}
+
+// runAPIKeyLimits shows the organization's token usage from the Admin API.
+// API-platform limits are rates rather than quotas, so there's no
+// utilization to report, only what was used.
+func runAPIKeyLimits(ctx context.Context) error {
+	client, err := newAPIKeyClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	buckets, err := client.GetUsageReport(ctx, api.UsageReportQuery{
+		Start:       now.Add(-7 * 24 * time.Hour).Truncate(time.Hour),
+		BucketWidth: "1h",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get usage report: %w", err)
+	}
+
+	var usage apiKeyUsage
+	fiveHoursAgo := now.Add(-5 * time.Hour)
+	for _, b := range buckets {
+		for _, r := range b.Results {
+			t := reportTokens(r)
+			usage.SevenDay = addTokens(usage.SevenDay, t)
+			if b.EndingAt.After(fiveHoursAgo) {
+				usage.FiveHour = addTokens(usage.FiveHour, t)
+			}
+		}
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	colors := outputColors()
+	fmt.Fprintf(stdout, "%sOrganization token usage (API key)%s\n\n", colors.Bold, colors.Reset)
+	const row = "%-16s %8s %8s %8s %8s %8s\n"
+	fmt.Fprintf(stdout, row, "WINDOW", "INPUT", "OUTPUT", "CACHE+", "CACHED", "TOTAL")
+	printTokenRow(row, "Last 5 hours", usage.FiveHour)
+	printTokenRow(row, "Last 7 days", usage.SevenDay)
+	return nil
+}
+
+// reportTokens converts a usage report result to token counts
+func reportTokens(r models.UsageReportResult) transcripts.Tokens {
+	return transcripts.Tokens{
+		Input:         r.UncachedInputTokens,
+		Output:        r.OutputTokens,
+		CacheCreation: r.CacheCreationInputTokens(),
+		CacheRead:     r.CacheReadInputTokens,
+	}
+}
+
+func addTokens(a, b transcripts.Tokens) transcripts.Tokens {
+	return transcripts.Tokens{
+		Input:         a.Input + b.Input,
+		Output:        a.Output + b.Output,
+		CacheCreation: a.CacheCreation + b.CacheCreation,
+		CacheRead:     a.CacheRead + b.CacheRead,
+	}
+}
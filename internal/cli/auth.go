@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect and test authentication",
+	Long: `Inspect, test, and clear the credentials claude-limits uses.
+
+claude-limits reads the OAuth credentials Claude Code stores in
+~/.claude/.credentials.json (or a profile's credentials path). Claude Code
+owns that file and refreshes the token when it runs; sign in or out with
+/login and /logout inside Claude Code.`,
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which credentials are used and when they expire",
+	Long: `Show the resolved profile, credentials file, subscription tier, and token
+expiry. No request is made; use 'auth test' to check the token works.
+
+Examples:
+  claude-limits auth status
+  claude-limits auth status --profile work --format json`,
+	RunE: runAuthStatus,
+	Args: cobra.NoArgs,
+}
+
+var authTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Make a live request to check the credentials work",
+	Long: `Fetch usage once, bypassing the cache, and report whether the API accepted
+the token. Exits non-zero on failure.
+
+Examples:
+  claude-limits auth test
+  claude-limits auth test --profile work`,
+	RunE: runAuthTest,
+	Args: cobra.NoArgs,
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Clear cached usage data for the profile",
+	Long: `Delete the profile's cached usage so no account data is served after
+switching accounts.
+
+The credentials file belongs to Claude Code and is left alone; run /logout
+in Claude Code to sign out.
+
+Examples:
+  claude-limits auth logout
+  claude-limits auth logout --profile work`,
+	RunE: runAuthLogout,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authTestCmd)
+	authCmd.AddCommand(authLogoutCmd)
+}
+
+// authStatus is the JSON shape of 'auth status'
+type authStatus struct {
+	Profile         string `json:"profile"`
+	CredentialsPath string `json:"credentials_path,omitempty"`
+	// CredentialCommand is set instead of CredentialsPath when the token
+	// comes from a command
+	CredentialCommand string    `json:"credential_command,omitempty"`
+	Subscription      string    `json:"subscription,omitempty"`
+	RateLimitTier     string    `json:"rate_limit_tier,omitempty"`
+	Scopes            []string  `json:"scopes,omitempty"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	Expired           bool      `json:"expired"`
+	HasRefreshToken   bool      `json:"has_refresh_token"`
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	name, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+
+	status := authStatus{
+		Profile:           displayProfile(name),
+		CredentialCommand: profile.CredentialCommand,
+		Subscription:      creds.SubscriptionType,
+		RateLimitTier:     creds.RateLimitTier,
+		Scopes:            creds.Scopes,
+		ExpiresAt:         creds.ExpiresAt,
+		Expired:           creds.IsExpired(),
+		HasRefreshToken:   creds.RefreshToken != "",
+	}
+
+	if status.CredentialCommand == "" {
+		status.CredentialsPath = credentialsPath(profile)
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "Profile:       %s\n", status.Profile)
+	if status.CredentialCommand != "" {
+		fmt.Fprintf(stdout, "Credentials:   %s\n", credentialsSource(profile))
+	} else {
+		fmt.Fprintf(stdout, "Credentials:   %s (Claude Code OAuth)\n", status.CredentialsPath)
+	}
+	fmt.Fprintf(stdout, "Subscription:  %s\n", valueOrUnknown(status.Subscription))
+	if status.RateLimitTier != "" {
+		fmt.Fprintf(stdout, "Rate limit:    %s\n", status.RateLimitTier)
+	}
+	if len(status.Scopes) > 0 {
+		fmt.Fprintf(stdout, "Scopes:        %s\n", strings.Join(status.Scopes, ", "))
+	}
+
+	expires := creds.ExpiresAt.Local().Format(GetFormats().Datetime)
+	if !creds.HasExpiry() {
+		fmt.Fprintln(stdout, "Token expires: unknown")
+	} else if status.Expired {
+		fmt.Fprintf(stdout, "Token expires: %s (expired %s ago)\n", expires, format.Duration(time.Since(creds.ExpiresAt)))
+		if status.HasRefreshToken {
+			fmt.Fprintln(stdout, "\nThe access token has expired. Start Claude Code to refresh it.")
+		}
+	} else {
+		fmt.Fprintf(stdout, "Token expires: %s (in %s)\n", expires, format.Duration(time.Until(creds.ExpiresAt)))
+	}
+	return nil
+}
+
+func runAuthTest(cmd *cobra.Command, args []string) error {
+	_, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient(creds.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	usage, err := client.GetUsageContext(cmd.Context())
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		if apierrors.Is(err, apierrors.ErrAuthRequired) || apierrors.Is(err, apierrors.ErrPermissionDenied) {
+			return fmt.Errorf("token rejected: %w\nStart Claude Code to refresh the token, or run /login in Claude Code", err)
+		}
+		return fmt.Errorf("request failed after %s: %w", elapsed, err)
+	}
+
+	fmt.Fprintf(stdout, "OK: usage API accepted the token (%s)\n", elapsed)
+	fmt.Fprintln(stdout, format.Compact(usage, format.NewColors(true), tableFormats(), format.CompactOptions{HideResets: true}))
+	return nil
+}
+
+func runAuthLogout(cmd *cobra.Command, args []string) error {
+	name, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	c := cache.New(IsVerbose(), cache.WithProfile(name))
+	if err := c.Clear(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Cleared cached usage for profile %s (%s)\n", displayProfile(name), c.File())
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Credentials are managed by Claude Code; run /logout in Claude Code to sign out.")
+	}
+	return nil
+}
+
+// displayProfile names the profile for messages, where "" is the default
+func displayProfile(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	badgeOut     string
+	badgeLabel   string
+	badgeWindows []string
+	badgeScale   int
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Generate an SVG or PNG badge of current utilization",
+	Long: `Write a shields-style badge of current utilization, e.g. "claude | 5h 45% | wk 23%",
+colored green, yellow, or red by the highest threshold level among its
+windows. Run it from cron to embed live-ish usage in dashboards and READMEs.
+
+The badge is SVG unless --out ends in .png. Files are replaced atomically,
+so a web server never serves a half-written badge.
+
+Examples:
+  claude-limits badge --out badge.svg
+  claude-limits badge --out badge.png --windows 5h
+  */10 * * * * claude-limits badge --quiet --out /var/www/claude.svg`,
+	RunE: runBadge,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	badgeCmd.Flags().StringVarP(&badgeOut, "out", "o", "-", "File to write, PNG if it ends in .png (- for SVG on standard output)")
+	badgeCmd.Flags().StringVar(&badgeLabel, "label", "claude", "Text on the left of the badge")
+	badgeCmd.Flags().StringSliceVar(&badgeWindows, "windows", []string{"5h", "wk"}, "Windows to show, by name or short label")
+	badgeCmd.Flags().IntVar(&badgeScale, "scale", 2, "PNG pixels per badge pixel")
+}
+
+func runBadge(cmd *cobra.Command, args []string) error {
+	if badgeScale < 1 {
+		return fmt.Errorf("--scale must be at least 1, got %d", badgeScale)
+	}
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	windows := make([]string, len(badgeWindows))
+	for i, w := range badgeWindows {
+		windows[i] = format.WindowName(w)
+	}
+	badge := format.NewBadge(usage, badgeLabel, windows, shareThresholds())
+
+	if badgeOut == "-" {
+		_, err := fmt.Fprint(stdout, badge.SVG())
+		return err
+	}
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(badgeOut), ".png") {
+		if err := badge.WritePNG(&buf, badgeScale); err != nil {
+			return err
+		}
+	} else {
+		buf.WriteString(badge.SVG())
+	}
+	// Unlike usage data, the badge is readable by all, for web servers
+	if err := cache.WriteAtomic(badgeOut, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/budget"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	budgetTasks  int
+	budgetWindow string
+)
+
+// budgetHistory is how much history sets the pace for budgets
+const budgetHistory = 7 * 24 * time.Hour
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Split the remaining weekly capacity into per-task budgets",
+	Long: `Divide what's left of the weekly limit evenly across the tasks you plan to
+do before it resets, and size each task against your recent pace.
+
+With usage history (recorded on each fetch; run the daemon for a steady
+record), the last week sets the pace: how many hours of work a task's share
+lasts, and the 5-hour limit utilization at which a session has spent it.
+Stop a session there to keep the rest of the week's tasks funded.
+
+Examples:
+  claude-limits budget --tasks 8
+  claude-limits budget --tasks 3 --window opus
+  claude-limits budget --tasks 8 --format json`,
+	RunE: runBudget,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	budgetCmd.Flags().IntVarP(&budgetTasks, "tasks", "t", 1, "Number of tasks to split the remaining capacity across")
+	budgetCmd.Flags().StringVarP(&budgetWindow, "window", "w", models.WindowSevenDay, "Window to budget, by name or short label (wk, opus, sonnet)")
+}
+
+func runBudget(cmd *cobra.Command, args []string) error {
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	plan, err := budget.New(usage, format.WindowName(budgetWindow), budgetTasks, recentBurns(profile))
+	if err != nil {
+		return err
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	printBudget(plan)
+	return nil
+}
+
+// recentBurns returns each window's consumption over budgetHistory. The pace
+// is a refinement, so failures just yield none.
+func recentBurns(profile string) map[string]history.Burn {
+	snapshots, err := querySnapshots(profile, time.Now().Add(-budgetHistory), time.Time{})
+	if err != nil {
+		slog.Debug("failed to read history", "error", err)
+		return nil
+	}
+	return history.Burns(snapshots)
+}
+
+func printBudget(plan budget.Plan) {
+	colors := outputColors()
+	name := format.FormatKey(plan.Window)
+
+	resets := ""
+	if plan.ResetsAt != nil {
+		fmts := tableFormats()
+		resets = ", resets " + format.ResetTime(*plan.ResetsAt, fmts.Datetime, fmts)
+	}
+	fmt.Fprintf(stdout, "%s%s limit:%s %.0f%% used, %.0f%% left%s\n\n", colors.Bold, name, colors.Reset, plan.Used, plan.Remaining, resets)
+
+	if plan.Remaining <= 0 {
+		fmt.Fprintln(stdout, "Nothing left to budget until the limit resets.")
+		return
+	}
+
+	tasks := "1 task"
+	if plan.Tasks > 1 {
+		tasks = fmt.Sprintf("%d tasks", plan.Tasks)
+	}
+	fmt.Fprintf(stdout, "Per task:     %.1f%% of the %s limit (%s)\n", plan.PerTask, name, tasks)
+	if plan.BurnRate == 0 {
+		fmt.Fprintln(stdout, "\nNot enough usage history from the last week to set a pace; run the daemon to record it.")
+		return
+	}
+	fmt.Fprintf(stdout, "Recent pace:  %.1f%% per active hour, so a task lasts about %s of work\n",
+		plan.BurnRate, format.Duration(time.Duration(plan.TaskHours*float64(time.Hour))))
+
+	if plan.SessionLimit == 0 {
+		return
+	}
+	if plan.Sessions <= 1 {
+		fmt.Fprintf(stdout, "Per session:  stop each task at %.0f%% of the 5-hour limit\n", plan.SessionLimit)
+	} else {
+		fmt.Fprintf(stdout, "Per session:  a task spans about %.1f full 5-hour sessions\n", plan.Sessions)
+	}
+}
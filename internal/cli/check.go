@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/benjaminabbitt/claude-limits/internal/alerts"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check usage as a Nagios/Icinga monitoring plugin",
+	Long: `Check usage against warning and critical thresholds and report the result
+in the standard monitoring plugin format, for Nagios, Icinga, Naemon, Sensu,
+and other systems that run check plugins.
+
+Prints one line with the state, a summary, and performance data for every
+window, and exits with the matching plugin code:
+
+  0  OK        every window is below the warning threshold
+  1  WARNING   a window reached the warning threshold
+  2  CRITICAL  a window reached the critical threshold
+  3  UNKNOWN   usage couldn't be fetched
+
+Thresholds default to 'alerts.warning' and 'alerts.critical' from config,
+then 80 and 95.
+
+Examples:
+  claude-limits check
+  claude-limits check --warning 70 --critical 90
+  claude-limits check --cache 0    # always query the API`,
+	RunE: runCheck,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	addThresholdFlags(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	thresholds := alertThresholds(cmd)
+	if thresholds.Warning > thresholds.Critical {
+		fmt.Fprintln(stdout, alerts.CheckUnknown(fmt.Errorf("--warning %.0f is above --critical %.0f", thresholds.Warning, thresholds.Critical)))
+		return &apierrors.StatusError{Code: alerts.PluginUnknown}
+	}
+
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		fmt.Fprintln(stdout, alerts.CheckUnknown(err))
+		return &apierrors.StatusError{Code: alerts.PluginUnknown}
+	}
+
+	result := alerts.Check(usage, thresholds)
+	fmt.Fprintln(stdout, result.Output)
+	if code := result.Code(); code != alerts.PluginOK {
+		return &apierrors.StatusError{Code: code}
+	}
+	return nil
+}
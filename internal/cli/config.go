@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configInitForce bool
+	configInitPrint bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create and check the config file",
+	Long: `Create and check the config file.
+
+The config file is read from --config, CLAUDE_LIMITS_CONFIG, or
+~/.config/claude-limits/config.yaml (%APPDATA%\claude-limits\config.yaml
+on Windows). Mistakes in it are otherwise ignored silently; run
+'config validate' after editing.
+
+Every key can also be set by an environment variable named CLAUDE_LIMITS_
+plus the key's path in upper case, e.g. CLAUDE_LIMITS_FORMATS_PRESET=24hour
+or CLAUDE_LIMITS_CACHE_TTL=120. Variables override the file. Lists, maps,
+and whole sections take YAML, e.g.
+CLAUDE_LIMITS_COMPACT_WINDOWS='[five_hour, seven_day]'.`,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented config file listing every setting",
+	Long: `Write a commented config file listing every setting and its default.
+
+Refuses to replace an existing file unless --force is given.
+
+Examples:
+  claude-limits config init
+  claude-limits config init --config ./config.yaml
+  claude-limits config init --print > config.yaml`,
+	RunE: runConfigInit,
+	Args: cobra.NoArgs,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Report unknown keys, bad presets, and invalid time layouts",
+	Long: `Check the config file for unknown keys (typos), values of the wrong type,
+unknown format presets, invalid time layouts, and inconsistent settings such
+as a default_profile that isn't defined. Problems are reported with line
+numbers, and the command exits non-zero if there are any. CLAUDE_LIMITS_*
+variables overriding config keys are checked too.
+
+Examples:
+  claude-limits config validate
+  claude-limits config validate --config ./config.yaml`,
+	RunE: runConfigValidate,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "Replace an existing config file")
+	configInitCmd.Flags().BoolVar(&configInitPrint, "print", false, "Print the config to stdout instead of writing it")
+
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	if configInitPrint {
+		_, err := stdout.Write(config.Template)
+		return err
+	}
+
+	path := config.ResolvePath(configPath)
+	if _, err := os.Stat(path); err == nil && !configInitForce {
+		return fmt.Errorf("config file already exists at %s\nUse --force to replace it", path)
+	}
+
+	// The config may hold webhook URLs, which are secrets
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, config.Template, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "Wrote %s\n", path)
+	return nil
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	envProblems := config.EnvProblems()
+	for _, p := range envProblems {
+		fmt.Fprintf(stdout, "environment: %s\n", p.Message)
+	}
+
+	path := config.ResolvePath(configPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if len(envProblems) > 0 {
+				return fmt.Errorf("%d problem(s) in the environment", len(envProblems))
+			}
+			return fmt.Errorf("no config file at %s\nRun 'claude-limits config init' to create one", path)
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	problems, err := config.Validate(data)
+	if err != nil {
+		return fmt.Errorf("%s: invalid YAML: %w", path, err)
+	}
+	if len(problems) == 0 {
+		if len(envProblems) > 0 {
+			return fmt.Errorf("%d problem(s) in the environment", len(envProblems))
+		}
+		fmt.Fprintf(stdout, "%s: OK\n", path)
+		return nil
+	}
+
+	// path:line: message, the form editors and CI annotate
+	for _, p := range problems {
+		if p.Line > 0 {
+			fmt.Fprintf(stdout, "%s:%d: %s\n", path, p.Line, p.Message)
+		} else {
+			fmt.Fprintf(stdout, "%s: %s\n", path, p.Message)
+		}
+	}
+	return fmt.Errorf("%d problem(s) in %s", len(problems)+len(envProblems), path)
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+)
+
+// loadCredentials loads the profile's OAuth credentials from its
+// credential_command or credentials file, or none when replaying, since a
+// fixture needs no token. Credentials files others can read are warned
+// about (see checkPermissions).
+func loadCredentials(ctx context.Context, profile config.Profile) (*auth.Credentials, error) {
+	if replaying() {
+		return &auth.Credentials{}, nil
+	}
+	if profile.CredentialCommand != "" {
+		return auth.FromCommand(ctx, profile.CredentialCommand)
+	}
+	path := credentialsPath(profile)
+	if err := checkPermissions(path); err != nil {
+		return nil, err
+	}
+	return auth.Load(path)
+}
+
+// credentialsPath is the profile's credentials file, or Claude Code's
+// default one
+func credentialsPath(profile config.Profile) string {
+	if profile.Credentials != "" {
+		return profile.Credentials
+	}
+	return auth.DefaultCredentialsPath()
+}
+
+// credentialsSource describes where the profile's credentials come from,
+// for display
+func credentialsSource(profile config.Profile) string {
+	if profile.CredentialCommand != "" {
+		return "`" + profile.CredentialCommand + "` (credential command)"
+	}
+	return credentialsPath(profile)
+}
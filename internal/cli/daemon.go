@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonInterval int
+	daemonNoAlerts bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Poll usage in the background, keeping cache and history fresh",
+	Long: `Run persistently, fetching usage on an interval.
+
+Each poll bypasses the cache, then writes the result to the cache, records it
+to history, and dispatches threshold alerts (desktop notifications and
+configured webhooks, see 'claude-limits notify').
+
+Other consumers — the status line script, tmux, MCP clients — can then read
+the cache without each invocation hitting the API. Give them a cache TTL at
+least as long as the daemon interval, e.g. 'claude-limits --cache 120'.
+
+The config file is reloaded when it changes, so edits to thresholds,
+webhooks, daemon.interval, and API settings apply from the next poll
+without a restart. Flags given on the command line keep precedence.
+
+Stops on SIGINT or SIGTERM.
+
+Examples:
+  claude-limits daemon
+  claude-limits daemon --interval 120 --no-alerts`,
+	RunE: runDaemon,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	daemonCmd.Flags().IntVarP(&daemonInterval, "interval", "i", 60, "Poll interval in seconds (config: daemon.interval)")
+	daemonCmd.Flags().BoolVar(&daemonNoAlerts, "no-alerts", false, "Don't dispatch threshold alerts")
+	daemonCmd.Flags().BoolVar(&noDesktop, "no-desktop", false, "Only deliver alerts to configured webhooks")
+	addThresholdFlags(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	if daemonInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", daemonInterval)
+	}
+
+	ctx := cmd.Context()
+	interval := daemonPollInterval()
+	reloads := watchConfig(ctx)
+
+	slog.Info("daemon started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pollOnce(cmd)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("daemon stopped")
+			return nil
+		case <-ticker.C:
+			pollOnce(cmd)
+		case c := <-reloads:
+			// Applied between polls, so a poll never sees a half-applied config
+			applyConfig(c)
+			if next := daemonPollInterval(); next != interval {
+				interval = next
+				ticker.Reset(interval)
+				slog.Info("poll interval changed", "interval", interval)
+			}
+		}
+	}
+}
+
+// daemonPollInterval resolves the poll interval from --interval, then the
+// config's daemon.interval
+func daemonPollInterval() time.Duration {
+	if !flagChanged("interval") && cfg != nil && cfg.Daemon.Interval > 0 {
+		return cfg.Daemon.Interval
+	}
+	return time.Duration(daemonInterval) * time.Second
+}
+
+// pollOnce performs a single fetch/record/alert cycle. Errors are logged and
+// the daemon keeps running so transient failures don't need a restart.
+func pollOnce(cmd *cobra.Command) {
+	ctx, span := otel.Start(cmd.Context(), "claude_limits.poll")
+	var err error
+	defer func() {
+		span.End(err)
+		FlushTelemetry()
+	}()
+
+	usage, err := fetchUsage(ctx, true)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("poll failed", "error", err)
+			RecordError(err)
+		}
+		return
+	}
+
+	slog.Debug("poll ok")
+	recordUsageMetrics(usage)
+
+	if daemonNoAlerts {
+		return
+	}
+	if err = checkAlerts(cmd, usage); err != nil {
+		slog.Error("alert delivery failed", "error", err)
+	}
+}
+
+// recordUsageMetrics records each window's utilization and time to reset as
+// OpenTelemetry gauges
+func recordUsageMetrics(usage *models.Usage) {
+	for _, w := range usage.Windows() {
+		window := telemetry.Attr{Key: "window", Value: w.Name}
+		otel.Gauge("claude_limits.utilization", "%", "Utilization of a usage window", w.Utilization, window)
+		if w.ResetsAt != nil {
+			otel.Gauge("claude_limits.resets_in", "s", "Time until a usage window resets", w.ResetsIn(time.Now()).Seconds(), window)
+		}
+	}
+}
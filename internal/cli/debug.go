@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/debugdump"
+	"github.com/benjaminabbitt/claude-limits/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+var debugDumpOutput string
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Collect diagnostics for bug reports",
+}
+
+var debugDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Write a redacted diagnostic bundle to attach to an issue",
+	Long: `Write a gzipped tarball with what's needed to diagnose a problem:
+
+  system.json   version, OS, and the paths in use
+  config.yaml   the config file, with webhook and proxy URLs masked
+  usage.json    the current usage, with every string but timestamps masked
+  cache.json    the cache entry's age and validators
+  errors.log    the last few errors claude-limits reported
+
+No tokens are included. Review the bundle before sharing it anyway.
+
+Examples:
+  claude-limits debug dump
+  claude-limits debug dump -o bundle.tar.gz
+  claude-limits debug dump -o - | tar -tzv`,
+	RunE: runDebugDump,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	debugDumpCmd.Flags().StringVarP(&debugDumpOutput, "output", "o", "", "Bundle path, or - for stdout (default: claude-limits-debug-<time>.tar.gz)")
+
+	debugCmd.AddCommand(debugDumpCmd)
+}
+
+// debugSystem is the JSON shape of a bundle's system.json
+type debugSystem struct {
+	Version         string    `json:"version"`
+	GoVersion       string    `json:"go_version"`
+	OS              string    `json:"os"`
+	Arch            string    `json:"arch"`
+	Profile         string    `json:"profile"`
+	ConfigPath      string    `json:"config_path"`
+	CredentialsPath string    `json:"credentials_path"`
+	CacheDir        string    `json:"cache_dir"`
+	StateDir        string    `json:"state_dir"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// debugCache is the JSON shape of a bundle's cache.json
+type debugCache struct {
+	Path            string     `json:"path"`
+	Exists          bool       `json:"exists"`
+	Error           string     `json:"error,omitempty"`
+	Timestamp       *time.Time `json:"timestamp,omitempty"`
+	Age             string     `json:"age,omitempty"`
+	HasETag         bool       `json:"has_etag"`
+	HasLastModified bool       `json:"has_last_modified"`
+}
+
+func runDebugDump(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	name, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	credentials := profile.Credentials
+	if credentials == "" {
+		credentials = auth.DefaultCredentialsPath()
+	}
+	c := cache.New(false, cache.WithProfile(name))
+
+	var files []debugdump.File
+	add := func(name string, data []byte) {
+		files = append(files, debugdump.File{Name: name, Data: data})
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		add(name, append(data, '\n'))
+		return nil
+	}
+
+	if err := addJSON("system.json", debugSystem{
+		Version:         version.Version,
+		GoVersion:       runtime.Version(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Profile:         displayProfile(name),
+		ConfigPath:      config.ResolvePath(configPath),
+		CredentialsPath: credentials,
+		CacheDir:        c.Dir(),
+		StateDir:        cache.StateDir(),
+		CreatedAt:       now,
+	}); err != nil {
+		return err
+	}
+
+	add("config.yaml", debugConfig())
+
+	if usage, err := getUsageWithCache(cmd.Context()); err != nil {
+		add("usage.json", []byte(fmt.Sprintf("# failed to get usage: %v\n", err)))
+	} else if data, err := debugdump.SanitizeJSON(usage.Raw); err != nil {
+		add("usage.json", []byte(fmt.Sprintf("# %v\n", err)))
+	} else {
+		add("usage.json", append(data, '\n'))
+	}
+
+	if err := addJSON("cache.json", debugCacheInfo(c, now)); err != nil {
+		return err
+	}
+
+	errorLog, err := os.ReadFile(debugdump.ErrorLogPath())
+	if err != nil && !os.IsNotExist(err) {
+		errorLog = []byte(fmt.Sprintf("# %v\n", err))
+	}
+	add("errors.log", errorLog)
+
+	return writeDebugBundle(files, now)
+}
+
+// debugConfig returns the redacted config file, or a note on why it's missing
+func debugConfig() []byte {
+	path := config.ResolvePath(configPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []byte("# no config file at " + path + "\n")
+	}
+	if err != nil {
+		return []byte(fmt.Sprintf("# %v\n", err))
+	}
+	redacted, err := debugdump.RedactConfig(data)
+	if err != nil {
+		// Unparsable configs can't be redacted safely, so leave them out
+		return []byte(fmt.Sprintf("# config omitted: %v\n", err))
+	}
+	return redacted
+}
+
+func debugCacheInfo(c *cache.Cache, now time.Time) debugCache {
+	info := debugCache{Path: c.File()}
+	if _, err := os.Stat(c.File()); err != nil {
+		if !os.IsNotExist(err) {
+			info.Error = err.Error()
+		}
+		return info
+	}
+	info.Exists = true
+
+	data, err := c.ReadStale()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Timestamp = &data.Timestamp
+	info.Age = now.Sub(data.Timestamp).Round(time.Second).String()
+	info.HasETag = data.ETag != ""
+	info.HasLastModified = data.LastModified != ""
+	return info
+}
+
+func writeDebugBundle(files []debugdump.File, now time.Time) error {
+	if debugDumpOutput == "-" {
+		return debugdump.Write(stdout, files, now)
+	}
+
+	path := debugDumpOutput
+	if path == "" {
+		path = "claude-limits-debug-" + now.Format("20060102-150405") + ".tar.gz"
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cache.FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	if err := debugdump.Write(f, files, now); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "Wrote debug bundle to %s\n", path)
+	return nil
+}
+
+// RecordError saves err to the recent errors log included in debug bundles
+func RecordError(err error) {
+	debugdump.RecordError(debugdump.ErrorLogPath(), err, time.Now())
+}
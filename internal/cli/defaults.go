@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// applyConfigDefaults fills --format, --no-color, and --cache from the
+// config's output and cache sections when they weren't given. Flags
+// override config, which overrides the built-in defaults; with --verbose,
+// each setting's resolution chain is logged. It runs again when the config
+// is reloaded.
+func applyConfigDefaults() {
+	out := cfg.Output
+	var format *string
+	if out.Format != "" {
+		format = &out.Format
+	}
+	resolveSetting("format", &outputFormat, "output.format", format)
+	resolveSetting("no-color", &noColor, "output.no_color", out.NoColor)
+	resolveSetting("cache", &cacheTTL, "cache.ttl", cfg.Cache.TTL)
+}
+
+// resolveSetting sets *v from conf unless the flag was given, or back to
+// the flag's default if neither was, and logs which source won, e.g.
+//
+//	format=json: flag --format=json > config output.format=compact > default table
+func resolveSetting[T any](flag string, v *T, key string, conf *T) {
+	var chain []string
+	from := "default"
+	if flagChanged(flag) {
+		chain = append(chain, fmt.Sprintf("flag --%s=%v", flag, *v))
+		from = "flag"
+	}
+	if conf != nil {
+		chain = append(chain, fmt.Sprintf("config %s=%v", key, *conf))
+		if from == "default" {
+			*v = *conf
+			from = "config"
+		}
+	}
+	if f := activeCmd.Flags().Lookup(flag); f != nil {
+		chain = append(chain, "default "+f.DefValue)
+		if from == "default" {
+			// Value.Set, unlike FlagSet.Set, leaves the flag unchanged
+			_ = f.Value.Set(f.DefValue)
+		}
+	}
+	slog.Debug(fmt.Sprintf("%s=%v: %s", flag, *v, strings.Join(chain, " > ")), "from", from)
+}
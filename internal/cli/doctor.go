@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	strictPerms bool
+	doctorFix   bool
+)
+
+// fixPermsHint suggests how to make a secrets file private
+const fixPermsHint = "Run 'claude-limits doctor --fix' or 'chmod 600' the file"
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that credentials and config secrets are private",
+	Long: `Check the permissions of every credentials file claude-limits reads, and
+of the config file when it holds secrets (webhook URLs or a proxy password).
+Files that other users can read are reported; --fix makes them private
+(mode 0600).
+
+Other commands warn when they read such a file, or fail with --strict-perms.
+
+Examples:
+  claude-limits doctor
+  claude-limits doctor --fix`,
+	RunE: runDoctor,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&strictPerms, "strict-perms", false, "Fail instead of warning when credentials or config secrets are readable by other users")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Remove group and other access from files that have it")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	insecure := 0
+	for _, path := range secretFiles() {
+		err := auth.CheckPermissions(path)
+		switch {
+		case err == nil:
+			fmt.Fprintf(stdout, "ok        %s\n", path)
+			continue
+		case !errors.Is(err, auth.ErrInsecurePermissions):
+			return err
+		case doctorFix:
+			if err := auth.FixPermissions(path); err != nil {
+				return fmt.Errorf("failed to fix %s: %w", path, err)
+			}
+			fmt.Fprintf(stdout, "fixed     %s\n", path)
+			continue
+		}
+		insecure++
+		fmt.Fprintf(stdout, "insecure  %v\n", err)
+	}
+
+	if insecure > 0 {
+		return apierrors.WithHint(fmt.Errorf("%d file(s) readable by other users", insecure), fixPermsHint)
+	}
+	return nil
+}
+
+// secretFiles returns the credentials files of the default and every
+// configured profile, then the config file if it holds secrets
+func secretFiles() []string {
+	paths := []string{auth.DefaultCredentialsPath()}
+	for name := range cfg.Profiles {
+		if _, profile, err := cfg.ResolveProfile(name); err == nil && profile.Credentials != "" {
+			paths = append(paths, profile.Credentials)
+		}
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	if cfg.HasSecrets() {
+		paths = append(paths, config.ResolvePath(configPath))
+	}
+	return paths
+}
+
+// warnedPerms holds the paths already warned about, so polling commands
+// warn once
+var warnedPerms sync.Map
+
+// checkPermissions warns when the secrets file at path is readable by
+// other users, or with --strict-perms fails. Files that can't be checked
+// are left for the read to report.
+func checkPermissions(path string) error {
+	err := auth.CheckPermissions(path)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, auth.ErrInsecurePermissions):
+		slog.Debug("failed to check file permissions", "error", err)
+		return nil
+	case strictPerms:
+		return apierrors.WithHint(err, fixPermsHint)
+	}
+	if _, warned := warnedPerms.LoadOrStore(path, true); !warned {
+		slog.Warn(err.Error() + "; run 'claude-limits doctor --fix'")
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut  string
+	exportFrom string
+	exportTo   string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export usage history to a JSON lines file",
+	Long: `Export the active profile's usage history as JSON lines, to move it to
+another machine or archive it. The first line is a header naming the format
+and its version; each line after it is one snapshot.
+
+Examples:
+  claude-limits export --out snapshots.jsonl
+  claude-limits export --from 2025-01-01T00:00:00Z | gzip > 2025.jsonl.gz
+  claude-limits export --profile work --out work.jsonl`,
+	RunE: runExport,
+	Args: cobra.NoArgs,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>...",
+	Short: "Merge exported usage history into this machine's history",
+	Long: `Import snapshots written by 'export' into the active profile's usage
+history. Snapshots already in the history are skipped, so importing a file
+twice, or importing overlapping exports, is safe. Use - to read standard
+input.
+
+Examples:
+  claude-limits import snapshots.jsonl
+  gunzip -c 2025.jsonl.gz | claude-limits import -
+  claude-limits import --profile work work.jsonl`,
+	RunE: runImport,
+	Args: cobra.MinimumNArgs(1),
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "-", "File to write (- for standard output)")
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "Start of range (RFC 3339; default: all history)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "End of range (RFC 3339)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	from, to, err := parseTimeRange(exportFrom, exportTo)
+	if err != nil {
+		return err
+	}
+
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+	snapshots, err := querySnapshots(profile, from, to)
+	if err != nil {
+		return err
+	}
+
+	err = writeExportFile(exportOut, func(w io.Writer) error {
+		return history.WriteExport(w, profile, snapshots)
+	})
+	if err != nil || exportOut == "-" {
+		return err
+	}
+	fmt.Fprintf(stdout, "Exported %d snapshots to %s\n", len(snapshots), exportOut)
+	return nil
+}
+
+// parseTimeRange parses --from and --to (RFC 3339); either may be empty
+// to leave that end of the range open
+func parseTimeRange(fromFlag, toFlag string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if fromFlag != "" {
+		t, err := time.Parse(time.RFC3339, fromFlag)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --from time %q: %w", fromFlag, err)
+		}
+		from = t
+	}
+	if toFlag != "" {
+		t, err := time.Parse(time.RFC3339, toFlag)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --to time %q: %w", toFlag, err)
+		}
+		to = t
+	}
+	return from, to, nil
+}
+
+// querySnapshots reads the profile's history between from and to
+func querySnapshots(profile string, from, to time.Time) ([]history.Snapshot, error) {
+	store, err := history.Open(history.PathForProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.Query(from, to)
+}
+
+// writeExportFile writes an export to path, or to stdout if path is "-"
+func writeExportFile(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(stdout)
+	}
+
+	// History holds API data, so the export is private like the database
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, history.FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	// Read every file before touching the database, so a bad file imports
+	// nothing
+	var snapshots []history.Snapshot
+	for _, path := range args {
+		snaps, err := readExport(cmd.InOrStdin(), path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		snapshots = append(snapshots, snaps...)
+	}
+
+	store, err := history.Open(history.PathForProfile(profile))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	added, err := store.Import(snapshots)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Imported %d snapshots (%d already present) into %s\n", added, len(snapshots)-added, store.Path())
+	return nil
+}
+
+// readExport reads the export at path, or from stdin if path is "-"
+func readExport(stdin io.Reader, path string) ([]history.Snapshot, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	_, snapshots, err := history.ReadExport(r)
+	return snapshots, err
+}
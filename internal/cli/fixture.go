@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/testsupport"
+)
+
+var (
+	recordFile string
+	replayFile string
+)
+
+// fixtureRT is the recorder or replayer shared by every client in the
+// process, so a recording holds all of a run's responses and a replay
+// advances through them
+var fixtureRT struct {
+	once sync.Once
+	rt   http.RoundTripper
+	err  error
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&recordFile, "record", "", "Save API responses to a fixture file for --replay")
+	RootCmd.PersistentFlags().StringVar(&replayFile, "replay", "", "Serve API responses from a fixture file instead of the API; no credentials needed")
+}
+
+// replaying reports whether API responses come from a --replay fixture
+func replaying() bool {
+	return replayFile != ""
+}
+
+// fixtureTransport wraps next to record responses with --record, or
+// replaces it with a fixture with --replay. Otherwise next is returned.
+func fixtureTransport(next http.RoundTripper) (http.RoundTripper, error) {
+	if recordFile == "" && replayFile == "" {
+		return next, nil
+	}
+	fixtureRT.once.Do(func() {
+		switch {
+		case recordFile != "" && replayFile != "":
+			fixtureRT.err = fmt.Errorf("--record and --replay can't be used together")
+		case replayFile != "":
+			f, err := testsupport.LoadFixture(replayFile, api.UsageEndpoint.Path)
+			if err != nil {
+				fixtureRT.err = err
+				return
+			}
+			fixtureRT.rt = testsupport.NewReplayer(f)
+		default:
+			fixtureRT.rt = testsupport.NewRecorder(recordFile, next)
+		}
+	})
+	return fixtureRT.rt, fixtureRT.err
+}
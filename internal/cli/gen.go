@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/version"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var (
+	genDocsDir    string
+	genDocsFormat string
+)
+
+var genCmd = &cobra.Command{
+	Use:    "gen",
+	Short:  "Generate files for packaging",
+	Hidden: true,
+}
+
+var genDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate man pages and a markdown reference from the command tree",
+	Long: `Generate a man page and a markdown page for every command, for packagers
+(Homebrew, AUR, Scoop). Pages are written to <dir>/man and <dir>/markdown.
+Man pages are dated from SOURCE_DATE_EPOCH when it's set, so builds can be
+reproducible.
+
+Examples:
+  claude-limits gen docs --dir docs
+  claude-limits gen docs --dir build --format man`,
+	RunE: runGenDocs,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	genDocsCmd.Flags().StringVar(&genDocsDir, "dir", "docs", "Directory to write the pages to")
+	genDocsCmd.Flags().StringVar(&genDocsFormat, "format", "all", "Pages to generate: man, markdown, or all")
+
+	genCmd.AddCommand(genDocsCmd)
+}
+
+func runGenDocs(cmd *cobra.Command, args []string) error {
+	var man, markdown bool
+	switch genDocsFormat {
+	case "man":
+		man = true
+	case "markdown":
+		markdown = true
+	case "all":
+		man, markdown = true, true
+	default:
+		return fmt.Errorf("unknown docs format %q (want man, markdown, or all)", genDocsFormat)
+	}
+
+	root := cmd.Root()
+	root.DisableAutoGenTag = true
+
+	if man {
+		dir := filepath.Join(genDocsDir, "man")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create man page directory: %w", err)
+		}
+		header := &doc.GenManHeader{
+			Title:   "CLAUDE-LIMITS",
+			Section: "1",
+			Source:  "claude-limits " + version.Version,
+			Manual:  "claude-limits manual",
+		}
+		if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+			sec, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+			}
+			date := time.Unix(sec, 0).UTC()
+			header.Date = &date
+		}
+		if err := doc.GenManTree(root, header, dir); err != nil {
+			return fmt.Errorf("failed to generate man pages: %w", err)
+		}
+		fmt.Fprintf(stdout, "Wrote man pages to %s\n", dir)
+	}
+
+	if markdown {
+		dir := filepath.Join(genDocsDir, "markdown")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create markdown directory: %w", err)
+		}
+		if err := doc.GenMarkdownTree(root, dir); err != nil {
+			return fmt.Errorf("failed to generate markdown: %w", err)
+		}
+		fmt.Fprintf(stdout, "Wrote markdown reference to %s\n", dir)
+	}
+	return nil
+}
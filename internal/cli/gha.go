@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var ghaSummaryCmd = &cobra.Command{
+	Use:   "gha-summary",
+	Short: "Write a usage report to a GitHub Actions job summary and step outputs",
+	Long: `In a GitHub Actions step, append a usage table to the job summary
+($GITHUB_STEP_SUMMARY) and set step outputs ($GITHUB_OUTPUT) so later steps
+can budget Claude usage per pipeline:
+
+  <window>_utilization  e.g. five_hour_utilization=62
+  <window>_remaining    percentage of the limit left
+  <window>_resets_at    RFC 3339, empty when unknown
+  max_utilization       the highest utilization of any window
+  most_constrained      the window with the highest utilization
+
+Outside GitHub Actions, both are printed to standard output instead.
+
+Example workflow steps:
+  - id: claude
+    run: claude-limits gha-summary
+  - if: fromJSON(steps.claude.outputs.five_hour_utilization) < 80
+    run: ./run-agent.sh`,
+	RunE: runGHASummary,
+	Args: cobra.NoArgs,
+}
+
+func runGHASummary(cmd *cobra.Command, args []string) error {
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	summary, err := format.Share(usage, "markdown", shareThresholds())
+	if err != nil {
+		return err
+	}
+
+	if err := appendGitHubFile("GITHUB_STEP_SUMMARY", summary+"\n\n"); err != nil {
+		return err
+	}
+	return appendGitHubFile("GITHUB_OUTPUT", format.GitHubOutputs(usage))
+}
+
+// appendGitHubFile appends content to the file named by the GitHub Actions
+// environment variable, or writes it to stdout when the variable is unset
+func appendGitHubFile(env, content string) error {
+	path := os.Getenv(env)
+	if path == "" {
+		_, err := io.WriteString(stdout, content)
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open $%s: %w", env, err)
+	}
+	if _, err := io.WriteString(f, content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write $%s: %w", env, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write $%s: %w", env, err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/fuzzy"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySince time.Duration
+	historyFrom  string
+	historyTo    string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [query]",
+	Short: "Show recorded usage over a time range",
+	Long: `Show usage snapshots recorded each time fresh data was fetched.
+
+By default, prints the utilization of every window for each snapshot.
+If a query is provided, fuzzy matches a single field and prints its value over time.
+
+Time ranges can be relative (--since) or absolute (--from/--to, RFC 3339).
+
+Examples:
+  claude-limits history
+  claude-limits history --since 6h five_hour_utilization
+  claude-limits history --from 2025-01-01T00:00:00Z --to 2025-01-02T00:00:00Z
+  claude-limits history --format json`,
+	RunE: runHistory,
+	Args: cobra.MaximumNArgs(1),
+}
+
+func init() {
+	historyCmd.Flags().DurationVar(&historySince, "since", 24*time.Hour, "Show snapshots from this long ago until now")
+	historyCmd.Flags().StringVar(&historyFrom, "from", "", "Start of range (RFC 3339, overrides --since)")
+	historyCmd.Flags().StringVar(&historyTo, "to", "", "End of range (RFC 3339)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	from, to, err := historyRange()
+	if err != nil {
+		return err
+	}
+
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	store, err := history.Open(history.PathForProfile(profile))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	snapshots, err := store.Query(from, to)
+	if err != nil {
+		return err
+	}
+
+	if GetOutputFormat() == "json" {
+		return printHistoryJSON(snapshots)
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Fprintln(stdout, "No usage history in the selected range")
+		return nil
+	}
+
+	if len(args) > 0 {
+		return printHistoryField(snapshots, args[0])
+	}
+	return printHistoryTable(snapshots)
+}
+
+// historyRange resolves the --since/--from/--to flags into a time range
+func historyRange() (time.Time, time.Time, error) {
+	from := time.Now().Add(-historySince)
+	var to time.Time
+
+	if historyFrom != "" {
+		t, err := time.Parse(time.RFC3339, historyFrom)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --from time %q: %w", historyFrom, err)
+		}
+		from = t
+	}
+	if historyTo != "" {
+		t, err := time.Parse(time.RFC3339, historyTo)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid --to time %q: %w", historyTo, err)
+		}
+		to = t
+	}
+
+	return from, to, nil
+}
+
+// Trend sparkline settings
+const (
+	trendWindow = 24 * time.Hour
+	trendWidth  = 20
+)
+
+// usageTrends returns a utilization sparkline per window from recent history.
+// Trends are decoration, so failures just yield none.
+func usageTrends() map[string]string {
+	profile, _, err := GetProfile()
+	if err != nil {
+		return nil
+	}
+
+	store, err := history.Open(history.PathForProfile(profile))
+	if err != nil {
+		slog.Debug("failed to open history", "error", err)
+		return nil
+	}
+	defer store.Close()
+
+	snapshots, err := store.Query(time.Now().Add(-trendWindow), time.Time{})
+	if err != nil {
+		slog.Debug("failed to read history", "error", err)
+		return nil
+	}
+
+	trends := make(map[string]string)
+	for window, values := range history.Utilization(snapshots) {
+		trends[window] = format.Sparkline(values, trendWidth)
+	}
+	return trends
+}
+
+func printHistoryJSON(snapshots []history.Snapshot) error {
+	if snapshots == nil {
+		snapshots = []history.Snapshot{}
+	}
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, string(data))
+	return nil
+}
+
+func printHistoryField(snapshots []history.Snapshot, query string) error {
+	colors := outputColors()
+	datetime := GetFormats().Datetime
+
+	for _, snap := range snapshots {
+		var data map[string]interface{}
+		if err := json.Unmarshal(snap.Usage, &data); err != nil {
+			continue
+		}
+		match, err := fuzzy.FindBestMatch(fuzzy.FlattenData(data, ""), query)
+		if err != nil {
+			continue
+		}
+
+		value := fmt.Sprintf("%v", match.Value)
+		if v, ok := match.Value.(float64); ok {
+			value = format.FormatNumber(v, match.Key, colors)
+		}
+		fmt.Fprintf(stdout, "%s  %s\n", snap.Timestamp.Local().Format(datetime), value)
+	}
+
+	return nil
+}
+
+// printHistoryTable prints one line per snapshot with every utilization field
+func printHistoryTable(snapshots []history.Snapshot) error {
+	colors := outputColors()
+	datetime := GetFormats().Datetime
+
+	for _, snap := range snapshots {
+		var data map[string]interface{}
+		if err := json.Unmarshal(snap.Usage, &data); err != nil {
+			continue
+		}
+
+		var parts []string
+		for _, kv := range fuzzy.FlattenData(data, "") {
+			v, ok := kv.Value.(float64)
+			if !ok || kv.Key != "utilization" {
+				continue
+			}
+			window := strings.TrimSuffix(kv.Path, "_utilization")
+			parts = append(parts, fmt.Sprintf("%s: %s", window, format.FormatNumber(v, kv.Key, colors)))
+		}
+		sort.Strings(parts)
+
+		fmt.Fprintf(stdout, "%s  %s\n", snap.Timestamp.Local().Format(datetime), strings.Join(parts, "  "))
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyExportFormat string
+	historyExportOut    string
+	historyExportSince  string
+	historyExportFrom   string
+	historyExportTo     string
+)
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export usage history as CSV or Parquet for analysis",
+	Long: `Export the active profile's usage history as a table with one row per
+window per snapshot: timestamp, window, utilization, and resets_at (UTC).
+Load it into pandas, DuckDB, or a spreadsheet.
+
+Parquet is binary, so it isn't written to a terminal; use --out or a pipe.
+To move history between machines, use 'export' and 'import' instead.
+
+Examples:
+  claude-limits history export > usage.csv
+  claude-limits history export --since 30d --format parquet -o usage.parquet
+  claude-limits history export --from 2025-01-01T00:00:00Z --to 2025-02-01T00:00:00Z
+  python -c "import pandas; print(pandas.read_parquet('usage.parquet'))"`,
+	RunE: runHistoryExport,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	historyExportCmd.Flags().StringVar(&historyExportFormat, "format", "csv", "Export format: csv or parquet")
+	historyExportCmd.Flags().StringVarP(&historyExportOut, "out", "o", "-", "File to write (- for standard output)")
+	historyExportCmd.Flags().StringVar(&historyExportSince, "since", "", "Export from this long ago until now, e.g. 30d (default: all history)")
+	historyExportCmd.Flags().StringVar(&historyExportFrom, "from", "", "Start of range (RFC 3339, overrides --since)")
+	historyExportCmd.Flags().StringVar(&historyExportTo, "to", "", "End of range (RFC 3339)")
+	historyCmd.AddCommand(historyExportCmd)
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	var write func(io.Writer, []history.Row) error
+	switch historyExportFormat {
+	case "csv":
+		write = history.WriteCSV
+	case "parquet":
+		if historyExportOut == "-" && format.IsTerminal() {
+			return fmt.Errorf("parquet is binary; write it to a file with --out")
+		}
+		write = history.WriteParquet
+	default:
+		return fmt.Errorf("invalid --format %q: must be csv or parquet", historyExportFormat)
+	}
+
+	from, to, err := parseTimeRange(historyExportFrom, historyExportTo)
+	if err != nil {
+		return err
+	}
+	if historyExportSince != "" && historyExportFrom == "" {
+		since, err := format.ParseDuration(historyExportSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		from = time.Now().Add(-since)
+	}
+
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+	snapshots, err := querySnapshots(profile, from, to)
+	if err != nil {
+		return err
+	}
+
+	rows := history.Rows(snapshots)
+	err = writeExportFile(historyExportOut, func(w io.Writer) error {
+		return write(w, rows)
+	})
+	if err != nil || historyExportOut == "-" {
+		return err
+	}
+	fmt.Fprintf(stdout, "Exported %d rows from %d snapshots to %s\n", len(rows), len(snapshots), historyExportOut)
+	return nil
+}
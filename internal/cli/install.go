@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/scripts"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	installScriptName string
+	installScriptPath string
+	dryRun            bool
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install integrations",
+	Long:  `Install claude-limits integrations with other tools.`,
+}
+
+var installStatuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Configure Claude Code's status line",
+	Long: `Configure Claude Code's statusLine setting to show usage limits.
+
+By default, the statusLine runs the built-in 'claude-limits statusline'
+command, which needs no extra tools. Use --script to install one of the
+embedded shell scripts instead (see 'claude-limits install-script --list').
+
+By default, user settings (~/.claude/settings.json) are updated.
+Use --project to update project settings (.claude/settings.json) instead.
+
+If statusLine is already configured, use --force to overwrite it.
+Use --dry-run to preview the settings change without writing anything.
+
+Examples:
+  claude-limits install statusline
+  claude-limits install statusline --dry-run
+  claude-limits install statusline --project --force
+  claude-limits install statusline --script bash --path ~/.local/bin/claude-limits-statusline.sh`,
+	RunE: runInstallStatusline,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	installStatuslineCmd.Flags().StringVar(&installScriptName, "script", "", "Install an embedded script instead of using the built-in command")
+	installStatuslineCmd.Flags().StringVar(&installScriptPath, "path", "", "Where to install the script (required with --script)")
+	installStatuslineCmd.Flags().BoolVar(&projectSettings, "project", false, "Configure statusLine in project settings (.claude/settings.json)")
+	installStatuslineCmd.Flags().BoolVar(&forceOverwrite, "force", false, "Overwrite existing script file and statusLine config")
+	installStatuslineCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without writing anything")
+
+	installCmd.AddCommand(installStatuslineCmd)
+}
+
+func runInstallStatusline(cmd *cobra.Command, args []string) error {
+	var script *scripts.Script
+	var scriptPath string
+	command := builtinStatuslineCommand()
+
+	if installScriptName != "" {
+		script = scripts.Get(installScriptName)
+		if script == nil {
+			return fmt.Errorf("unknown script: %s\nRun 'claude-limits install-script --list' to see available scripts", installScriptName)
+		}
+		if !script.StatusLine {
+			return fmt.Errorf("%s is not a Claude Code status line script\nUse 'claude-limits install-script %s <path>' instead", installScriptName, installScriptName)
+		}
+		if installScriptPath == "" {
+			return fmt.Errorf("--path is required with --script")
+		}
+		// Claude Code runs the command from the session's directory, so a
+		// relative path would only work from here
+		abs, err := filepath.Abs(installScriptPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", installScriptPath, err)
+		}
+		scriptPath = abs
+		if _, err := os.Stat(scriptPath); err == nil && !forceOverwrite {
+			return fmt.Errorf("file already exists: %s\nUse --force to overwrite", scriptPath)
+		}
+		command = shellPath(scriptPath)
+	}
+
+	settingsPath, settingsType := settingsTarget()
+	settings, err := claudecode.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load Claude Code settings: %w", err)
+	}
+
+	if err := settings.SetStatusLine(command, forceOverwrite); err != nil {
+		if errors.Is(err, claudecode.ErrStatusLineExists) {
+			return fmt.Errorf("statusLine already configured in %s settings (%s)\nUse --force to overwrite", settingsType, settingsPath)
+		}
+		return err
+	}
+
+	if dryRun {
+		return printInstallPlan(script, scriptPath, settingsPath, settings)
+	}
+
+	if script != nil {
+		if err := writeScript(script, scriptPath); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Installed %s to %s\n", script.Filename, scriptPath)
+	}
+
+	if err := claudecode.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("failed to save Claude Code settings: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "Configured statusLine in %s settings (%s)\n", settingsType, settingsPath)
+	return nil
+}
+
+// printInstallPlan describes the changes a real run would make
+func printInstallPlan(script *scripts.Script, scriptPath, settingsPath string, settings claudecode.Settings) error {
+	if script != nil {
+		fmt.Fprintf(stdout, "Would install %s to %s\n", script.Filename, scriptPath)
+	}
+
+	diff, err := claudecode.DiffSettings(settingsPath, settings)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintf(stdout, "No changes to %s\n", settingsPath)
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "Would update %s:\n\n%s", settingsPath, diff)
+	return nil
+}
+
+// writeScript renders an embedded script with the user's settings and writes
+// it, making executable scripts executable on Unix
+func writeScript(script *scripts.Script, path string) error {
+	content, err := script.Render(scriptVars())
+	if err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if script.Executable && runtime.GOOS != "windows" {
+		perm = 0755
+	}
+	if err := os.WriteFile(path, content, perm); err != nil {
+		return fmt.Errorf("failed to write script: %w", err)
+	}
+	return nil
+}
+
+// scriptVars resolves the values substituted into installed scripts from
+// flags and config
+func scriptVars() scripts.Vars {
+	vars := scripts.DefaultVars()
+	vars.Binary = scriptBinary()
+	if flagChanged("cache") {
+		vars.CacheTTL = GetCacheTTL()
+	}
+
+	thresholds := format.DefaultThresholds
+	if cfg != nil {
+		if t, ok := cfg.ResolvedThresholds()[""]; ok {
+			thresholds = t
+		}
+	}
+	vars.Warning = int(thresholds.Warn)
+	vars.Critical = int(thresholds.Critical)
+
+	vars.Clock24 = strings.Contains(GetFormats().Time, "15")
+	return vars
+}
+
+// scriptBinary returns this binary's path for installed scripts to run, or ""
+// if it's the claude-limits on PATH, so scripts keep working after upgrades
+// that move the binary
+func scriptBinary() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	found, err := exec.LookPath("claude-limits")
+	if err != nil {
+		return exe
+	}
+	foundInfo, err1 := os.Stat(found)
+	exeInfo, err2 := os.Stat(exe)
+	if err1 == nil && err2 == nil && os.SameFile(foundInfo, exeInfo) {
+		return ""
+	}
+	return exe
+}
+
+// builtinStatuslineCommand returns the statusLine command that runs this
+// binary's statusline subcommand, using an absolute path when available so
+// it works regardless of Claude Code's PATH
+func builtinStatuslineCommand() string {
+	return selfCommand() + " statusline"
+}
+
+// selfCommand returns the path of this binary for use in a shell command,
+// quoted, or "claude-limits" if it can't be determined
+func selfCommand() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "claude-limits"
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return shellPath(exe)
+}
+
+// shellPath quotes path for use in a shell command. It's single-quoted, so
+// the shell expands nothing in it, and each embedded single quote closes
+// the quoting, is escaped, and reopens it.
+// cmd.exe doesn't understand single quotes, so on Windows a path is
+// double-quoted, and only if it has spaces.
+func shellPath(path string) string {
+	if runtime.GOOS == "windows" {
+		if strings.ContainsAny(path, " \t") {
+			return `"` + path + `"`
+		}
+		return path
+	}
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
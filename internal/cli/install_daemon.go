@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serviceSystemd       bool
+	serviceLaunchd       bool
+	serviceScheduledTask bool
+	serviceTimer         bool
+	serviceInterval      int
+	serviceNoStart       bool
+)
+
+var installDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the daemon as a user service at login",
+	Long: `Install a user-level service that runs 'claude-limits daemon', keeping the
+cache and history fresh and dispatching alerts without a terminal open.
+
+The service manager defaults to the platform's: a systemd user unit on
+Linux (--systemd), a launchd agent on macOS (--launchd), or a scheduled
+task on Windows (--scheduled-task). The service is enabled and started
+unless --no-start is given.
+
+With --timer, 'claude-limits notify' runs every --interval seconds instead
+of a long-running daemon (a systemd timer, a launchd StartInterval, or a
+repeating task), which suits machines that sleep often.
+
+--config and --profile are passed on to the service. Use --dry-run to print
+the files and commands without changing anything, and
+'claude-limits uninstall daemon' to remove the service.
+
+Examples:
+  claude-limits install daemon
+  claude-limits install daemon --systemd --timer --interval 300
+  claude-limits install daemon --profile work --dry-run`,
+	RunE: runInstallDaemon,
+	Args: cobra.NoArgs,
+}
+
+var uninstallDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Stop and remove the daemon service",
+	Long: `Reverse 'install daemon': stop the service and remove its unit, agent,
+or scheduled task. The service manager defaults to the platform's.
+
+Examples:
+  claude-limits uninstall daemon
+  claude-limits uninstall daemon --dry-run`,
+	RunE: runUninstallDaemon,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{installDaemonCmd, uninstallDaemonCmd} {
+		cmd.Flags().BoolVar(&serviceSystemd, "systemd", false, "Use a systemd user unit (default on Linux)")
+		cmd.Flags().BoolVar(&serviceLaunchd, "launchd", false, "Use a launchd agent (default on macOS)")
+		cmd.Flags().BoolVar(&serviceScheduledTask, "scheduled-task", false, "Use a Windows scheduled task (default on Windows)")
+		cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without changing anything")
+		cmd.MarkFlagsMutuallyExclusive("systemd", "launchd", "scheduled-task")
+	}
+	installDaemonCmd.Flags().BoolVar(&serviceTimer, "timer", false, "Run 'notify' on a schedule instead of a long-running daemon")
+	installDaemonCmd.Flags().IntVarP(&serviceInterval, "interval", "i", 60, "Poll interval in seconds")
+	installDaemonCmd.Flags().BoolVar(&serviceNoStart, "no-start", false, "Write the service without enabling or starting it")
+
+	installCmd.AddCommand(installDaemonCmd)
+	uninstallCmd.AddCommand(uninstallDaemonCmd)
+}
+
+// serviceKind resolves the service manager flags
+func serviceKind() service.Kind {
+	switch {
+	case serviceSystemd:
+		return service.Systemd
+	case serviceLaunchd:
+		return service.Launchd
+	case serviceScheduledTask:
+		return service.ScheduledTask
+	}
+	return service.Default()
+}
+
+func runInstallDaemon(cmd *cobra.Command, args []string) error {
+	if serviceInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", serviceInterval)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the claude-limits binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	// Without --interval, the daemon's own default or daemon.interval in
+	// the config applies, and stays reloadable
+	spec := service.Spec{Binary: exe, Args: []string{"daemon"}}
+	if flagChanged("interval") {
+		spec.Args = append(spec.Args, "--interval", strconv.Itoa(serviceInterval))
+	}
+	if serviceTimer {
+		spec.Args = []string{"notify"}
+		spec.Interval = time.Duration(serviceInterval) * time.Second
+	}
+	if configPath != "" {
+		spec.Args = append(spec.Args, "--config", absPath(configPath))
+	}
+	if profileName != "" {
+		spec.Args = append(spec.Args, "--profile", profileName)
+	}
+
+	plan, err := service.Install(serviceKind(), spec)
+	if err != nil {
+		return err
+	}
+	if serviceNoStart {
+		plan.After = nil
+	}
+	return applyServicePlan(plan, true)
+}
+
+func runUninstallDaemon(cmd *cobra.Command, args []string) error {
+	kind := serviceKind()
+	plan, err := service.Uninstall(kind)
+	if err != nil {
+		return err
+	}
+	if len(plan.Before) == 0 && len(plan.Files) == 0 {
+		fmt.Fprintf(stdout, "No %s service installed\n", kind)
+		return nil
+	}
+	return applyServicePlan(plan, false)
+}
+
+// applyServicePlan runs a plan's commands and writes (or removes) its
+// files, or with --dry-run describes them. Failing commands before files
+// are changed are reported but not fatal when uninstalling, since the
+// service may already be stopped.
+func applyServicePlan(plan service.Plan, install bool) error {
+	if dryRun {
+		for _, c := range plan.Before {
+			fmt.Fprintf(stdout, "Would run: %s\n", strings.Join(c, " "))
+		}
+		for _, f := range plan.Files {
+			if install {
+				fmt.Fprintf(stdout, "Would write %s:\n\n%s\n", f.Path, f.Content)
+			} else {
+				fmt.Fprintf(stdout, "Would remove %s\n", f.Path)
+			}
+		}
+		for _, c := range plan.After {
+			fmt.Fprintf(stdout, "Would run: %s\n", strings.Join(c, " "))
+		}
+		return nil
+	}
+
+	for _, c := range plan.Before {
+		if err := runServiceCommand(c); err != nil {
+			if install {
+				return err
+			}
+			fmt.Fprintf(stdout, "Warning: %v\n", err)
+		}
+	}
+	for _, f := range plan.Files {
+		if !install {
+			if err := removePath("service file", f.Path, os.Remove); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(f.Path), err)
+		}
+		if err := os.WriteFile(f.Path, []byte(f.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write service file: %w", err)
+		}
+		fmt.Fprintf(stdout, "Wrote %s\n", f.Path)
+	}
+	for _, c := range plan.After {
+		if err := runServiceCommand(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runServiceCommand runs a service manager command, passing its output
+// through
+func runServiceCommand(args []string) error {
+	fmt.Fprintf(stdout, "Running: %s\n", strings.Join(args, " "))
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdout = stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"runtime"
+	"path/filepath"
 	"sort"
 
 	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
@@ -14,25 +14,30 @@ import (
 )
 
 var (
-	forceOverwrite bool
-	listScripts    bool
+	forceOverwrite  bool
+	listScripts     bool
 	projectSettings bool
 )
 
 var installScriptCmd = &cobra.Command{
 	Use:   "install-script <name> <path>",
 	Short: "Install an embedded script to a file path",
-	Long: `Install one of the embedded status line scripts to a specified location.
+	Long: `Install one of the embedded scripts to a specified location.
 
-This command:
+For status line scripts, this command:
 1. Installs the script file to the specified path
 2. Configures Claude Code's statusLine setting to use the script
 
 Available scripts:
   bash        - Bash status line script for Claude Code
   powershell  - PowerShell status line script for Claude Code
+  fish        - Fish status line script for Claude Code (no jq needed)
+  nushell     - Nushell status line script for Claude Code
+  tmux        - tmux status bar segment
 
-The bash script will be installed with executable permissions (0755) on Unix systems.
+All but the PowerShell script will be installed with executable permissions
+(0755) on Unix systems. The tmux script doesn't touch Claude Code settings;
+the tmux.conf lines to use it are printed instead.
 
 By default, the statusLine is configured in user settings (~/.claude/settings.json).
 Use --project to configure in project settings (.claude/settings.json) instead.
@@ -42,6 +47,8 @@ If statusLine is already configured, use --force to overwrite it.
 Examples:
   claude-limits install-script bash ~/.local/bin/claude-limits-statusline.sh
   claude-limits install-script powershell ~/bin/claude-limits-statusline.ps1
+  claude-limits install-script fish ~/.local/bin/claude-limits-statusline.fish
+  claude-limits install-script tmux ~/.local/bin/claude-limits-tmux.sh
   claude-limits install-script --project bash .local/bin/claude-limits-statusline.sh
   claude-limits install-script --list`,
 	RunE: runInstallScript,
@@ -83,22 +90,23 @@ func runInstallScript(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check statusLine conflict before writing any files
-	if err := checkStatusLineConflict(); err != nil {
-		return err
-	}
-
-	// Determine permissions
-	perm := os.FileMode(0644)
-	if name == "bash" && runtime.GOOS != "windows" {
-		perm = 0755
+	if script.StatusLine {
+		if err := checkStatusLineConflict(); err != nil {
+			return err
+		}
 	}
 
 	// Write the script file
-	if err := os.WriteFile(path, script.Content, perm); err != nil {
-		return fmt.Errorf("failed to write script: %w", err)
+	if err := writeScript(script, path); err != nil {
+		return err
 	}
 
-	fmt.Printf("Installed %s to %s\n", script.Filename, path)
+	fmt.Fprintf(stdout, "Installed %s to %s\n", script.Filename, path)
+
+	if !script.StatusLine {
+		printTmuxSetup(path)
+		return nil
+	}
 
 	// Configure statusLine in Claude Code settings
 	if err := configureStatusLine(path); err != nil {
@@ -108,21 +116,21 @@ func runInstallScript(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// settingsTarget returns the Claude Code settings path and its description
+// ("user" or "project") selected by --project
+func settingsTarget() (string, string) {
+	if projectSettings {
+		return claudecode.DefaultProjectSettingsPath(), "project"
+	}
+	return claudecode.DefaultUserSettingsPath(), "user"
+}
+
 func checkStatusLineConflict() error {
 	if forceOverwrite {
 		return nil
 	}
 
-	var settingsPath string
-	var settingsType string
-
-	if projectSettings {
-		settingsPath = claudecode.DefaultProjectSettingsPath()
-		settingsType = "project"
-	} else {
-		settingsPath = claudecode.DefaultUserSettingsPath()
-		settingsType = "user"
-	}
+	settingsPath, settingsType := settingsTarget()
 
 	settings, err := claudecode.LoadSettings(settingsPath)
 	if err != nil {
@@ -137,16 +145,7 @@ func checkStatusLineConflict() error {
 }
 
 func configureStatusLine(scriptPath string) error {
-	var settingsPath string
-	var settingsType string
-
-	if projectSettings {
-		settingsPath = claudecode.DefaultProjectSettingsPath()
-		settingsType = "project"
-	} else {
-		settingsPath = claudecode.DefaultUserSettingsPath()
-		settingsType = "user"
-	}
+	settingsPath, settingsType := settingsTarget()
 
 	settings, err := claudecode.LoadSettings(settingsPath)
 	if err != nil {
@@ -164,13 +163,24 @@ func configureStatusLine(scriptPath string) error {
 		return fmt.Errorf("failed to save Claude Code settings: %w", err)
 	}
 
-	fmt.Printf("Configured statusLine in %s settings (%s)\n", settingsType, settingsPath)
+	fmt.Fprintf(stdout, "Configured statusLine in %s settings (%s)\n", settingsType, settingsPath)
 	return nil
 }
 
+// printTmuxSetup shows the tmux.conf lines that run an installed tmux script
+func printTmuxSetup(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Add to ~/.tmux.conf:")
+	fmt.Fprintf(stdout, "  set -g status-right '#(%s)'\n", path)
+	fmt.Fprintln(stdout, "  set -g status-interval 60")
+}
+
 func printAvailableScripts() error {
-	fmt.Println("Available scripts:")
-	fmt.Println()
+	fmt.Fprintln(stdout, "Available scripts:")
+	fmt.Fprintln(stdout)
 
 	// Sort script names for consistent output
 	names := scripts.List()
@@ -178,10 +188,10 @@ func printAvailableScripts() error {
 
 	for _, name := range names {
 		script := scripts.Get(name)
-		fmt.Printf("  %-12s %s\n", name, script.Description)
+		fmt.Fprintf(stdout, "  %-12s %s\n", name, script.Description)
 	}
 
-	fmt.Println()
-	fmt.Println("Usage: claude-limits install-script <name> <path>")
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Usage: claude-limits install-script <name> <path>")
 	return nil
 }
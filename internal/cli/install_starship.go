@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// starshipModule is the starship custom module's table name
+const starshipModule = "[custom.claude_limits]"
+
+var starshipConfig string
+
+var installStarshipCmd = &cobra.Command{
+	Use:   "starship",
+	Short: "Add a claude-limits module to the starship prompt",
+	Long: `Append a [custom.claude_limits] module to starship's config, which shows
+usage with --format starship in every prompt. Starship's default format
+includes custom modules, so nothing else needs to change.
+
+The config is $STARSHIP_CONFIG, or ~/.config/starship.toml. Use --dry-run to
+print the module instead of writing it, e.g. to paste it yourself. Usage is
+cached for 30 seconds (--cache), so prompts don't each query the API.
+
+Examples:
+  claude-limits install starship
+  claude-limits install starship --icons
+  claude-limits install starship --dry-run`,
+	RunE: runInstallStarship,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	installStarshipCmd.Flags().StringVar(&starshipConfig, "config", "", "Starship config file (default: $STARSHIP_CONFIG or ~/.config/starship.toml)")
+	installStarshipCmd.Flags().BoolVar(&starshipIcons, "icons", false, "Show Nerd Font icons instead of window labels")
+	installStarshipCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the module instead of writing it")
+
+	installCmd.AddCommand(installStarshipCmd)
+}
+
+func runInstallStarship(cmd *cobra.Command, args []string) error {
+	module := starshipModuleTOML()
+	if dryRun {
+		fmt.Fprint(stdout, module)
+		return nil
+	}
+
+	path := starshipConfig
+	if path == "" {
+		path = defaultStarshipConfig()
+	}
+	if path == "" {
+		return fmt.Errorf("no home directory to find the starship config in; use --config or set STARSHIP_CONFIG")
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read starship config: %w", err)
+	}
+	if strings.Contains(string(existing), starshipModule) {
+		return fmt.Errorf("%s already configured in %s", starshipModule, path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create starship config directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open starship config: %w", err)
+	}
+	defer f.Close()
+
+	if len(existing) > 0 {
+		module = "\n" + module
+		if !strings.HasSuffix(string(existing), "\n") {
+			module = "\n" + module
+		}
+	}
+	if _, err := f.WriteString(module); err != nil {
+		return fmt.Errorf("failed to write starship config: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "Added %s to %s\n", starshipModule, path)
+	return nil
+}
+
+// starshipModuleTOML returns the custom module running this binary
+func starshipModuleTOML() string {
+	command := selfCommand() + " --format starship"
+	if starshipIcons {
+		command += " --icons"
+	}
+
+	var b strings.Builder
+	b.WriteString("# Claude.ai usage limits (claude-limits install starship)\n")
+	b.WriteString(starshipModule + "\n")
+	fmt.Fprintf(&b, "command = %s\n", strconv.Quote(command))
+	b.WriteString("when = true\n")
+	b.WriteString(`format = "[$output]($style) "` + "\n")
+	b.WriteString(`style = "bold cyan"` + "\n")
+	b.WriteString(`description = "Claude.ai usage limits"` + "\n")
+	return b.String()
+}
+
+// defaultStarshipConfig returns the config file starship reads, or "" if
+// there's no home directory to find it in
+func defaultStarshipConfig() string {
+	if path := os.Getenv("STARSHIP_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "starship.toml")
+}
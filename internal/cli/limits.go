@@ -1,20 +1,47 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/benjaminabbitt/claude-limits/internal/api"
 	"github.com/benjaminabbitt/claude-limits/internal/auth"
 	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
 	"github.com/benjaminabbitt/claude-limits/internal/format"
 	"github.com/benjaminabbitt/claude-limits/internal/fuzzy"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/jsonpath"
 	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/usecase"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	jsonQuery      string
+	showAllMatches bool
+	showTrend      bool
+	byModel        bool
+	starshipIcons  bool
+	failAt         float64
+	failAtWindow   map[string]string
+	rawOutput      bool
+	fields         []string
+	excludeFields  []string
+	imageOut       string
+)
+
 var limitsCmd = &cobra.Command{
 	Use:   "limits [query]",
 	Short: "Display current usage",
@@ -23,70 +50,287 @@ var limitsCmd = &cobra.Command{
 If a query is provided, fuzzy matches against field names and returns just the value.
 Example: claude-limits limits five  →  returns value for "Five Hour" field
 
+Use --by-model to compare weekly usage per model (Opus, Sonnet) against
+the overall weekly cap. Per-model windows turn yellow at 60% and red at 85%,
+earlier than the overall thresholds, since the Opus cap usually runs out first.
+
+Use --fail-at to gate scripts on remaining quota: usage prints as usual,
+then the command exits with status 2 if any window's utilization reached
+the threshold. --fail-at-window sets a limit for individual windows, by
+name or short label (5h, wk, opus, sonnet):
+  claude-limits --format compact --fail-at 90 --fail-at-window opus=75
+
+Exit codes: 0 success, 1 other error, 2 threshold reached,
+3 authentication error, 4 API request failed.
+
+Use --fields to show only some fields, in any format, and --exclude to drop
+some. Paths are dot-separated, and windows can be named by label:
+  claude-limits --fields 5h,wk --format json
+  claude-limits --exclude seven_day_oauth_apps,five_hour.resets_at
+
+Use --trend to add a sparkline of recent utilization (from usage history)
+next to each window in table and compact output.
+
+Use --all to list every matching field with its score. When several fields
+match equally well, you're asked to pick one if running interactively;
+otherwise the alphabetically first is shown and the others are noted on stderr.
+
+Use --all-orgs to see every organization you belong to at once. Claude Code
+credentials are signed in to one organization at a time, so configure a
+profile per organization, each with its own credentials file (and optionally
+an organization name to label it). Every profile is fetched concurrently,
+shown as a section per organization in table and compact output, or merged
+into one JSON document keyed by profile:
+  claude-limits --all-orgs
+  claude-limits --all-orgs --format json | jq '.work.usage.seven_day'
+
+Use --format image to render the usage table as a PNG, with each window's
+bar in its threshold color, for posting in Slack or Discord:
+  claude-limits --format image --out usage.png
+
+Use --raw to print the response body exactly as the API sent it, with no
+re-encoding, for checksumming or archiving. It always makes a fresh,
+unconditional request, since cached entries are re-encoded.
+
+For precise, scriptable extraction use --query with a JSONPath expression.
+Strings print unquoted, objects and arrays print as JSON, and a path that
+doesn't exist is an error:
+  claude-limits --query '$.five_hour.resets_at'
+  claude-limits --query '$.seven_day_opus'
+
 Authentication uses OAuth credentials from Claude Code (~/.claude/.credentials.json).
-Make sure you have authenticated with Claude Code first.`,
+Make sure you have authenticated with Claude Code first.
+
+API-platform organizations can use --auth-mode api-key with an admin key in
+ANTHROPIC_API_KEY to see the organization's token usage over the last 5 hours
+and 7 days from the Admin API, in table or json format.`,
 	RunE: runLimits,
 	Args: cobra.MaximumNArgs(1),
 }
 
+func init() {
+	addLimitsFlags(limitsCmd)
+	addLimitsFlags(RootCmd)
+}
+
+// addLimitsFlags registers the query flags on a command that runs limits
+func addLimitsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&jsonQuery, "query", "", "Extract a field with a JSONPath expression (e.g. '$.five_hour.resets_at')")
+	cmd.Flags().BoolVar(&showAllMatches, "all", false, "List every field matching the fuzzy query, with scores")
+	cmd.Flags().BoolVar(&showTrend, "trend", false, "Show a sparkline of each window's utilization over the last 24h of history")
+	cmd.Flags().BoolVar(&byModel, "by-model", false, "Compare weekly usage per model against the overall weekly cap")
+	cmd.Flags().BoolVar(&starshipIcons, "icons", false, "Show Nerd Font icons instead of window labels with --format starship")
+	cmd.Flags().Float64Var(&failAt, "fail-at", 0, "Exit with status 2 if any window's utilization reaches this percent")
+	cmd.Flags().StringToStringVar(&failAtWindow, "fail-at-window", nil, "Per-window --fail-at, e.g. 5h=90,opus=75 (overrides --fail-at for that window)")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "Show only these fields, e.g. five_hour,seven_day.utilization (windows also by label: 5h, wk)")
+	cmd.Flags().StringSliceVar(&excludeFields, "exclude", nil, "Omit these fields, e.g. seven_day_oauth_apps,five_hour.resets_at")
+	cmd.Flags().BoolVar(&rawOutput, "raw", false, "Print the API response body exactly as received (always fetches fresh)")
+	cmd.Flags().StringVarP(&imageOut, "out", "o", "-", "File to write --format image to (- for standard output)")
+	cmd.Flags().BoolVar(&allOrgs, "all-orgs", false, "Fetch every configured profile (one per organization) concurrently and show them together")
+}
+
 func runLimits(cmd *cobra.Command, args []string) error {
-	usage, err := getUsageWithCache()
+	if jsonQuery != "" && len(args) > 0 {
+		return fmt.Errorf("--query cannot be combined with a fuzzy query argument")
+	}
+	if rawOutput && (jsonQuery != "" || len(args) > 0 || byModel || len(fields) > 0 || len(excludeFields) > 0) {
+		return fmt.Errorf("--raw cannot be combined with a query, --by-model, --fields, or --exclude")
+	}
+
+	mode, err := authMode()
+	if err != nil {
+		return err
+	}
+	if mode == authModeAPIKey {
+		if jsonQuery != "" || len(args) > 0 || rawOutput || byModel || len(fields) > 0 || len(excludeFields) > 0 || failAt > 0 || len(failAtWindow) > 0 {
+			return fmt.Errorf("--auth-mode %s supports only table and json output", authModeAPIKey)
+		}
+		return runAPIKeyLimits(cmd.Context())
+	}
+
+	if allOrgs {
+		if jsonQuery != "" || len(args) > 0 || rawOutput || byModel || showTrend {
+			return fmt.Errorf("--all-orgs cannot be combined with a query, --raw, --by-model, or --trend")
+		}
+		return runAllOrgs(cmd.Context())
+	}
+
+	if q := defaultQuery(); q != "" && len(args) == 0 && jsonQuery == "" && !rawOutput && !byModel && len(fields) == 0 && len(excludeFields) == 0 {
+		slog.Debug("using default query from config output.query", "query", q)
+		args = []string{q}
+	}
+
+	limits, err := failAtLimits()
+	if err != nil {
+		return err
+	}
+
+	usage, err := fetchUsage(cmd.Context(), rawOutput)
+	if err != nil {
+		return err
+	}
+
+	if rawOutput {
+		_, err = stdout.Write(usage.Raw)
+	} else {
+		err = printSelected(usage, args)
+	}
+	if err != nil {
+		return err
+	}
+	return checkFailAt(usage, limits)
+}
+
+// defaultQuery returns the config's output.query
+func defaultQuery() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Output.Query
+}
+
+// printSelected prints the fields chosen by --fields and --exclude
+func printSelected(usage *models.Usage, args []string) error {
+	selected, err := usage.Select(fieldPaths(fields), fieldPaths(excludeFields))
 	if err != nil {
 		return err
 	}
+	return printUsage(selected, args)
+}
+
+// fieldPaths resolves window labels such as "5h" at the start of each path
+func fieldPaths(paths []string) []string {
+	out := make([]string, len(paths))
+	for i, path := range paths {
+		window, rest, nested := strings.Cut(path, ".")
+		out[i] = format.WindowName(window)
+		if nested {
+			out[i] += "." + rest
+		}
+	}
+	return out
+}
+
+// printUsage prints usage in the form selected by the query and flags
+func printUsage(usage *models.Usage, args []string) error {
+
+	if jsonQuery != "" {
+		return printPathValue(usage, jsonQuery)
+	}
 
 	// If a query argument is provided, do fuzzy match
 	if len(args) > 0 {
 		return printMatchedValue(usage, args[0])
 	}
 
-	if GetOutputFormat() == "json" {
+	if byModel {
+		return printByModel(usage)
+	}
+
+	switch GetOutputFormat() {
+	case "json":
 		return printJSON(usage)
+	case "compact":
+		return printCompact(usage)
+	case "influx":
+		return printInflux(usage)
+	case "waybar":
+		return printWaybar(usage)
+	case "starship":
+		fmt.Fprintln(stdout, format.Starship(usage, compactOptions(), starshipIcons))
+		return nil
+	case "image":
+		return printImage(usage)
 	}
 	return printTable(usage)
 }
 
-func getUsageWithCache() (*models.Usage, error) {
-	ttl := GetCacheTTL()
-	c := cache.New(IsVerbose())
+// imageScale is how many PNG pixels --format image draws per font pixel
+const imageScale = 2
 
-	// Try to read from cache if TTL > 0
-	if ttl > 0 {
-		if cached, err := c.Read(ttl); err == nil {
-			if IsVerbose() {
-				fmt.Fprintln(os.Stderr, "Using cached data")
-			}
-			return cached, nil
+// printImage writes usage as a PNG to --out
+func printImage(usage *models.Usage) error {
+	var buf bytes.Buffer
+	if err := format.WriteImage(&buf, usage, shareThresholds(), tableFormats(), imageScale); err != nil {
+		return err
+	}
+	if imageOut == "-" {
+		if format.IsTerminal() {
+			return fmt.Errorf("--format image is binary; write it to a file with --out")
 		}
+		_, err := stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := cache.WriteAtomic(imageOut, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
 	}
+	return nil
+}
 
-	// Fetch fresh data
-	creds, err := auth.Load("")
-	if err != nil {
+func getUsageWithCache(ctx context.Context) (*models.Usage, error) {
+	return fetchUsage(ctx, false)
+}
+
+// fetchUsage returns usage from the cache when fresh, otherwise from the API.
+// With refresh, the cache is bypassed for reading but always written, so
+// long-running pollers keep it warm for other consumers.
+func fetchUsage(ctx context.Context, refresh bool) (*models.Usage, error) {
+	if mode, err := authMode(); err != nil {
 		return nil, err
+	} else if mode != authModeOAuth {
+		return nil, fmt.Errorf("subscription usage needs OAuth credentials; --auth-mode %s only works with limits", mode)
 	}
 
-	if IsVerbose() {
-		fmt.Fprintf(os.Stderr, "Using Claude Code credentials (subscription: %s)\n", creds.SubscriptionType)
-		if creds.IsExpired() {
-			fmt.Fprintln(os.Stderr, "Warning: access token may be expired")
-		}
+	profile, settings, err := GetProfile()
+	if err != nil {
+		return nil, err
 	}
+	return fetchProfileUsage(ctx, profile, settings, refresh)
+}
 
-	client := api.NewClient(creds.AccessToken)
-	usage, err := client.GetUsage()
+// fetchProfileUsage is fetchUsage for a given profile, with its own cache
+// and history. It is safe to call for several profiles concurrently.
+func fetchProfileUsage(ctx context.Context, profile string, settings config.Profile, refresh bool) (*models.Usage, error) {
+	s, err := usageService(profile, settings, refresh)
 	if err != nil {
 		return nil, err
 	}
+	return s.Usage(ctx)
+}
 
-	// Save to cache
-	if ttl > 0 {
-		if err := c.Write(usage); err != nil && IsVerbose() {
-			fmt.Fprintf(os.Stderr, "Failed to write cache: %v\n", err)
-		}
+// usageService fetches the profile's usage with the global flags and config
+// applied: the cache TTL, the API client settings, and history recording.
+// They're read once, here, so the service can be used while the config is
+// swapped. With refresh, the cache is bypassed for reading but always
+// written.
+func usageService(profile string, settings config.Profile, refresh bool) (*usecase.UsageService, error) {
+	clientOpts, err := apiClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts := []usecase.Option{
+		usecase.WithClient(func(accessToken string) (*api.Client, error) {
+			return api.NewClient(accessToken, clientOpts...), nil
+		}),
+		// --raw needs the body itself, not a 304
+		usecase.WithRevalidation(!rawOutput),
+	}
+	if cacheEnabled() {
+		c := cache.New(IsVerbose(), cache.WithProfile(profile))
+		opts = append(opts, usecase.WithCache(c, time.Duration(GetCacheTTL())*time.Second))
+	}
+	if refresh {
+		opts = append(opts, usecase.WithRefresh(usecase.RefreshAlways))
+	}
+	// Replayed usage isn't the account's
+	if !replaying() {
+		opts = append(opts, usecase.WithHistory(history.PathForProfile(profile)))
 	}
 
-	return usage, nil
+	credentials := func(ctx context.Context) (*auth.Credentials, error) {
+		return loadCredentials(ctx, settings)
+	}
+	return usecase.NewUsageService(credentials, opts...), nil
 }
 
 func printMatchedValue(usage *models.Usage, query string) error {
@@ -95,25 +339,120 @@ func printMatchedValue(usage *models.Usage, query string) error {
 		return fmt.Errorf("failed to parse usage data: %w", err)
 	}
 
-	pairs := fuzzy.FlattenData(data, "")
-	match, err := fuzzy.FindBestMatch(pairs, query)
+	matches := fuzzy.FindMatches(fuzzy.FlattenData(data, ""), query)
+	if len(matches) == 0 {
+		return apierrors.NewQueryError(query, apierrors.ErrNoMatch)
+	}
+
+	colors := outputColors()
+
+	if showAllMatches {
+		return printMatches(matches, colors)
+	}
+
+	match := matches[0]
+	if tied := fuzzy.Tied(matches); len(tied) > 1 {
+		if isStdinTerminal() && format.IsTerminal() {
+			picked, err := pickMatch(tied)
+			if err != nil {
+				return err
+			}
+			match = picked
+		} else if !quiet {
+			fmt.Fprintf(os.Stderr, "%q matches %d fields equally; showing %s (use --all to list them)\n", query, len(tied), match.Path)
+		}
+	}
+
+	fmt.Fprintln(stdout, formatMatchValue(match.KeyValue, colors))
+	return nil
+}
+
+// formatMatchValue renders a matched field's value for display
+func formatMatchValue(kv fuzzy.KeyValue, colors format.Colors) string {
+	if v, ok := kv.Value.(float64); ok {
+		return format.FormatNumber(v, kv.Key, colors)
+	}
+	return fmt.Sprintf("%v", kv.Value)
+}
+
+// printMatches lists every candidate field, best first
+func printMatches(matches []fuzzy.Match, colors format.Colors) error {
+	if GetOutputFormat() == "json" {
+		type jsonMatch struct {
+			Field string      `json:"field"`
+			Score int         `json:"score"`
+			Value interface{} `json:"value"`
+		}
+		out := make([]jsonMatch, len(matches))
+		for i, m := range matches {
+			out[i] = jsonMatch{Field: m.Path, Score: m.Score, Value: m.Value}
+		}
+		j, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize matches: %w", err)
+		}
+		fmt.Fprintln(stdout, string(j))
+		return nil
+	}
+
+	for _, m := range matches {
+		fmt.Fprintf(stdout, "%-32s %5d  %s\n", m.Path, m.Score, formatMatchValue(m.KeyValue, colors))
+	}
+	return nil
+}
+
+// pickMatch asks the user to choose between equally good matches
+func pickMatch(matches []fuzzy.Match) (fuzzy.Match, error) {
+	fmt.Fprintln(os.Stderr, "Several fields match equally well:")
+	for i, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, m.Path)
+	}
+	fmt.Fprintf(os.Stderr, "Select a field [1-%d] (default 1): ", len(matches))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fuzzy.Match{}, fmt.Errorf("no field selected")
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return matches[0], nil
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(matches) {
+		return fuzzy.Match{}, fmt.Errorf("invalid selection: %s", line)
+	}
+	return matches[n-1], nil
+}
+
+// printPathValue prints the value at a JSONPath expression, jq -r style
+func printPathValue(usage *models.Usage, path string) error {
+	var data interface{}
+	if err := json.Unmarshal(usage.Raw, &data); err != nil {
+		return fmt.Errorf("failed to parse usage data: %w", err)
+	}
+
+	value, err := jsonpath.Get(data, path)
 	if err != nil {
 		return err
 	}
 
-	colors := format.NewColors(NoColor())
-
-	switch v := match.Value.(type) {
-	case float64:
-		fmt.Println(format.FormatNumber(v, match.Key, colors))
+	switch v := value.(type) {
 	case string:
-		fmt.Println(v)
-	case bool:
-		fmt.Println(v)
+		fmt.Fprintln(stdout, v)
+	case map[string]interface{}, []interface{}:
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize value: %w", err)
+		}
+		fmt.Fprintln(stdout, string(out))
+	case float64:
+		fmt.Fprintln(stdout, strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+		fmt.Fprintln(stdout, "null")
 	default:
-		fmt.Printf("%v\n", v)
+		fmt.Fprintf(stdout, "%v\n", v)
 	}
-
 	return nil
 }
 
@@ -122,17 +461,171 @@ func printJSON(usage *models.Usage) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(j)
+	fmt.Fprintln(stdout, j)
 	return nil
 }
 
 func printTable(usage *models.Usage) error {
-	colors := format.NewColors(NoColor())
+	colors := outputColors()
+
+	opts := format.TableOptions{Width: tableWidth()}
+	if showTrend {
+		// Table rows are keyed by field path, so attach trends to each window's utilization
+		opts.Trends = make(map[string]string)
+		for window, trend := range usageTrends() {
+			opts.Trends[window+"_utilization"] = trend
+		}
+	}
+	return format.WriteTable(stdout, usage, colors, tableFormats(), opts)
+}
+
+// failAtLimits parses --fail-at-window into limits keyed by window name
+func failAtLimits() (map[string]float64, error) {
+	if failAt < 0 || failAt > 100 {
+		return nil, fmt.Errorf("--fail-at must be between 0 and 100")
+	}
+
+	limits := make(map[string]float64, len(failAtWindow))
+	for window, value := range failAtWindow {
+		limit, err := strconv.ParseFloat(value, 64)
+		if err != nil || limit <= 0 || limit > 100 {
+			return nil, fmt.Errorf("invalid --fail-at-window %s=%s: percent must be between 0 and 100", window, value)
+		}
+		limits[format.WindowName(window)] = limit
+	}
+	return limits, nil
+}
+
+// checkFailAt returns a ThresholdError for the first window whose utilization
+// reached its --fail-at-window limit, or --fail-at if it has none
+func checkFailAt(usage *models.Usage, limits map[string]float64) error {
+	for _, w := range usage.Windows() {
+		limit, ok := limits[w.Name]
+		if !ok {
+			limit = failAt
+		}
+		if limit > 0 && w.Utilization >= limit {
+			return &apierrors.ThresholdError{Window: w.Name, Utilization: w.Utilization, Limit: limit}
+		}
+	}
+	return nil
+}
+
+func printByModel(usage *models.Usage) error {
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(format.ModelBreakdown(usage), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, format.ByModel(usage, outputColors(), tableFormats()))
+	fmt.Fprintln(stdout)
+	return nil
+}
+
+func printCompact(usage *models.Usage) error {
+	colors := outputColors()
+	opts := compactOptions()
+	if showTrend {
+		opts.Trends = usageTrends()
+	}
+	fmt.Fprintln(stdout, format.Compact(usage, colors, tableFormats(), opts))
+	return nil
+}
+
+// printInflux prints line protocol, tagged with the profile if one is active
+func printInflux(usage *models.Usage) error {
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, format.Influx(usage, time.Now(), map[string]string{"profile": profile}))
+	return nil
+}
+
+// printWaybar prints the JSON for a Waybar custom module
+func printWaybar(usage *models.Usage) error {
+	data, err := json.Marshal(format.Waybar(usage, outputColors(), tableFormats(), compactOptions()))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, string(data))
+	return nil
+}
+
+// compactOptions returns the compact output settings from config
+func compactOptions() format.CompactOptions {
+	var opts format.CompactOptions
+	if cfg != nil {
+		opts = format.CompactOptions{
+			Windows:    cfg.Compact.Windows,
+			Separator:  cfg.Compact.Separator,
+			HideResets: cfg.Compact.HideResets,
+		}
+	}
+	opts.Windows = selectWindows(opts.Windows)
+	return opts
+}
+
+// selectWindows narrows the windows of one-line output to those chosen by
+// --fields, in the order given, minus any excluded whole
+func selectWindows(windows []string) []string {
+	if len(fields) > 0 {
+		windows = nil
+		for _, path := range fieldPaths(fields) {
+			name, _, _ := strings.Cut(path, ".")
+			if format.WindowLabel(name) != name && !slices.Contains(windows, name) {
+				windows = append(windows, name)
+			}
+		}
+	}
+	if len(excludeFields) == 0 {
+		return windows
+	}
+
+	if len(windows) == 0 {
+		windows = format.DefaultCompactWindows
+	}
+	excluded := fieldPaths(excludeFields)
+	kept := make([]string, 0, len(windows))
+	for _, name := range windows {
+		if !slices.Contains(excluded, name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// outputLocale returns the configured locale, or English without a config
+func outputLocale() format.Locale {
+	if cfg == nil {
+		return format.Locale{}
+	}
+	return cfg.ResolvedLocale()
+}
+
+// tableWidth returns the terminal width to fit table rows to, or 0 when
+// output isn't going to the terminal
+func tableWidth() int {
+	if stdout != os.Stdout {
+		return 0
+	}
+	return format.TerminalWidth()
+}
+
+// tableFormats converts the configured format preset into table formats
+func tableFormats() format.Formats {
 	fmts := GetFormats()
-	formats := format.Formats{
-		Datetime: fmts.Datetime,
-		Date:     fmts.Date,
-		Time:     fmts.Time,
+	return format.Formats{
+		Datetime:     fmts.Datetime,
+		Date:         fmts.Date,
+		Time:         fmts.Time,
+		Relative:     RelativeTimes(),
+		RawDurations: cfg != nil && cfg.Formats.RawDurations,
+		Locale:       outputLocale(),
 	}
-	return format.Table(usage, colors, formats)
 }
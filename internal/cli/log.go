@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/redact"
+)
+
+var (
+	logLevel  string
+	logFormat string
+	logFile   string
+
+	traceHTTP     bool
+	traceHTTPFile string
+	// traceOut receives HTTP traces, or is nil when tracing is off
+	traceOut io.Writer
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn, or error (--verbose implies debug)")
+	RootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text or json")
+	RootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append logs to this file instead of stderr")
+	RootCmd.PersistentFlags().BoolVar(&traceHTTP, "trace-http", false, "Trace API requests, responses, and retries to stderr (credentials redacted)")
+	RootCmd.PersistentFlags().StringVar(&traceHTTPFile, "trace-http-file", "", "Append HTTP traces to this file (implies --trace-http)")
+}
+
+// setupLogging installs the default slog logger from --log-level,
+// --log-format, and --log-file
+func setupLogging() error {
+	level, err := parseLogLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	if !flagChanged("log-level") {
+		switch {
+		case quiet:
+			level = slog.LevelError
+		case verbose:
+			level = slog.LevelDebug
+		}
+	}
+
+	out, err := openLogOutput(logFile)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	// Logs are often pasted into issues, so keep credentials and IDs out
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact.ReplaceAttr}
+	var handler slog.Handler
+	switch strings.ToLower(logFormat) {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		return fmt.Errorf("unknown log format %q (want text or json)", logFormat)
+	}
+
+	slog.SetDefault(slog.New(handler))
+
+	if traceHTTP || traceHTTPFile != "" {
+		if traceOut, err = openLogOutput(traceHTTPFile); err != nil {
+			return fmt.Errorf("failed to open HTTP trace file: %w", err)
+		}
+	}
+	return nil
+}
+
+// openLogOutput opens path for appending, or returns stderr if path is empty.
+// Logs may hold account details, so new files are private.
+func openLogOutput(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stderr, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+}
+
+func parseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn, or error)", s)
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/testsupport"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockPort       int
+	mockFixture    string
+	mockLatency    time.Duration
+	mockErrorRate  float64
+	mockErrors     []int
+	mockRetryAfter time.Duration
+)
+
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Serve canned API responses for local development",
+	Long: `Emulate the usage API on localhost, so claude-limits and integrations can
+be developed against it by pointing CLAUDE_API_BASE_URL at it. No
+credentials are needed or checked.
+
+Responses come from --fixture: a file saved with --record, or any JSON
+document, such as --raw output, to serve as the usage response. Without
+one, sample usage is served.
+
+--latency slows every response, and --error-rate fails that fraction of
+requests with a status picked from --errors, to exercise timeouts, retries,
+and error handling.
+
+Examples:
+  claude-limits mock-server
+  claude-limits mock-server --port 8081 --fixture usage.json
+  claude-limits mock-server --latency 2s --error-rate 0.3 --errors 429,503
+  CLAUDE_API_BASE_URL=http://127.0.0.1:8081 claude-limits --format compact`,
+	RunE: runMockServer,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	mockServerCmd.Flags().IntVar(&mockPort, "port", 8081, "Port to listen on, on localhost (0 picks a free one)")
+	mockServerCmd.Flags().StringVar(&mockFixture, "fixture", "", "Fixture or usage JSON file to serve (default: sample usage)")
+	mockServerCmd.Flags().DurationVar(&mockLatency, "latency", 0, "Delay every response by this long")
+	mockServerCmd.Flags().Float64Var(&mockErrorRate, "error-rate", 0, "Fraction of requests to fail, from 0 to 1")
+	mockServerCmd.Flags().IntSliceVar(&mockErrors, "errors", testsupport.DefaultErrorStatuses, "HTTP statuses to fail with")
+	mockServerCmd.Flags().DurationVar(&mockRetryAfter, "retry-after", time.Second, "Retry-After of simulated 429 and 503 responses (0 leaves it out)")
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	if mockErrorRate < 0 || mockErrorRate > 1 {
+		return fmt.Errorf("--error-rate must be between 0 and 1, got %g", mockErrorRate)
+	}
+	for _, status := range mockErrors {
+		if status < 400 || status > 599 {
+			return fmt.Errorf("--errors takes HTTP error statuses, got %d", status)
+		}
+	}
+
+	var fixture *testsupport.Fixture
+	if mockFixture != "" {
+		f, err := testsupport.LoadFixture(mockFixture, api.UsageEndpoint.Path)
+		if err != nil {
+			return err
+		}
+		fixture = f
+	} else {
+		f, err := sampleFixture(time.Now())
+		if err != nil {
+			return err
+		}
+		fixture = f
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(mockPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := testsupport.NewMockServer(fixture,
+		testsupport.WithLatency(mockLatency),
+		testsupport.WithErrors(mockErrorRate, mockErrors...),
+		testsupport.WithRetryAfter(mockRetryAfter),
+	)
+	url := "http://" + ln.Addr().String()
+	fmt.Fprintf(stdout, "Mock API listening on %s\n\n  export CLAUDE_API_BASE_URL=%s\n\n", url, url)
+	return srv.Serve(cmd.Context(), ln)
+}
+
+// sampleFixture serves usage with reset times a few hours and days from now
+func sampleFixture(now time.Time) (*testsupport.Fixture, error) {
+	window := func(utilization float64, resetsIn time.Duration) map[string]any {
+		return map[string]any{
+			"utilization": utilization,
+			"resets_at":   now.Add(resetsIn).UTC().Truncate(time.Hour).Format(time.RFC3339),
+		}
+	}
+	body, err := json.Marshal(map[string]any{
+		"five_hour":            window(42, 3*time.Hour),
+		"seven_day":            window(17, 4*24*time.Hour),
+		"seven_day_opus":       window(8, 4*24*time.Hour),
+		"seven_day_oauth_apps": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &testsupport.Fixture{
+		Version: testsupport.FixtureVersion,
+		Interactions: []testsupport.Interaction{{
+			Path:   api.UsageEndpoint.Path,
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   body,
+		}},
+	}, nil
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/alerts"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertWarning  float64
+	alertCritical float64
+	noDesktop     bool
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send notifications when usage crosses thresholds",
+	Long: `Check usage once and send a notification for each window that crossed a
+threshold, or reset after being constrained, since the last check.
+
+Notifications are de-duplicated: each window alerts once when it enters the
+warning or critical level, and re-arms after its utilization drops (e.g., after
+a reset). Run this from cron, or use 'watch --notify' for continuous checking.
+
+Desktop notifications use notify-send (Linux), osascript (macOS), or toast
+notifications (Windows). Webhooks configured under 'alerts.webhooks' in
+config.yaml also receive every alert. Thresholds default to 'alerts.warning'
+and 'alerts.critical' from config, then 80 and 95.
+
+Each window can have its own policy under 'alerts.windows' (by name or short
+label): thresholds, a cooldown that holds back repeat alerts of the same
+level, and on_reset, which picks the resets that notify (constrained, the
+default, for windows that had reached warning; always; or never). Set
+remind_before, e.g. 1h, to be reminded that long before a window resets;
+with on_reset: always, you're told again once the quota is refreshed. A
+window's own thresholds take precedence over --warning and --critical.
+
+Examples:
+  claude-limits notify
+  claude-limits notify --warning 70 --critical 90
+  claude-limits notify --no-desktop     # webhooks only
+  claude-limits watch --notify`,
+	RunE: runNotify,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	addThresholdFlags(notifyCmd)
+	notifyCmd.Flags().BoolVar(&noDesktop, "no-desktop", false, "Only deliver to configured webhooks")
+}
+
+// addThresholdFlags registers the shared --warning/--critical alert flags
+func addThresholdFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&alertWarning, "warning", alerts.DefaultWarning, "Warning threshold (percent)")
+	cmd.Flags().Float64Var(&alertCritical, "critical", alerts.DefaultCritical, "Critical threshold (percent)")
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		return err
+	}
+	return checkAlerts(cmd, usage)
+}
+
+// alertThresholds resolves thresholds from flags, then config, then defaults
+func alertThresholds(cmd *cobra.Command) alerts.Thresholds {
+	thresholds := alerts.DefaultThresholds()
+	if cfg != nil {
+		if cfg.Alerts.Warning > 0 {
+			thresholds.Warning = cfg.Alerts.Warning
+		}
+		if cfg.Alerts.Critical > 0 {
+			thresholds.Critical = cfg.Alerts.Critical
+		}
+	}
+	if cmd.Flags().Changed("warning") {
+		thresholds.Warning = alertWarning
+	}
+	if cmd.Flags().Changed("critical") {
+		thresholds.Critical = alertCritical
+	}
+	return thresholds
+}
+
+// alertPolicies resolves each window's alert policy: thresholds from
+// alertThresholds, then the alerts section's cooldown, on_reset, and
+// remind_before, with alerts.windows overriding any of them for its window
+func alertPolicies(cmd *cobra.Command) (alerts.Policies, error) {
+	policies := alerts.Policies{Default: alerts.Policy{Thresholds: alertThresholds(cmd)}}
+	if cfg == nil {
+		return policies, nil
+	}
+
+	conf := cfg.Alerts
+	mode, err := alerts.ParseResetMode(conf.OnReset)
+	if err != nil {
+		return policies, fmt.Errorf("alerts.on_reset: %w", err)
+	}
+	policies.Default.Cooldown = conf.Cooldown
+	policies.Default.OnReset = mode
+	policies.Default.RemindBefore = conf.RemindBefore
+
+	for name, w := range conf.Windows {
+		policy := policies.Default
+		if w.Warning > 0 {
+			policy.Warning = w.Warning
+		}
+		if w.Critical > 0 {
+			policy.Critical = w.Critical
+		}
+		if w.Cooldown != nil {
+			policy.Cooldown = *w.Cooldown
+		}
+		if w.RemindBefore != nil {
+			policy.RemindBefore = *w.RemindBefore
+		}
+		if w.OnReset != "" {
+			if policy.OnReset, err = alerts.ParseResetMode(w.OnReset); err != nil {
+				return policies, fmt.Errorf("alerts.windows.%s.on_reset: %w", name, err)
+			}
+		}
+		if policies.Windows == nil {
+			policies.Windows = make(map[string]alerts.Policy)
+		}
+		policies.Windows[format.WindowName(name)] = policy
+	}
+	return policies, nil
+}
+
+// alertNotifiers builds the desktop notifier plus any configured webhooks
+func alertNotifiers() ([]alerts.Notifier, error) {
+	var notifiers []alerts.Notifier
+	if !noDesktop {
+		notifiers = append(notifiers, alerts.Desktop{})
+	}
+
+	if cfg != nil {
+		for _, wh := range cfg.Alerts.Webhooks {
+			w, err := alerts.NewWebhook(wh.URL, wh.Type, wh.Template)
+			if err != nil {
+				return nil, fmt.Errorf("invalid webhook config: %w", err)
+			}
+			notifiers = append(notifiers, w)
+		}
+	}
+
+	return notifiers, nil
+}
+
+// checkAlerts dispatches notifications for new threshold crossings and
+// persists the de-duplication state
+func checkAlerts(cmd *cobra.Command, usage *models.Usage) error {
+	profile, settings, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	notifiers, err := alertNotifiers()
+	if err != nil {
+		return err
+	}
+
+	policies, err := alertPolicies(cmd)
+	if err != nil {
+		return err
+	}
+
+	tracker := alerts.NewTracker(alerts.StatePath(profile), policies)
+
+	fired := tracker.Check(usage)
+	if before := cfg.Alerts.TokenExpiry; before > 0 {
+		fired = append(fired, tracker.CheckExpiry(tokenExpiry(cmd.Context(), settings), before)...)
+	}
+	slog.Debug("checked alerts", "fired", len(fired))
+
+	dispatchErr := alerts.Dispatch(cmd.Context(), notifiers, fired)
+
+	if err := tracker.Save(); err != nil {
+		return err
+	}
+	return dispatchErr
+}
+
+// tokenExpiry returns when the profile's access token expires, re-reading
+// the credentials so a token Claude Code refreshed since the last check
+// counts. It's zero when unknown.
+func tokenExpiry(ctx context.Context, settings config.Profile) time.Time {
+	creds, err := loadCredentials(ctx, settings)
+	if err != nil {
+		slog.Debug("failed to read token expiry", "error", err)
+		return time.Time{}
+	}
+	if !creds.HasExpiry() {
+		return time.Time{}
+	}
+	return creds.ExpiresAt
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/transcripts"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	orgUsageBy    string
+	orgUsageSince time.Duration
+)
+
+var orgUsageCmd = &cobra.Command{
+	Use:   "org-usage",
+	Short: "Report organization token usage and cost from the Admin API",
+	Long: `Report the organization's token usage and cost from the Anthropic Admin
+API, grouped by model, workspace, API key, or user. Needs an admin API key
+(sk-ant-admin...) in ANTHROPIC_API_KEY.
+
+--by user reports Claude Code usage per user from the Claude Code usage
+report, with its estimated cost. The other groupings come from the messages
+usage report, with costs from the cost report where it can be split the
+same way (not per API key).
+
+Output is a table, or CSV or JSON with --format csv or --format json.
+
+Examples:
+  claude-limits org-usage
+  claude-limits org-usage --by workspace --since 720h
+  claude-limits org-usage --by user --format csv > usage.csv`,
+	RunE: runOrgUsage,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	orgUsageCmd.Flags().StringVar(&orgUsageBy, "by", "model", "Group by model, workspace, api-key, or user")
+	orgUsageCmd.Flags().DurationVar(&orgUsageSince, "since", 7*24*time.Hour, "Report usage from this long ago until now")
+}
+
+// orgUsageGroups maps --by to the usage and cost report groupings. The cost
+// report can't be split by API key.
+var orgUsageGroups = map[string]struct{ usage, cost string }{
+	"model":     {usage: "model", cost: "description"},
+	"workspace": {usage: "workspace_id", cost: "workspace_id"},
+	"api-key":   {usage: "api_key_id"},
+}
+
+// orgUsageRow is one group's usage. Cost is in dollars and nil when the
+// grouping has no cost data.
+type orgUsageRow struct {
+	Name string `json:"name"`
+	transcripts.Tokens
+	Cost *float64 `json:"cost_usd,omitempty"`
+}
+
+func (r *orgUsageRow) addCost(cost float64) {
+	if r.Cost != nil {
+		cost += *r.Cost
+	}
+	r.Cost = &cost
+}
+
+// orgUsageReport is the JSON shape of 'org-usage'
+type orgUsageReport struct {
+	Since time.Time     `json:"since"`
+	By    string        `json:"by"`
+	Rows  []orgUsageRow `json:"rows"`
+	Total orgUsageRow   `json:"total"`
+}
+
+func runOrgUsage(cmd *cobra.Command, args []string) error {
+	switch GetOutputFormat() {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("org-usage supports table, csv, and json output")
+	}
+	if orgUsageSince <= 0 {
+		return fmt.Errorf("--since must be positive, got %s", orgUsageSince)
+	}
+
+	client, err := newAPIKeyClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since := now.Add(-orgUsageSince)
+	rows := map[string]*orgUsageRow{}
+	row := func(name string) *orgUsageRow {
+		if rows[name] == nil {
+			rows[name] = &orgUsageRow{Name: name}
+		}
+		return rows[name]
+	}
+
+	ctx := cmd.Context()
+	switch orgUsageBy {
+	case "user":
+		records, err := client.GetClaudeCodeReport(ctx, since, now)
+		if err != nil {
+			return fmt.Errorf("failed to get Claude Code usage report: %w", err)
+		}
+		for _, rec := range records {
+			r := row(rec.ActorName())
+			for _, m := range rec.ModelBreakdown {
+				r.Tokens = addTokens(r.Tokens, transcripts.Tokens{
+					Input:         m.Tokens.Input,
+					Output:        m.Tokens.Output,
+					CacheCreation: m.Tokens.CacheCreation,
+					CacheRead:     m.Tokens.CacheRead,
+				})
+				r.addCost(float64(m.EstimatedCost.Amount) / 100)
+			}
+		}
+
+	case "model", "workspace", "api-key":
+		group := orgUsageGroups[orgUsageBy]
+		buckets, err := client.GetUsageReport(ctx, api.UsageReportQuery{Start: since, BucketWidth: "1d", GroupBy: []string{group.usage}})
+		if err != nil {
+			return fmt.Errorf("failed to get usage report: %w", err)
+		}
+		for _, b := range buckets {
+			for _, res := range b.Results {
+				r := row(orgUsageName(res.Model, res.WorkspaceID, res.APIKeyID))
+				r.Tokens = addTokens(r.Tokens, reportTokens(res))
+			}
+		}
+
+		if group.cost != "" {
+			costs, err := client.GetCostReport(ctx, since, time.Time{}, []string{group.cost})
+			if err != nil {
+				return fmt.Errorf("failed to get cost report: %w", err)
+			}
+			for _, b := range costs {
+				for _, res := range b.Results {
+					model := res.Model
+					if orgUsageBy == "model" && model == nil {
+						model = res.Description
+					}
+					row(orgUsageName(model, res.WorkspaceID, nil)).addCost(float64(res.Amount) / 100)
+				}
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid --by %q (use model, workspace, api-key, or user)", orgUsageBy)
+	}
+
+	report := orgUsageReport{Since: since, By: orgUsageBy, Total: orgUsageRow{Name: "Total"}}
+	for _, r := range rows {
+		report.Rows = append(report.Rows, *r)
+		report.Total.Tokens = addTokens(report.Total.Tokens, r.Tokens)
+		if r.Cost != nil {
+			report.Total.addCost(*r.Cost)
+		}
+	}
+	slices.SortFunc(report.Rows, func(a, b orgUsageRow) int {
+		return cmp.Or(cmp.Compare(b.Total(), a.Total()), cmp.Compare(a.Name, b.Name))
+	})
+
+	switch GetOutputFormat() {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	case "csv":
+		return writeOrgUsageCSV(report)
+	}
+	printOrgUsage(report)
+	return nil
+}
+
+// orgUsageName names a usage report group by whichever field it's grouped by
+func orgUsageName(model, workspace, apiKey *string) string {
+	switch {
+	case model != nil:
+		return *model
+	case workspace != nil:
+		return *workspace
+	case apiKey != nil:
+		return *apiKey
+	case orgUsageBy == "workspace":
+		return "Default workspace"
+	}
+	return "unknown"
+}
+
+func printOrgUsage(report orgUsageReport) {
+	colors := outputColors()
+	fmt.Fprintf(stdout, "%sOrganization usage by %s since %s%s\n\n", colors.Bold, report.By, report.Since.Local().Format(GetFormats().Datetime), colors.Reset)
+	if len(report.Rows) == 0 {
+		fmt.Fprintln(stdout, "No usage in this period")
+		return
+	}
+
+	const row = "%-40s %8s %8s %8s %8s %8s %10s\n"
+	fmt.Fprintf(stdout, row, "NAME", "INPUT", "OUTPUT", "CACHE+", "CACHED", "TOTAL", "COST")
+	for _, r := range append(report.Rows, report.Total) {
+		t := r.Tokens
+		fmt.Fprintf(stdout, row, truncateLeft(r.Name, 40), tokenCount(t.Input), tokenCount(t.Output), tokenCount(t.CacheCreation), tokenCount(t.CacheRead), tokenCount(t.Total()), orgUsageCost(r.Cost))
+	}
+}
+
+func orgUsageCost(cost *float64) string {
+	if cost == nil {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", *cost)
+}
+
+func writeOrgUsageCSV(report orgUsageReport) error {
+	w := csv.NewWriter(stdout)
+	_ = w.Write([]string{"name", "input_tokens", "output_tokens", "cache_creation_input_tokens", "cache_read_input_tokens", "total_tokens", "cost_usd"})
+	for _, r := range report.Rows {
+		cost := ""
+		if r.Cost != nil {
+			cost = strconv.FormatFloat(*r.Cost, 'f', 2, 64)
+		}
+		t := r.Tokens
+		_ = w.Write([]string{
+			r.Name,
+			strconv.FormatInt(t.Input, 10),
+			strconv.FormatInt(t.Output, 10),
+			strconv.FormatInt(t.CacheCreation, 10),
+			strconv.FormatInt(t.CacheRead, 10),
+			strconv.FormatInt(t.Total(), 10),
+			cost,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/benjaminabbitt/claude-limits/internal/alerts"
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/debugdump"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/transcripts"
+	"github.com/benjaminabbitt/claude-limits/internal/update"
+
+	"github.com/spf13/cobra"
+)
+
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Show where config, cache, credentials, and settings live",
+	Long: `Show the files and directories claude-limits reads and writes, resolved
+the way every other command resolves them: --config, --profile,
+--cache-dir, --state-dir, --claude-dir, CLAUDE_LIMITS_CONFIG,
+CLAUDE_LIMITS_PROFILE, CLAUDE_LIMITS_CACHE_DIR, CLAUDE_LIMITS_STATE_DIR,
+CLAUDE_CONFIG_DIR, XDG_CONFIG_HOME, XDG_CACHE_HOME, XDG_STATE_HOME, and
+STARSHIP_CONFIG are all honored. Paths that don't exist yet are marked.
+Under WSL, Claude Code's directory may be the Windows user profile's; the
+claude_dir entry notes it.
+
+Examples:
+  claude-limits paths
+  claude-limits paths --format json
+  claude-limits paths --format json | jq -r '.[] | select(.name == "config") | .path'`,
+	RunE: runPaths,
+	Args: cobra.NoArgs,
+}
+
+// pathEntry is one resolved location
+type pathEntry struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+	// Note says where the path came from, when that isn't obvious
+	Note string `json:"note,omitempty"`
+}
+
+func runPaths(cmd *cobra.Command, args []string) error {
+	name, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	credentials := profile.Credentials
+	if credentials == "" {
+		credentials = auth.DefaultCredentialsPath()
+	}
+	c := cache.New(false, cache.WithProfile(name))
+
+	entries := []pathEntry{
+		{Name: "config", Path: config.ResolvePath(configPath)},
+		claudeDirEntry(),
+		{Name: "credentials", Path: credentials},
+		{Name: "cache_dir", Path: c.Dir()},
+		{Name: "cache", Path: c.File()},
+		{Name: "state_dir", Path: cache.StateDir()},
+		{Name: "history", Path: history.PathForProfile(name)},
+		{Name: "alerts", Path: alerts.StatePath(name)},
+		{Name: "update_check", Path: update.StatePath()},
+		{Name: "errors_log", Path: debugdump.ErrorLogPath()},
+		{Name: "user_settings", Path: claudecode.DefaultUserSettingsPath()},
+		{Name: "project_settings", Path: absPath(claudecode.DefaultProjectSettingsPath())},
+		{Name: "transcripts", Path: transcripts.DefaultDir()},
+		{Name: "starship_config", Path: defaultStarshipConfig()},
+	}
+	for i := range entries {
+		if entries[i].Path == "" {
+			continue
+		}
+		_, err := os.Stat(entries[i].Path)
+		entries[i].Exists = err == nil
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	colors := outputColors()
+	for _, e := range entries {
+		path := e.Path
+		if path == "" {
+			path = "(unavailable)"
+		}
+		missing := ""
+		if !e.Exists {
+			missing = fmt.Sprintf(" %s(missing)%s", colors.Yellow, colors.Reset)
+		}
+		note := ""
+		if e.Note != "" {
+			note = " (" + e.Note + ")"
+		}
+		fmt.Fprintf(stdout, "%-18s %s%s%s\n", e.Name, path, note, missing)
+	}
+	return nil
+}
+
+// claudeDirEntry is Claude Code's config directory, noting when it's not
+// the default
+func claudeDirEntry() pathEntry {
+	dir, loc := claudecode.Locate()
+	entry := pathEntry{Name: "claude_dir", Path: dir}
+	switch loc {
+	case claudecode.LocationEnv:
+		entry.Note = "from " + claudecode.ConfigDirEnv
+	case claudecode.LocationWindows:
+		entry.Note = "Windows profile, via WSL"
+	}
+	return entry
+}
+
+// absPath returns path made absolute, or path unchanged if that fails
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/redact"
+)
+
+// quiet discards normal output and logs below error level, for cron jobs
+// and scheduled tasks that mail whatever a command prints
+var quiet bool
+
+func init() {
+	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print nothing but errors, each as one line (for cron)")
+}
+
+// PrintError reports a command's error on stderr, redacted, with a hint for
+// fixing it underneath when there is one. With --format json it is
+// a JSON object, e.g.
+//
+//	{"error":{"type":"auth","message":"...","exit_code":3,"status":401,"hint":"..."}}
+//
+// With --quiet it is a single line with a stable code, e.g.
+//
+//	claude-limits: error code=auth exit=3 msg="credentials not found"
+func PrintError(err error) {
+	err = redact.Error(err)
+	if GetOutputFormat() == "json" {
+		data, jsonErr := json.Marshal(struct {
+			Error apierrors.Report `json:"error"`
+		}{apierrors.NewReport(err)})
+		if jsonErr == nil {
+			fmt.Fprintln(os.Stderr, string(data))
+			return
+		}
+	}
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if hint := apierrors.Hint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, "Hint:", hint)
+		}
+		return
+	}
+	// %q keeps multi-line messages on one line
+	fmt.Fprintf(os.Stderr, "claude-limits: error code=%s exit=%d msg=%q\n", apierrors.Code(err), apierrors.ExitCode(err), err.Error())
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+)
+
+// watchConfig watches the config file, sending each reloaded config on the
+// returned channel until ctx is done. A config that fails to load is logged
+// and skipped, keeping the current one. If the file can't be watched, the
+// channel is nil and never receives.
+func watchConfig(ctx context.Context) <-chan *config.Config {
+	reloads := make(chan *config.Config, 1)
+	err := config.Watch(ctx, configPath, func(c *config.Config, err error) {
+		if err != nil {
+			slog.Warn("config reload failed; keeping the current settings", "error", err)
+			return
+		}
+		// Only the latest config matters if the last wasn't applied yet
+		select {
+		case <-reloads:
+		default:
+		}
+		reloads <- c
+	})
+	if err != nil {
+		slog.Debug("not watching the config file for changes", "error", err)
+		return nil
+	}
+	return reloads
+}
+
+// applyConfig makes c the active config and re-resolves the flag defaults
+// it provides
+func applyConfig(c *config.Config) {
+	cfg = c
+	applyConfigDefaults()
+	slog.Info("config reloaded", "path", config.ResolvePath(configPath))
+}
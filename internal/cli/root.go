@@ -1,20 +1,61 @@
 package cli
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
 	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/telemetry"
 	"github.com/benjaminabbitt/claude-limits/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputFormat string
-	verbose      bool
-	noColor      bool
-	cacheTTL     int
-	configPath   string
-	cfg          *config.Config
+	outputFormat  string
+	verbose       bool
+	noColor       bool
+	relativeTimes bool
+	cacheTTL      int
+	configPath    string
+	profileName   string
+	cacheDir      string
+	stateDir      string
+	claudeDir     string
+	cfg           *config.Config
+
+	// API request settings; see apiClientOptions
+	apiTimeout      time.Duration
+	maxRetries      int
+	retryBackoff    time.Duration
+	retryMaxBackoff time.Duration
+	proxyURL        string
+	caCert          string
+	insecureTLS     bool
+
+	// activeCmd is the command being run, for checking which flags were set
+	activeCmd *cobra.Command
+
+	// stdout receives command output, so it can be redirected with SetOut
+	// when embedding the CLI or capturing its output
+	stdout io.Writer = os.Stdout
+
+	// otel exports traces and metrics when CLAUDE_LIMITS_OTEL_ENDPOINT is
+	// set, and is nil otherwise
+	otel = telemetry.FromEnv()
 )
 
+// telemetryFlushTimeout bounds the export at exit, so a down collector
+// doesn't hang the command
+const telemetryFlushTimeout = 5 * time.Second
+
 // RootCmd is the root command for the CLI
 var RootCmd = &cobra.Command{
 	Use:     "claude-limits [query]",
@@ -22,9 +63,31 @@ var RootCmd = &cobra.Command{
 	Long:    `A CLI tool to check your Claude.ai usage and limits for Pro/Max subscriptions.`,
 	Version: version.Version,
 	Args:    cobra.MaximumNArgs(1),
+	// main prints the error once and picks the exit code
+	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and args parsed; errors from here on aren't usage mistakes
+		cmd.SilenceUsage = true
+
 		// Load configuration file
 		cfg = config.LoadOrDefault(configPath)
+		activeCmd = cmd
+		stdout = cmd.OutOrStdout()
+		if quiet {
+			stdout = io.Discard
+		}
+		applyDirFlags()
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		// doctor reports this itself, and --fix must run under --strict-perms
+		if cfg.HasSecrets() && cmd != doctorCmd {
+			if err := checkPermissions(config.ResolvePath(configPath)); err != nil {
+				return err
+			}
+		}
+		applyConfigDefaults()
+		startUpdateCheck()
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -35,14 +98,53 @@ var RootCmd = &cobra.Command{
 
 func init() {
 	RootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/claude-limits/config.yaml)")
-	RootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table", "Output format: table or json")
+	RootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table", "Output format: table, json, compact, influx, waybar, starship, or image")
 	RootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	RootCmd.PersistentFlags().BoolVar(&relativeTimes, "relative", false, "Show reset times as countdowns, e.g. \"in 2h 14m\"")
 	RootCmd.PersistentFlags().IntVar(&cacheTTL, "cache", 30, "Cache TTL in seconds (0 to disable)")
+	RootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Named profile from config (env: CLAUDE_LIMITS_PROFILE)")
+	RootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Cache directory (env: "+cache.DirEnv+")")
+	RootCmd.PersistentFlags().StringVar(&stateDir, "state-dir", "", "History and alert state directory (env: "+cache.StateDirEnv+")")
+	RootCmd.PersistentFlags().StringVar(&claudeDir, "claude-dir", "", "Claude Code config directory with credentials, settings, and transcripts (env: "+claudecode.ConfigDirEnv+", default: ~/.claude)")
+	RootCmd.PersistentFlags().DurationVar(&apiTimeout, "timeout", api.DefaultTimeout, "Time limit for each API request attempt")
+	RootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", api.DefaultMaxRetries, "Retries for failed API requests (0 disables)")
+	RootCmd.PersistentFlags().DurationVar(&retryBackoff, "retry-backoff", api.DefaultInitialBackoff, "Wait before the first retry, doubling after each")
+	RootCmd.PersistentFlags().DurationVar(&retryMaxBackoff, "retry-max-backoff", api.DefaultMaxBackoff, "Longest wait between retries")
+	RootCmd.PersistentFlags().StringVar(&proxyURL, "proxy", "", "Proxy URL: http, https, or socks5 (default: HTTPS_PROXY/NO_PROXY)")
+	RootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "", "PEM file of extra root certificates to trust")
+	RootCmd.PersistentFlags().BoolVar(&insecureTLS, "insecure-skip-verify", false, "Disable TLS certificate verification (debugging only)")
 
 	RootCmd.AddCommand(limitsCmd)
 	RootCmd.AddCommand(serveCmd)
 	RootCmd.AddCommand(installScriptCmd)
+	RootCmd.AddCommand(watchCmd)
+	RootCmd.AddCommand(historyCmd)
+	RootCmd.AddCommand(statsCmd)
+	RootCmd.AddCommand(budgetCmd)
+	RootCmd.AddCommand(teamCmd)
+	RootCmd.AddCommand(shareCmd)
+	RootCmd.AddCommand(ghaSummaryCmd)
+	RootCmd.AddCommand(badgeCmd)
+	RootCmd.AddCommand(exportCmd)
+	RootCmd.AddCommand(importCmd)
+	RootCmd.AddCommand(sessionsCmd)
+	RootCmd.AddCommand(notifyCmd)
+	RootCmd.AddCommand(alertsCmd)
+	RootCmd.AddCommand(checkCmd)
+	RootCmd.AddCommand(daemonCmd)
+	RootCmd.AddCommand(statuslineCmd)
+	RootCmd.AddCommand(installCmd)
+	RootCmd.AddCommand(uninstallCmd)
+	RootCmd.AddCommand(authCmd)
+	RootCmd.AddCommand(whoamiCmd)
+	RootCmd.AddCommand(orgUsageCmd)
+	RootCmd.AddCommand(mockServerCmd)
+	RootCmd.AddCommand(configCmd)
+	RootCmd.AddCommand(debugCmd)
+	RootCmd.AddCommand(doctorCmd)
+	RootCmd.AddCommand(pathsCmd)
+	RootCmd.AddCommand(genCmd)
 }
 
 // GetOutputFormat returns the output format setting
@@ -60,11 +162,173 @@ func NoColor() bool {
 	return noColor
 }
 
-// GetCacheTTL returns the cache TTL in seconds
+// RelativeTimes returns true if reset times should render as countdowns,
+// from --relative or else the config's formats.relative
+func RelativeTimes() bool {
+	if !flagChanged("relative") && cfg != nil {
+		return cfg.Formats.Relative
+	}
+	return relativeTimes
+}
+
+// GetCacheTTL returns the cache TTL in seconds, or 0 if the config turns
+// caching off
 func GetCacheTTL() int {
+	if !cacheEnabled() {
+		return 0
+	}
 	return cacheTTL
 }
 
+// cacheEnabled reports whether usage may be cached on disk at all.
+// Recording and replaying always go to the API, and never cache what they
+// get.
+func cacheEnabled() bool {
+	if recordFile != "" || replayFile != "" {
+		return false
+	}
+	return cfg == nil || cfg.CacheEnabled()
+}
+
+// GetProfile resolves the active profile from --profile, CLAUDE_LIMITS_PROFILE,
+// or the config's default_profile, in that order
+func GetProfile() (string, config.Profile, error) {
+	name := profileName
+	if name == "" {
+		name = os.Getenv("CLAUDE_LIMITS_PROFILE")
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return cfg.ResolveProfile(name)
+}
+
+// FlushTelemetry exports buffered traces and metrics. Failures are logged at
+// debug level, since telemetry shouldn't break the command.
+func FlushTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryFlushTimeout)
+	defer cancel()
+	if err := otel.Flush(ctx); err != nil {
+		slog.Debug("failed to export telemetry", "error", err)
+	}
+}
+
+// flagChanged reports whether a flag was set explicitly on the command line
+func flagChanged(name string) bool {
+	return activeCmd != nil && activeCmd.Flags().Changed(name)
+}
+
+// applyDirFlags exports --cache-dir and --state-dir as their environment
+// variables, so every package resolving default paths, and any child
+// process, sees them
+func applyDirFlags() {
+	if cacheDir != "" {
+		os.Setenv(cache.DirEnv, cacheDir)
+	}
+	if stateDir != "" {
+		os.Setenv(cache.StateDirEnv, stateDir)
+	}
+	if claudeDir != "" {
+		os.Setenv(claudecode.ConfigDirEnv, config.ExpandHome(claudeDir))
+	}
+}
+
+// apiClientOptions resolves request settings from flags, then the config's
+// api section, then defaults
+func apiClientOptions() ([]api.ClientOption, error) {
+	var conf config.API
+	if cfg != nil {
+		conf = cfg.API
+	}
+
+	timeout := conf.Timeout
+	if flagChanged("timeout") || timeout <= 0 {
+		timeout = apiTimeout
+	}
+
+	policy := api.DefaultRetryPolicy()
+	if conf.MaxRetries != nil {
+		policy.MaxRetries = *conf.MaxRetries
+	}
+	if conf.InitialBackoff > 0 {
+		policy.InitialBackoff = conf.InitialBackoff
+	}
+	if conf.MaxBackoff > 0 {
+		policy.MaxBackoff = conf.MaxBackoff
+	}
+	if flagChanged("max-retries") {
+		policy.MaxRetries = maxRetries
+	}
+	if flagChanged("retry-backoff") {
+		policy.InitialBackoff = retryBackoff
+	}
+	if flagChanged("retry-max-backoff") {
+		policy.MaxBackoff = retryMaxBackoff
+	}
+
+	opts := []api.ClientOption{api.WithTimeout(timeout), api.WithRetryPolicy(policy), api.WithTelemetry(otel), api.WithTrace(traceOut)}
+
+	transportConf := api.TransportConfig{
+		Proxy:              conf.Proxy,
+		CACert:             config.ExpandHome(conf.CACert),
+		InsecureSkipVerify: conf.InsecureSkipVerify || insecureTLS,
+	}
+	if proxyURL != "" {
+		transportConf.Proxy = proxyURL
+	}
+	if caCert != "" {
+		transportConf.CACert = caCert
+	}
+	var transport http.RoundTripper
+	if !transportConf.IsZero() {
+		if transportConf.InsecureSkipVerify {
+			slog.Warn("TLS certificate verification is disabled")
+		}
+		t, err := api.NewTransport(transportConf)
+		if err != nil {
+			return nil, err
+		}
+		transport = t
+	}
+	transport, err := fixtureTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+	if transport != nil {
+		opts = append(opts, api.WithTransport(transport))
+	}
+
+	return opts, nil
+}
+
+// newAPIClient creates an API client configured from flags and config
+func newAPIClient(accessToken string) (*api.Client, error) {
+	opts, err := apiClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(accessToken, opts...), nil
+}
+
+// outputColors returns the colors for stdout, honoring --no-color and the
+// config's theme, colors, and thresholds
+func outputColors() format.Colors {
+	return applyColorConfig(format.NewColors(NoColor()))
+}
+
+// applyColorConfig applies the config's colors to base. A bad color setting
+// is reported and the base palette used; 'config validate' pinpoints it.
+func applyColorConfig(base format.Colors) format.Colors {
+	if cfg == nil {
+		return base
+	}
+	colors, err := cfg.ApplyColors(base)
+	if err != nil {
+		slog.Warn("ignoring color config", "error", err)
+	}
+	return colors
+}
+
 // GetFormats returns the resolved format settings from config
 func GetFormats() config.FormatPreset {
 	if cfg != nil {
@@ -1,31 +1,216 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
 
-	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
 	"github.com/benjaminabbitt/claude-limits/internal/mcp"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/redact"
+	"github.com/benjaminabbitt/claude-limits/internal/usecase"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	servePollInterval int
+	serveTransport    string
+	serveListen       string
+	serveAuthToken    string
+	serveMaxDuration  time.Duration
+)
+
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start MCP server",
 	Long: `Start an MCP (Model Context Protocol) server that exposes usage tools.
 
 Authentication uses OAuth credentials from Claude Code (~/.claude/.credentials.json).
-Make sure you have authenticated with Claude Code first.`,
+Make sure you have authenticated with Claude Code first. Credentials are
+re-read on each fetch, so tokens Claude Code refreshes are picked up, and
+tools read through the usage cache (--cache) like other commands.
+
+Usage is also published as the resources usage://current and usage://history.
+The server polls usage every --poll-interval seconds, records it to history,
+and sends resource-updated notifications when it changes. Use
+--poll-interval 0 to disable polling. Changes to serve.poll_interval in the
+config file take effect without a restart.
+
+Transports:
+  stdio  Serve a single client over stdin/stdout (default)
+  http   Serve remote clients over HTTP with Server-Sent Events:
+         GET /sse opens the event stream, POST /message sends requests.
+         GET /healthz reports the server is up, and GET /readyz that
+         usage was fetched in the last 5 minutes (or two poll
+         intervals) or is cached and fresh; neither needs the token.
+         It also serves history to Grafana's JSON datasource plugin:
+         GET / tests the connection, POST /search lists the windows,
+         and POST /query returns their utilization over time
+
+The http transport listens on localhost:8765 by default; use --listen :8765
+to accept connections on all interfaces (e.g. in a container). Set
+--auth-token or CLAUDE_LIMITS_MCP_TOKEN to require
+"Authorization: Bearer <token>" on every request but the health checks.
+Requests other than the SSE stream time out after --max-request-duration.
+On SIGINT or SIGTERM the server stops accepting connections and gives
+in-flight requests 5 seconds to finish.
+
+Examples:
+  claude-limits serve
+  claude-limits serve --transport http
+  CLAUDE_LIMITS_MCP_TOKEN=secret claude-limits serve --transport http --listen :8765`,
 	RunE: runServe,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	serveCmd.Flags().IntVar(&servePollInterval, "poll-interval", 60, "Seconds between usage polls for resource notifications (0 disables; config: serve.poll_interval)")
+	serveCmd.Flags().StringVar(&serveTransport, "transport", "stdio", "Transport: stdio or http")
+	serveCmd.Flags().StringVar(&serveListen, "listen", mcp.DefaultListenAddr, "Address to listen on with --transport http")
+	serveCmd.Flags().StringVar(&serveAuthToken, "auth-token", "", "Bearer token required by the http transport (env: CLAUDE_LIMITS_MCP_TOKEN)")
+	serveCmd.Flags().DurationVar(&serveMaxDuration, "max-request-duration", time.Minute, "Time out http transport requests after this long, except the SSE stream (0 disables)")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
-	creds, err := auth.Load("")
+	if servePollInterval < 0 {
+		return fmt.Errorf("--poll-interval must not be negative")
+	}
+	if serveMaxDuration < 0 {
+		return fmt.Errorf("--max-request-duration must not be negative")
+	}
+	if serveTransport != "stdio" && serveTransport != "http" {
+		return fmt.Errorf("unknown transport: %s (use stdio or http)", serveTransport)
+	}
+
+	profileName, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	// Fail fast without credentials; fetches re-read them, so a token
+	// Claude Code refreshes while serving is picked up
+	creds, err := loadCredentials(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+
+	first, err := newServeUsage(profileName, profile)
 	if err != nil {
 		return err
 	}
+	var current atomic.Pointer[serveUsage]
+	current.Store(first)
+
+	interval := servePollEvery()
+	// Tools read through the cache like the CLI; polls refresh it, and
+	// record history as they do. Errors reach MCP clients, so they're
+	// redacted like the CLI's.
+	fetch := func(refresh bool) mcp.UsageFunc {
+		return func(ctx context.Context) (*models.Usage, error) {
+			s := current.Load().cached
+			if refresh {
+				s = current.Load().refresh
+			}
+			usage, err := s.Usage(ctx)
+			return usage, redact.Error(err)
+		}
+	}
+	srv := mcp.NewServer(fetch(false),
+		mcp.WithPollInterval(interval),
+		mcp.WithPollUsage(fetch(true)),
+		mcp.WithHistoryPath(history.PathForProfile(profileName)),
+		mcp.WithRecording(false),
+		mcp.WithMaxRequestDuration(serveMaxDuration),
+		// Fresh cached usage is as good as a fetch for readiness
+		mcp.WithReadyCheck(func() bool {
+			_, err := cache.New(false, cache.WithProfile(profileName)).Read(current.Load().cacheTTL)
+			return err == nil
+		}),
+	)
+	go reloadServeConfig(cmd.Context(), srv, interval, func() {
+		name, profile, err := cfg.ResolveProfile(profileName)
+		if err == nil {
+			var next *serveUsage
+			if next, err = newServeUsage(name, profile); err == nil {
+				current.Store(next)
+				return
+			}
+		}
+		slog.Warn("keeping the previous usage settings", "error", err)
+	})
+
+	if serveTransport == "http" {
+		token := serveAuthToken
+		if token == "" {
+			token = os.Getenv("CLAUDE_LIMITS_MCP_TOKEN")
+		}
+		if token == "" {
+			slog.Warn("no --auth-token set; the MCP endpoint is unauthenticated")
+		}
+
+		slog.Info("starting MCP server", "url", "http://"+serveListen+"/sse", "subscription", creds.SubscriptionType)
+		return srv.ListenAndServe(cmd.Context(), serveListen, token)
+	}
 
-	fmt.Printf("Starting MCP server (subscription: %s)\n", creds.SubscriptionType)
+	// stdout carries the protocol, so logs must not go there
+	slog.Info("starting MCP server", "transport", "stdio", "subscription", creds.SubscriptionType)
+	return srv.ServeStdio(cmd.Context())
+}
 
-	return mcp.Serve(creds.AccessToken)
+// serveUsage is how a running server fetches usage, built from one config.
+// Reloads build another and swap it in whole, so fetches in flight never
+// read the config while it changes.
+type serveUsage struct {
+	cached   *usecase.UsageService
+	refresh  *usecase.UsageService
+	cacheTTL time.Duration
+}
+
+func newServeUsage(profileName string, profile config.Profile) (*serveUsage, error) {
+	cached, err := usageService(profileName, profile, false)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := usageService(profileName, profile, true)
+	if err != nil {
+		return nil, err
+	}
+	return &serveUsage{cached: cached, refresh: refresh, cacheTTL: time.Duration(GetCacheTTL()) * time.Second}, nil
+}
+
+// servePollEvery resolves the poll interval from --poll-interval, then the
+// config's serve.poll_interval
+func servePollEvery() time.Duration {
+	if !flagChanged("poll-interval") && cfg != nil && cfg.Serve.PollInterval > 0 {
+		return cfg.Serve.PollInterval
+	}
+	return time.Duration(servePollInterval) * time.Second
+}
+
+// reloadServeConfig applies config file changes to a running server until
+// ctx is done. Fetches read only what rebuild publishes from the new
+// config, so the globals applyConfig rewrites aren't shared with them.
+func reloadServeConfig(ctx context.Context, srv *mcp.Server, interval time.Duration, rebuild func()) {
+	reloads := watchConfig(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case c := <-reloads:
+			applyConfig(c)
+			rebuild()
+			if next := servePollEvery(); next != interval {
+				interval = next
+				srv.SetPollInterval(interval)
+				slog.Info("poll interval changed", "interval", interval)
+			}
+		}
+	}
 }
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/transcripts"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionsSince time.Duration
+	sessionsBy    string
+	sessionsDir   string
+	sessionsLimit int
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Show token usage per project or session from Claude Code transcripts",
+	Long: `Total the tokens recorded in Claude Code's local session transcripts
+(~/.claude/projects) per project or per session, to see which work is using up
+your limits.
+
+By default, covers the current weekly window, starting 7 days before the
+weekly reset; without usage data, the last 7 days. The account's utilization
+is shown alongside for comparison. Transcripts only cover Claude Code on this
+machine, not claude.ai or other devices.
+
+Examples:
+  claude-limits sessions
+  claude-limits sessions --by session --limit 5
+  claude-limits sessions --since 5h
+  claude-limits sessions --format json`,
+	RunE: runSessions,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	sessionsCmd.Flags().DurationVar(&sessionsSince, "since", 7*24*time.Hour, "Count messages from this long ago (default: the current weekly window)")
+	sessionsCmd.Flags().StringVar(&sessionsBy, "by", "project", "Group by project or session")
+	sessionsCmd.Flags().StringVar(&sessionsDir, "dir", "", "Transcripts directory (default: ~/.claude/projects)")
+	sessionsCmd.Flags().IntVar(&sessionsLimit, "limit", 0, "Show only the top N rows (0 for all)")
+}
+
+// sessionsReport is the JSON output of the sessions command
+type sessionsReport struct {
+	Since    time.Time             `json:"since"`
+	Total    transcripts.Tokens    `json:"total"`
+	Projects []transcripts.Project `json:"projects,omitempty"`
+	Sessions []transcripts.Session `json:"sessions,omitempty"`
+	Windows  []sessionsWindow      `json:"windows,omitempty"`
+}
+
+// sessionsWindow is an account usage window shown beside the token totals
+type sessionsWindow struct {
+	Window      string     `json:"window"`
+	Utilization float64    `json:"utilization"`
+	ResetsAt    *time.Time `json:"resets_at"`
+}
+
+func runSessions(cmd *cobra.Command, args []string) error {
+	if sessionsBy != "project" && sessionsBy != "session" {
+		return fmt.Errorf("invalid --by %q (use project or session)", sessionsBy)
+	}
+
+	// Account usage is context, so the report works offline
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		slog.Debug("failed to get usage", "error", err)
+	}
+
+	since := time.Now().Add(-sessionsSince)
+	if !flagChanged("since") && usage != nil && usage.SevenDay != nil && usage.SevenDay.ResetsAt != nil {
+		if start := usage.SevenDay.ResetsAt.Add(-7 * 24 * time.Hour); start.Before(time.Now()) {
+			since = start
+		}
+	}
+
+	dir := sessionsDir
+	if dir == "" {
+		dir = transcripts.DefaultDir()
+	}
+	sessions, err := transcripts.Read(dir, since)
+	if err != nil {
+		return err
+	}
+
+	report := sessionsReport{Since: since, Total: transcripts.Total(sessions)}
+	if sessionsBy == "session" {
+		report.Sessions = limitRows(sessions, sessionsLimit)
+	} else {
+		report.Projects = limitRows(transcripts.Projects(sessions), sessionsLimit)
+	}
+	if usage != nil {
+		for _, w := range usage.Windows() {
+			report.Windows = append(report.Windows, sessionsWindow{
+				Window:      w.Name,
+				Utilization: w.Utilization,
+				ResetsAt:    w.ResetsAt,
+			})
+		}
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	printSessionsReport(report)
+	return nil
+}
+
+// limitRows returns the first n rows, or all of them if n is 0
+func limitRows[T any](rows []T, n int) []T {
+	if n > 0 && n < len(rows) {
+		return rows[:n]
+	}
+	return rows
+}
+
+func printSessionsReport(report sessionsReport) {
+	colors := outputColors()
+	datetime := GetFormats().Datetime
+
+	fmt.Fprintf(stdout, "%sToken usage since %s%s\n\n", colors.Bold, report.Since.Local().Format(datetime), colors.Reset)
+	if report.Total.Total() == 0 {
+		fmt.Fprintln(stdout, "No Claude Code activity in transcripts")
+	} else {
+		const row = "%-40s %8s %8s %8s %8s %8s\n"
+		if report.Sessions != nil {
+			fmt.Fprintf(stdout, row, "SESSION", "INPUT", "OUTPUT", "CACHE+", "CACHED", "TOTAL")
+			for _, s := range report.Sessions {
+				name := shortID(s.ID) + "  " + filepath.Base(s.Project)
+				printTokenRow(row, name, s.Tokens)
+			}
+		} else {
+			fmt.Fprintf(stdout, row, "PROJECT", "INPUT", "OUTPUT", "CACHE+", "CACHED", "TOTAL")
+			for _, p := range report.Projects {
+				printTokenRow(row, truncateLeft(p.Path, 40), p.Tokens)
+			}
+		}
+		printTokenRow(row, "Total", report.Total)
+	}
+
+	if len(report.Windows) > 0 {
+		var parts []string
+		for _, w := range report.Windows {
+			parts = append(parts, fmt.Sprintf("%s %s%.0f%%%s", format.WindowLabel(w.Window), colors.UtilizationColor(w.Window, w.Utilization), w.Utilization, colors.Reset))
+		}
+		fmt.Fprintf(stdout, "\nAccount utilization: %s\n", strings.Join(parts, " · "))
+	}
+}
+
+func printTokenRow(layout, name string, t transcripts.Tokens) {
+	fmt.Fprintf(stdout, layout, name, tokenCount(t.Input), tokenCount(t.Output), tokenCount(t.CacheCreation), tokenCount(t.CacheRead), tokenCount(t.Total()))
+}
+
+// tokenCount abbreviates a token count, e.g. 950, 12.3k, 4.1M
+func tokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return strconv.FormatFloat(float64(n)/1e9, 'f', 1, 64) + "B"
+	case n >= 1_000_000:
+		return strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64) + "M"
+	case n >= 1_000:
+		return strconv.FormatFloat(float64(n)/1e3, 'f', 1, 64) + "k"
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+// shortID returns the first segment of a session UUID
+func shortID(id string) string {
+	if i := strings.IndexByte(id, '-'); i > 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// truncateLeft shortens s to width runes, keeping the end of a path
+func truncateLeft(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return "…" + string(r[len(r)-width+1:])
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var shareStyle string
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Print an anonymous usage snapshot to post in chats or issues",
+	Long: `Print the current usage as a snapshot safe to share when discussing limit
+behavior in team chats or GitHub issues: each window's utilization, a bar,
+and time to reset. Nothing identifying the account is included (no
+organization IDs, emails, or tokens), and reset times are relative so they
+don't give away your time zone.
+
+Styles:
+  markdown  A table for GitHub, Slack, and Discord, with a colored marker
+            for each window's threshold level (default)
+  text      A box-drawn table that reads well in a code block or screenshot
+
+Examples:
+  claude-limits share
+  claude-limits share --style text
+  claude-limits share | pbcopy`,
+	RunE: runShare,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	shareCmd.Flags().StringVar(&shareStyle, "style", "markdown", "Snapshot style: "+strings.Join(format.ShareStyles, " or "))
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := format.Share(usage, shareStyle, shareThresholds())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, snapshot)
+	return nil
+}
+
+// shareThresholds returns uncolored Colors carrying the configured
+// thresholds, which set the markers in shared snapshots
+func shareThresholds() format.Colors {
+	var thresholds format.Colors
+	if cfg != nil {
+		thresholds.Thresholds = cfg.ResolvedThresholds()
+	}
+	return thresholds
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var statsPeriod string
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize recorded usage over a period",
+	Long: `Summarize usage history per window: average and peak utilization, how many
+times the limit was hit, the hour it was usually hit, and the weekdays with
+the most usage. Useful for deciding whether a plan fits how you work.
+
+History is recorded whenever fresh usage is fetched; run the daemon for
+regular samples. Hours and weekdays are in local time.
+
+Examples:
+  claude-limits stats
+  claude-limits stats --period 7d
+  claude-limits stats --period 12w --format json`,
+	RunE: runStats,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsPeriod, "period", "30d", "How far back to summarize (e.g. 7d, 2w, 12h)")
+}
+
+// busiestWeekdays is how many weekdays stats lists per window
+const busiestWeekdays = 3
+
+// statsWindow is the JSON shape of one window in 'stats'
+type statsWindow struct {
+	history.WindowStats
+	TypicalHitHour  *int     `json:"typical_hit_hour"`
+	BusiestWeekdays []string `json:"busiest_weekdays"`
+}
+
+// statsReport is the JSON shape of 'stats'
+type statsReport struct {
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Snapshots int           `json:"snapshots"`
+	Windows   []statsWindow `json:"windows"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	period, err := format.ParseDuration(statsPeriod)
+	if err != nil {
+		return fmt.Errorf("invalid --period: %w", err)
+	}
+	if period <= 0 {
+		return fmt.Errorf("--period must be positive, got %s", statsPeriod)
+	}
+
+	profile, _, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	store, err := history.Open(history.PathForProfile(profile))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	to := time.Now()
+	from := to.Add(-period)
+	snapshots, err := store.Query(from, to)
+	if err != nil {
+		return err
+	}
+
+	report := statsReport{From: from, To: to, Snapshots: len(snapshots), Windows: []statsWindow{}}
+	for _, s := range history.Stats(snapshots, time.Local) {
+		w := statsWindow{WindowStats: s, BusiestWeekdays: []string{}}
+		if hour, ok := s.TypicalHitHour(); ok {
+			w.TypicalHitHour = &hour
+		}
+		for _, d := range s.BusiestWeekdays(busiestWeekdays) {
+			w.BusiestWeekdays = append(w.BusiestWeekdays, d.String())
+		}
+		report.Windows = append(report.Windows, w)
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Fprintf(stdout, "No usage history in the last %s\n", statsPeriod)
+		return nil
+	}
+	return printStatsTable(report)
+}
+
+func printStatsTable(report statsReport) error {
+	colors := outputColors()
+	fmt.Fprintf(stdout, "%sUsage over the last %s%s (%d snapshots since %s)\n\n",
+		colors.Bold, statsPeriod, colors.Reset, report.Snapshots, report.From.Local().Format("Jan 2"))
+
+	const row = "%-22s %7s %7s %6s  %-13s  %s\n"
+	fmt.Fprintf(stdout, row, "WINDOW", "AVERAGE", "PEAK", "HITS", "USUALLY HIT", "BUSIEST DAYS")
+	for _, w := range report.Windows {
+		hitHour := "-"
+		if w.TypicalHitHour != nil {
+			hitHour = fmt.Sprintf("%02d:00-%02d:00", *w.TypicalHitHour, (*w.TypicalHitHour+1)%24)
+		}
+		days := make([]string, len(w.BusiestWeekdays))
+		for i, d := range w.BusiestWeekdays {
+			days[i] = d[:3]
+		}
+		busiest := strings.Join(days, ", ")
+		if busiest == "" {
+			busiest = "-"
+		}
+		fmt.Fprintf(stdout, row, w.Window,
+			fmt.Sprintf("%.0f%%", w.Average), fmt.Sprintf("%.0f%%", w.Peak),
+			fmt.Sprint(w.LimitHits), hitHour, busiest)
+	}
+	return nil
+}
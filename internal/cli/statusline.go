@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/statusline"
+
+	"github.com/spf13/cobra"
+)
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Print a one-line usage summary for Claude Code's status line",
+	Long: `Print a compact, colorized one-line usage summary for Claude Code's status line.
+
+Reads Claude Code's status line JSON from stdin to show context window usage,
+and uses cached usage data (see --cache) for the 5-hour and weekly limits:
+
+  5h: 45% @ 2:30 PM | wk: 23% @ Tue 8:00 AM | ctx: 67%
+
+Unlike the shell scripts, this needs no jq, curl, or date utilities and works
+the same on every platform. Configure it in ~/.claude/settings.json:
+
+  "statusLine": {"type": "command", "command": "claude-limits statusline"}
+
+Times use the configured format preset. Unavailable values render as "?".`,
+	RunE: runStatusline,
+	Args: cobra.NoArgs,
+}
+
+func runStatusline(cmd *cobra.Command, args []string) error {
+	var in statusline.Input
+	if !isStdinTerminal() {
+		in = statusline.ReadInput(os.Stdin)
+	}
+
+	// A status line should always render something, so fetch errors only
+	// blank out the usage values
+	usage, err := getUsageWithCache(cmd.Context())
+	if err != nil {
+		slog.Debug("failed to get usage", "error", err)
+	}
+
+	// Claude Code renders ANSI colors even though stdout is a pipe, so
+	// only --no-color or NO_COLOR turn them off
+	colors := applyColorConfig(format.NewANSIColors())
+	if NoColor() || format.NoColorEnv() {
+		colors = format.Colors{}
+	}
+
+	fmt.Fprintln(stdout, statusline.Render(usage, in, colors, tableFormats()))
+	return nil
+}
+
+// isStdinTerminal reports whether stdin is interactive (i.e., nothing piped)
+func isStdinTerminal() bool {
+	return isTerminalFile(os.Stdin)
+}
+
+// isTerminalFile reports whether f is a terminal
+func isTerminalFile(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
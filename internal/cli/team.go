@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"github.com/spf13/cobra"
+)
+
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Compare usage across the team's profiles",
+	Long: `Fetch the usage of every profile listed under team.profiles in the config
+file concurrently and show them side by side: a row per profile and a column
+per window, each with its utilization and time to reset. Useful for keeping
+an eye on shared seats such as build accounts.
+
+team.windows picks the columns; by default every window any profile has is
+shown. Profiles that can't be fetched show the error in their row, and the
+command exits nonzero.
+
+Example config:
+  profiles:
+    build-1:
+      credentials: ~/.claude-build-1/.credentials.json
+    build-2:
+      credentials: ~/.claude-build-2/.credentials.json
+  team:
+    profiles: [build-1, build-2]
+    windows: [5h, wk]
+
+Examples:
+  claude-limits team
+  claude-limits team --format json`,
+	RunE: runTeam,
+	Args: cobra.NoArgs,
+}
+
+// teamJSON is one profile's entry in 'team --format json'
+type teamJSON struct {
+	Profile      string          `json:"profile"`
+	Organization string          `json:"organization,omitempty"`
+	Usage        json.RawMessage `json:"usage,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+func runTeam(cmd *cobra.Command, args []string) error {
+	if len(cfg.Team.Profiles) == 0 {
+		return fmt.Errorf("no team configured: list profiles under team.profiles in the config file (see 'claude-limits config init')")
+	}
+	outputFormat := GetOutputFormat()
+	if outputFormat != "table" && outputFormat != "json" {
+		return fmt.Errorf("team supports table and json output")
+	}
+
+	results := fetchProfiles(cmd.Context(), cfg.Team.Profiles)
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Label(), r.Err))
+		}
+	}
+
+	if outputFormat == "json" {
+		entries := make([]teamJSON, 0, len(results))
+		for _, r := range results {
+			entry := teamJSON{Profile: r.Profile, Organization: r.Organization}
+			if r.Err != nil {
+				entry.Error = r.Err.Error()
+			} else {
+				entry.Usage = r.Usage.Raw
+			}
+			entries = append(entries, entry)
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		rows := make([]format.ComparisonRow, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, format.ComparisonRow{Label: r.Label(), Usage: r.Usage, Err: r.Err})
+		}
+		windows := make([]string, 0, len(cfg.Team.Windows))
+		for _, w := range cfg.Team.Windows {
+			windows = append(windows, format.WindowName(w))
+		}
+		if err := format.WriteComparison(stdout, rows, windows, outputColors()); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+	"github.com/benjaminabbitt/claude-limits/internal/config"
+	"github.com/benjaminabbitt/claude-limits/internal/scripts"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	uninstallClearCache   bool
+	uninstallRemoveConfig bool
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the status line integration",
+	Long: `Reverse 'install statusline' and 'install-script'.
+
+Removes the statusLine entry from Claude Code settings, but only if it runs
+claude-limits (the built-in statusline command or one of the embedded
+scripts). If it points at an installed script, the script file is deleted.
+Use --force to remove a statusLine that doesn't belong to claude-limits.
+
+Optionally also clears the cache directory (cached usage) and state directory
+(history and alert state) and removes the config file.
+
+Examples:
+  claude-limits uninstall
+  claude-limits uninstall --project
+  claude-limits uninstall --clear-cache --remove-config
+  claude-limits uninstall --dry-run`,
+	RunE: runUninstall,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	uninstallCmd.Flags().BoolVar(&projectSettings, "project", false, "Remove statusLine from project settings (.claude/settings.json)")
+	uninstallCmd.Flags().BoolVar(&forceOverwrite, "force", false, "Remove statusLine even if it doesn't run claude-limits")
+	uninstallCmd.Flags().BoolVar(&uninstallClearCache, "clear-cache", false, "Also delete the cache and state directories")
+	uninstallCmd.Flags().BoolVar(&uninstallRemoveConfig, "remove-config", false, "Also delete the config file")
+	uninstallCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without changing anything")
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	if err := uninstallStatusLine(); err != nil {
+		return err
+	}
+
+	if uninstallClearCache {
+		if err := removePath("cache directory", cache.DefaultDir(), os.RemoveAll); err != nil {
+			return err
+		}
+		if err := removePath("state directory", cache.StateDir(), os.RemoveAll); err != nil {
+			return err
+		}
+	}
+
+	if uninstallRemoveConfig {
+		if err := removePath("config file", config.ResolvePath(configPath), os.Remove); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func uninstallStatusLine() error {
+	settingsPath, settingsType := settingsTarget()
+	settings, err := claudecode.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load Claude Code settings: %w", err)
+	}
+
+	if !settings.HasStatusLine() {
+		fmt.Fprintf(stdout, "No statusLine configured in %s settings (%s)\n", settingsType, settingsPath)
+		return nil
+	}
+
+	command := settings.StatusLineCommand()
+	scriptPath, ours := ownedStatusLine(command)
+	if !ours && !forceOverwrite {
+		return fmt.Errorf("statusLine in %s settings runs %q, which isn't claude-limits\nUse --force to remove it anyway", settingsType, command)
+	}
+
+	if scriptPath != "" {
+		if err := removePath("script", scriptPath, os.Remove); err != nil {
+			return err
+		}
+	}
+
+	settings.RemoveStatusLine()
+	if dryRun {
+		fmt.Fprintf(stdout, "Would remove statusLine from %s settings (%s)\n", settingsType, settingsPath)
+		return nil
+	}
+	if err := claudecode.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("failed to save Claude Code settings: %w", err)
+	}
+	fmt.Fprintf(stdout, "Removed statusLine from %s settings (%s)\n", settingsType, settingsPath)
+	return nil
+}
+
+// removePath deletes path (honoring --dry-run), treating a missing path as done
+func removePath(what, path string, remove func(string) error) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(stdout, "Would remove %s %s\n", what, path)
+		return nil
+	}
+	if err := remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", what, err)
+	}
+	fmt.Fprintf(stdout, "Removed %s %s\n", what, path)
+	return nil
+}
+
+// ownedStatusLine reports whether a statusLine command belongs to claude-limits.
+// If it runs an installed script, the script's path is returned for deletion.
+func ownedStatusLine(command string) (string, bool) {
+	exe, rest := splitCommand(command)
+	if exe == "" {
+		return "", false
+	}
+
+	// Built-in: "<path>/claude-limits[.exe] statusline"
+	if rest == "statusline" && strings.HasPrefix(filepath.Base(exe), "claude-limits") {
+		return "", true
+	}
+	if rest != "" {
+		return "", false
+	}
+
+	// Installed script: recognized by content, or by its default file name
+	content, err := os.ReadFile(exe)
+	if err != nil {
+		return "", false
+	}
+	for _, name := range scripts.List() {
+		s := scripts.Get(name)
+		if s.Matches(content) || filepath.Base(exe) == s.Filename {
+			return exe, true
+		}
+	}
+	return "", false
+}
+
+// splitCommand splits a command into its (possibly quoted) executable and the remainder
+func splitCommand(command string) (string, string) {
+	command = strings.TrimSpace(command)
+	if strings.HasPrefix(command, "'") {
+		return splitSingleQuoted(command)
+	}
+	if strings.HasPrefix(command, `"`) {
+		if end := strings.Index(command[1:], `"`); end >= 0 {
+			return command[1 : end+1], strings.TrimSpace(command[end+2:])
+		}
+	}
+	exe, rest, _ := strings.Cut(command, " ")
+	return exe, strings.TrimSpace(rest)
+}
+
+// splitSingleQuoted splits a command whose executable is single-quoted, as
+// shellPath writes it, undoing the escapes of embedded single quotes.
+// Unterminated quotes yield no executable.
+func splitSingleQuoted(command string) (string, string) {
+	var exe strings.Builder
+	i := 0
+	for i < len(command) {
+		switch {
+		case command[i] == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return "", ""
+			}
+			exe.WriteString(command[i+1 : i+1+end])
+			i += end + 2
+		case strings.HasPrefix(command[i:], `\'`):
+			exe.WriteByte('\'')
+			i += 2
+		default:
+			return exe.String(), strings.TrimSpace(command[i:])
+		}
+	}
+	return exe.String(), ""
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/update"
+	"github.com/benjaminabbitt/claude-limits/internal/version"
+)
+
+// updateWait bounds how long the command waits at exit for a release check
+// still in flight; an unfinished check is retried on the next run
+const updateWait = 500 * time.Millisecond
+
+// updateNotice receives the new-release notice from the background check,
+// and is closed when the check is done. Nil if no check was started.
+var updateNotice chan string
+
+// startUpdateCheck looks for a newer release in the background, at most once
+// a day, for release builds run in a terminal unless update_check is off
+func startUpdateCheck() {
+	if quiet || version.Version == "dev" || (cfg != nil && !cfg.UpdateCheckEnabled()) || !isTerminalFile(os.Stderr) {
+		return
+	}
+
+	notice := make(chan string, 1)
+	updateNotice = notice
+	go func() {
+		defer close(notice)
+		// Not the command's context, which is canceled as it exits
+		latest, err := update.New(update.StatePath()).Latest(context.Background())
+		if err == nil && update.Newer(latest, version.Version) {
+			notice <- fmt.Sprintf("claude-limits %s is available (you have %s): %s", latest, version.Version, update.ReleasesPage)
+		}
+	}()
+}
+
+// PrintUpdateNotice prints a one-line notice to stderr if the background
+// check found a newer release
+func PrintUpdateNotice() {
+	if updateNotice == nil {
+		return
+	}
+	select {
+	case msg, ok := <-updateNotice:
+		if ok {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+	case <-time.After(updateWait):
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// ANSI sequence to move the cursor home and clear the screen
+const clearScreen = "\033[H\033[2J"
+
+var (
+	watchInterval int
+	watchNotify   bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously display usage, refreshing on an interval",
+	Long: `Poll usage on an interval and redraw the table in place.
+
+Numeric fields that changed since the previous refresh are annotated with
+a delta indicator (▲ for increases, ▼ for decreases).
+
+When stdout is not a terminal, or is a Windows console without ANSI
+support, each refresh is appended instead of redrawn.
+Press Ctrl+C to stop.
+
+With --notify, desktop notifications are sent when a window crosses the
+warning or critical threshold (see 'claude-limits notify').
+
+Examples:
+  claude-limits watch
+  claude-limits watch --interval 30
+  claude-limits watch --notify --warning 70`,
+	RunE: runWatch,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	watchCmd.Flags().IntVarP(&watchInterval, "interval", "i", 60, "Refresh interval in seconds")
+	watchCmd.Flags().BoolVar(&watchNotify, "notify", false, "Send desktop notifications on threshold crossings")
+	addThresholdFlags(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", watchInterval)
+	}
+
+	ctx := cmd.Context()
+
+	ticker := time.NewTicker(time.Duration(watchInterval) * time.Second)
+	defer ticker.Stop()
+
+	var prev *models.Usage
+	for {
+		prev = refreshWatch(cmd, prev)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshWatch fetches and renders one frame, returning the snapshot to diff
+// against next time. Fetch errors are shown but don't stop the loop.
+func refreshWatch(cmd *cobra.Command, prev *models.Usage) *models.Usage {
+	ctx := cmd.Context()
+	usage, err := getUsageWithCache(ctx)
+	if ctx.Err() != nil {
+		return prev
+	}
+
+	// Clear only once the fetch completes so the previous frame stays visible meanwhile
+	if format.IsANSITerminal() {
+		fmt.Fprint(stdout, clearScreen)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return prev
+	}
+
+	if watchNotify {
+		if err := checkAlerts(cmd, usage); err != nil {
+			fmt.Fprintf(os.Stderr, "Notification error: %v\n", err)
+		}
+	}
+
+	colors := outputColors()
+	if err := format.WriteTable(stdout, usage, colors, tableFormats(), format.TableOptions{Prev: prev, Width: tableWidth()}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	fmt.Fprintf(stdout, "Updated %s · refreshing every %ds\n", time.Now().Format(GetFormats().Time), watchInterval)
+	return usage
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show which account the usage numbers belong to",
+	Long: `Fetch the profile of the account the credentials belong to and show its
+email, name, and organization alongside the subscription type and rate-limit
+tier from the credentials file.
+
+Examples:
+  claude-limits whoami
+  claude-limits whoami --profile work --format json`,
+	RunE: runWhoami,
+	Args: cobra.NoArgs,
+}
+
+// whoami is the JSON shape of 'whoami'
+type whoami struct {
+	Profile         string `json:"profile"`
+	CredentialsPath string `json:"credentials_path,omitempty"`
+	// CredentialCommand is set instead of CredentialsPath when the token
+	// comes from a command
+	CredentialCommand string `json:"credential_command,omitempty"`
+	Email             string `json:"email"`
+	Name              string `json:"name,omitempty"`
+	AccountUUID       string `json:"account_uuid,omitempty"`
+	Organization      string `json:"organization,omitempty"`
+	OrgUUID           string `json:"organization_uuid,omitempty"`
+	Subscription      string `json:"subscription,omitempty"`
+	RateLimitTier     string `json:"rate_limit_tier,omitempty"`
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	name, profile, err := GetProfile()
+	if err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials(cmd.Context(), profile)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient(creds.AccessToken)
+	if err != nil {
+		return err
+	}
+	account, err := client.GetAccountProfile(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get account profile: %w", err)
+	}
+
+	me := whoami{
+		Profile:           displayProfile(name),
+		CredentialCommand: profile.CredentialCommand,
+		Email:             account.Account.Email,
+		Name:              account.Name(),
+		AccountUUID:       account.Account.UUID,
+		Organization:      account.Organization.Name,
+		OrgUUID:           account.Organization.UUID,
+		Subscription:      creds.SubscriptionType,
+		RateLimitTier:     creds.RateLimitTier,
+	}
+	if me.CredentialCommand == "" {
+		me.CredentialsPath = credentialsPath(profile)
+	}
+	if me.RateLimitTier == "" {
+		me.RateLimitTier = account.Organization.RateLimitTier
+	}
+
+	if GetOutputFormat() == "json" {
+		data, err := json.MarshalIndent(me, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "Email:         %s\n", valueOrUnknown(me.Email))
+	if me.Name != "" {
+		fmt.Fprintf(stdout, "Name:          %s\n", me.Name)
+	}
+	if me.Organization != "" {
+		fmt.Fprintf(stdout, "Organization:  %s\n", me.Organization)
+	}
+	fmt.Fprintf(stdout, "Subscription:  %s\n", valueOrUnknown(me.Subscription))
+	if me.RateLimitTier != "" {
+		fmt.Fprintf(stdout, "Rate limit:    %s\n", me.RateLimitTier)
+	}
+	fmt.Fprintf(stdout, "Profile:       %s\n", me.Profile)
+	fmt.Fprintf(stdout, "Credentials:   %s\n", credentialsSource(profile))
+	return nil
+}
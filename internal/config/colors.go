@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+)
+
+// ResolvedThresholds returns the configured utilization thresholds keyed by
+// window name, with "" as the default for every window. Returns nil if none
+// are configured.
+func (c *Config) ResolvedThresholds() map[string]format.Thresholds {
+	t := c.Thresholds
+	if t.Warning == 0 && t.Critical == 0 && len(t.Windows) == 0 {
+		return nil
+	}
+
+	def := format.DefaultThresholds
+	if t.Warning > 0 {
+		def.Warn = t.Warning
+	}
+	if t.Critical > 0 {
+		def.Critical = t.Critical
+	}
+
+	resolved := make(map[string]format.Thresholds, len(t.Windows)+1)
+	if t.Warning > 0 || t.Critical > 0 {
+		resolved[""] = def
+	}
+	for name, w := range t.Windows {
+		wt := def
+		if w.Warning > 0 {
+			wt.Warn = w.Warning
+		}
+		if w.Critical > 0 {
+			wt.Critical = w.Critical
+		}
+		resolved[format.WindowName(name)] = wt
+	}
+	return resolved
+}
+
+// ApplyColors applies the configured theme, color overrides, and thresholds
+// to base. Disabled colors (an empty Reset) stay disabled. On error, base is
+// returned with only the thresholds applied.
+func (c *Config) ApplyColors(base format.Colors) (format.Colors, error) {
+	if base.Reset == "" {
+		return base, nil
+	}
+	base.Thresholds = c.ResolvedThresholds()
+
+	colors := base
+	if theme := c.Colors.Theme; theme != "" {
+		t, ok := format.Themes[theme]
+		if !ok {
+			return base, fmt.Errorf("unknown color theme %q (use %s)", theme, strings.Join(format.ThemeNames(), ", "))
+		}
+		colors = t
+	}
+
+	overrides := []struct {
+		spec   string
+		target *string
+	}{
+		{c.Colors.OK, &colors.Green},
+		{c.Colors.Warning, &colors.Yellow},
+		{c.Colors.Critical, &colors.Red},
+		{c.Colors.Accent, &colors.Cyan},
+	}
+	for _, o := range overrides {
+		if o.spec == "" {
+			continue
+		}
+		code, err := format.ParseColorLevel(o.spec, base.Level)
+		if err != nil {
+			return base, err
+		}
+		*o.target = code
+	}
+
+	colors.Level = base.Level
+	colors.Thresholds = base.Thresholds
+	return colors, nil
+}
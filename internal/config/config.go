@@ -2,9 +2,14 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -58,14 +63,279 @@ type Formats struct {
 	Datetime string `yaml:"datetime"`
 	Date     string `yaml:"date"`
 	Time     string `yaml:"time"`
+	// Relative renders reset times as countdowns, e.g. "in 2h 14m"
+	Relative bool `yaml:"relative"`
+	// RawDurations shows *_seconds, *_minutes, and *remaining fields as
+	// plain numbers rather than durations such as "2h 14m"
+	RawDurations bool `yaml:"raw_durations"`
+}
+
+// Profile holds per-account settings for a named profile
+type Profile struct {
+	// Credentials is the path to a Claude Code credentials file.
+	// Empty uses the default (~/.claude/.credentials.json).
+	Credentials string `yaml:"credentials"`
+	// CredentialCommand is a shell command printing the access token or
+	// credentials JSON, e.g. from a password manager. It takes precedence
+	// over Credentials.
+	CredentialCommand string `yaml:"credential_command"`
+	// Organization names the organization the credentials are signed in
+	// to, labeling the profile in --all-orgs output
+	Organization string `yaml:"organization"`
+}
+
+// Webhook configures a webhook alert destination
+type Webhook struct {
+	URL string `yaml:"url"`
+	// Type is the payload shape: "generic" (default), "slack", or "discord"
+	Type string `yaml:"type"`
+	// Template is an optional Go text/template for the message text,
+	// evaluated against the alert ({{.ResetTime}} and {{.ResetsIn}} give
+	// the reset time as a time and a countdown)
+	Template string `yaml:"template"`
+}
+
+// Alerts contains threshold alerting configuration
+type Alerts struct {
+	Warning  float64 `yaml:"warning"`
+	Critical float64 `yaml:"critical"`
+	// Cooldown is the least time between alerts of the same level for a
+	// window, e.g. "1h"; zero alerts on every crossing
+	Cooldown time.Duration `yaml:"cooldown"`
+	// OnReset picks which resets notify: "constrained" (default, windows
+	// that reached warning), "always", or "never"
+	OnReset string `yaml:"on_reset"`
+	// RemindBefore notifies this long before a window resets, e.g. "30m";
+	// zero sends no reminders
+	RemindBefore time.Duration `yaml:"remind_before"`
+	// TokenExpiry notifies this long before the Claude Code access token
+	// expires, e.g. "2h"; zero sends no expiry alerts
+	TokenExpiry time.Duration `yaml:"token_expiry"`
+	// Windows overrides these settings per window, keyed by window name or
+	// short label (5h, wk, opus, sonnet)
+	Windows  map[string]AlertPolicy `yaml:"windows"`
+	Webhooks []Webhook              `yaml:"webhooks"`
+}
+
+// AlertPolicy overrides the alerts settings for one window. Unset fields
+// keep them.
+type AlertPolicy struct {
+	Warning      float64        `yaml:"warning"`
+	Critical     float64        `yaml:"critical"`
+	Cooldown     *time.Duration `yaml:"cooldown"`
+	OnReset      string         `yaml:"on_reset"`
+	RemindBefore *time.Duration `yaml:"remind_before"`
+}
+
+// Compact configures --format compact output
+type Compact struct {
+	// Windows lists the windows to show, in order (default: five_hour, seven_day)
+	Windows []string `yaml:"windows"`
+	// Separator goes between windows (default: " | ")
+	Separator string `yaml:"separator"`
+	// HideResets omits reset times
+	HideResets bool `yaml:"hide_resets"`
+}
+
+// Threshold is a pair of utilization percentages where output turns yellow and red
+type Threshold struct {
+	Warning  float64 `yaml:"warning"`
+	Critical float64 `yaml:"critical"`
+}
+
+// Thresholds configures utilization colors. Zero values use the defaults
+// (80 and 95); Windows overrides them for individual windows.
+type Thresholds struct {
+	Warning  float64              `yaml:"warning"`
+	Critical float64              `yaml:"critical"`
+	Windows  map[string]Threshold `yaml:"windows"`
+}
+
+// Colors configures the output palette: a named theme, then overrides for
+// individual roles. Values are color specs such as "red", "bright-red",
+// "bold yellow", "208", or "#ff8800".
+type Colors struct {
+	Theme    string `yaml:"theme"`
+	OK       string `yaml:"ok"`
+	Warning  string `yaml:"warning"`
+	Critical string `yaml:"critical"`
+	Accent   string `yaml:"accent"`
+}
+
+// API configures requests to the usage API. Zero values use the defaults.
+type API struct {
+	// Timeout limits each request attempt, e.g. "10s"
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxRetries is how many times a failed request is retried.
+	// Nil uses the default; 0 disables retries.
+	MaxRetries *int `yaml:"max_retries"`
+	// InitialBackoff is the wait before the first retry, doubling after each
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Proxy is an http, https, or socks5 proxy URL. Empty uses HTTPS_PROXY/NO_PROXY.
+	Proxy string `yaml:"proxy"`
+	// CACert is a PEM file of extra root certificates to trust
+	CACert string `yaml:"ca_cert"`
+	// InsecureSkipVerify disables TLS certificate verification
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+}
+
+// Daemon configures the daemon command
+type Daemon struct {
+	// Interval is the time between polls, like --interval
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Serve configures the serve command
+type Serve struct {
+	// PollInterval is the time between usage polls, like --poll-interval.
+	// Zero uses the flag; disable polling with --poll-interval 0.
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+// Team lists the profiles the team command compares
+type Team struct {
+	// Profiles are the profile names to compare, in display order
+	Profiles []string `yaml:"profiles"`
+	// Windows are the window columns to show, e.g. "5h" or "opus"; empty
+	// shows every window any profile has
+	Windows []string `yaml:"windows"`
+}
+
+// OutputFormats are the values of --format and output.format
+var OutputFormats = []string{"table", "json", "compact", "influx", "waybar", "starship", "image"}
+
+// Output sets defaults for the output flags. Flags override them.
+type Output struct {
+	// Format is the default --format
+	Format string `yaml:"format"`
+	// NoColor disables colored output like --no-color
+	NoColor *bool `yaml:"no_color"`
+	// Query is the field 'limits' shows when run without a query, e.g. "5h"
+	Query string `yaml:"query"`
+}
+
+// CacheSettings configures the on-disk usage cache. A bare boolean, as in
+// "cache: false", sets Enabled.
+type CacheSettings struct {
+	// Enabled turns the cache on or off; nil is enabled
+	Enabled *bool `yaml:"enabled"`
+	// TTL is the default --cache in seconds; 0 skips the cache unless
+	// --cache is given
+	TTL *int `yaml:"ttl"`
+}
+
+// UnmarshalYAML accepts either a boolean or a mapping
+func (c *CacheSettings) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var enabled bool
+		if err := node.Decode(&enabled); err != nil {
+			return err
+		}
+		c.Enabled = &enabled
+		return nil
+	}
+	type plain CacheSettings
+	return node.Decode((*plain)(c))
 }
 
 // Config represents the full configuration file
 type Config struct {
-	Formats Formats `yaml:"formats"`
+	Formats        Formats            `yaml:"formats"`
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+	Team           Team               `yaml:"team"`
+	Alerts         Alerts             `yaml:"alerts"`
+	Compact        Compact            `yaml:"compact"`
+	API            API                `yaml:"api"`
+	Thresholds     Thresholds         `yaml:"thresholds"`
+	Colors         Colors             `yaml:"colors"`
+	Output         Output             `yaml:"output"`
+	Daemon         Daemon             `yaml:"daemon"`
+	Serve          Serve              `yaml:"serve"`
+	// CredentialCommand is the default profile's credential_command, also
+	// used by profiles without their own
+	CredentialCommand string `yaml:"credential_command"`
+	// Locale translates dates, numbers, and table labels, e.g. "de" or
+	// "auto" for LANG. Empty is English.
+	Locale string `yaml:"locale"`
+	// UpdateCheck enables the daily check for new releases; nil is disabled
+	UpdateCheck *bool `yaml:"update_check"`
+	// Cache configures the on-disk usage cache
+	Cache CacheSettings `yaml:"cache"`
+}
+
+// UpdateCheckEnabled reports whether to check for new releases
+func (c *Config) UpdateCheckEnabled() bool {
+	return c.UpdateCheck != nil && *c.UpdateCheck
 }
 
-// ResolvedFormats returns the effective format strings, applying preset then overrides
+// CacheEnabled reports whether usage may be cached on disk
+func (c *Config) CacheEnabled() bool {
+	return c.Cache.Enabled == nil || *c.Cache.Enabled
+}
+
+// HasSecrets reports whether the config holds credentials that other users
+// mustn't read: webhook URLs, which are bearer secrets, or a proxy password
+func (c *Config) HasSecrets() bool {
+	if len(c.Alerts.Webhooks) > 0 {
+		return true
+	}
+	u, err := url.Parse(c.API.Proxy)
+	if err != nil || u.User == nil {
+		return false
+	}
+	_, ok := u.User.Password()
+	return ok
+}
+
+// profileNamePattern restricts profile names to characters safe for file names,
+// since profile names key the cache and history files
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ResolveProfile returns the effective profile name and its settings.
+// An empty name falls back to default_profile. If neither is set, returns
+// the unnamed default profile. Names not present in profiles are an error.
+// Profiles without a credential_command inherit the top-level one.
+func (c *Config) ResolveProfile(name string) (string, Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return "", Profile{CredentialCommand: c.CredentialCommand}, nil
+	}
+
+	if !profileNamePattern.MatchString(name) {
+		return "", Profile{}, fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return "", Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+
+	profile.Credentials = ExpandHome(profile.Credentials)
+	if profile.CredentialCommand == "" {
+		profile.CredentialCommand = c.CredentialCommand
+	}
+	return name, profile, nil
+}
+
+// ExpandHome replaces a leading "~/" with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// ResolvedFormats returns the effective format strings, applying preset then
+// overrides
 func (c *Config) ResolvedFormats() FormatPreset {
 	result := FormatPreset{
 		Datetime: DefaultDatetimeFormat,
@@ -73,11 +343,13 @@ func (c *Config) ResolvedFormats() FormatPreset {
 		Time:     DefaultTimeFormat,
 	}
 
-	// Apply preset if specified
+	// Apply preset if specified, else the locale's customary clock
 	if c.Formats.Preset != "" {
 		if preset, ok := Presets[c.Formats.Preset]; ok {
 			result = preset
 		}
+	} else if c.ResolvedLocale().Clock24 {
+		result = Presets[localePreset]
 	}
 
 	// Apply individual overrides
@@ -119,10 +391,9 @@ func DefaultPath() string {
 	return filepath.Join(configDir, "claude-limits", "config.yaml")
 }
 
-// Load reads and parses the configuration file from the given path.
-// If path is empty, it uses the default path.
-// Returns an empty config (not an error) if the file doesn't exist.
-func Load(path string) (*Config, error) {
+// ResolvePath returns the config file path that Load would read: path if
+// set, then CLAUDE_LIMITS_CONFIG, then DefaultPath
+func ResolvePath(path string) string {
 	if path == "" {
 		// Check environment variable first
 		path = os.Getenv("CLAUDE_LIMITS_CONFIG")
@@ -130,22 +401,33 @@ func Load(path string) (*Config, error) {
 	if path == "" {
 		path = DefaultPath()
 	}
+	return path
+}
+
+// Load reads and parses the configuration file from the given path, then
+// applies CLAUDE_LIMITS_* environment overrides (see ApplyEnv).
+// If path is empty, it uses the default path.
+// Returns an empty config (not an error) if the file doesn't exist.
+func Load(path string) (*Config, error) {
+	path = ResolvePath(path)
 
 	cfg := &Config{}
 
 	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// No config file is not an error
-			return cfg, nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, err
+	// No config file is not an error
+	if err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, err
+		}
 	}
 
+	// Bad variables are skipped like unknown keys; 'config validate'
+	// reports them
+	_ = cfg.ApplyEnv()
 	return cfg, nil
 }
 
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variable that overrides each config key.
+// The rest is the key's path in upper case, joined by underscores:
+// formats.preset is CLAUDE_LIMITS_FORMATS_PRESET.
+const EnvPrefix = "CLAUDE_LIMITS_"
+
+// EnvVar returns the environment variable overriding a dotted config key,
+// e.g. "cache.ttl"
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyEnv overrides settings from CLAUDE_LIMITS_* environment variables.
+// Strings are taken as is; other values, including whole sections, lists,
+// and maps, are parsed as YAML, e.g.
+// CLAUDE_LIMITS_COMPACT_WINDOWS='[five_hour, seven_day]'. A section's
+// variable is applied before those of its keys, and empty variables are
+// ignored. Variables that don't parse are skipped and returned as one error.
+func (c *Config) ApplyEnv() error {
+	return errors.Join(applyEnv(reflect.ValueOf(c).Elem(), strings.TrimSuffix(EnvPrefix, "_"))...)
+}
+
+// applyEnv sets each field of the struct v from its variable, name plus
+// the field's key, recursing into sections
+func applyEnv(v reflect.Value, name string) []error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if key == "-" || key == "" || !f.IsExported() {
+			continue
+		}
+		envName := name + "_" + strings.ToUpper(key)
+		field := v.Field(i)
+
+		if value := os.Getenv(envName); value != "" {
+			if err := setFromEnv(field, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", envName, err))
+			}
+		}
+		if field.Kind() == reflect.Struct {
+			errs = append(errs, applyEnv(field, envName)...)
+		}
+	}
+	return errs
+}
+
+// setFromEnv sets v from a variable's value. Lists and maps are replaced,
+// while a section only changes the keys given.
+func setFromEnv(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+
+	// Decode into a copy, so a bad value leaves the setting alone
+	decoded := reflect.New(v.Type())
+	if v.Kind() != reflect.Map && v.Kind() != reflect.Slice {
+		decoded.Elem().Set(v)
+	}
+	if err := yaml.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			msgs := make([]string, len(typeErr.Errors))
+			for i, msg := range typeErr.Errors {
+				msgs[i] = typeProblem(msg).Message
+			}
+			return errors.New(strings.Join(msgs, "; "))
+		}
+		return err
+	}
+	v.Set(decoded.Elem())
+	return nil
+}
+
+// EnvProblems reports CLAUDE_LIMITS_* variables whose values don't parse
+func EnvProblems() []Problem {
+	var problems []Problem
+	for _, err := range applyEnv(reflect.ValueOf(&Config{}).Elem(), strings.TrimSuffix(EnvPrefix, "_")) {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	return problems
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvVar(t *testing.T) {
+	if got := EnvVar("formats.preset"); got != "CLAUDE_LIMITS_FORMATS_PRESET" {
+		t.Errorf("EnvVar = %q", got)
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "formats:\n  preset: 12hour\n  time: \"3:04 PM\"\ncompact:\n  windows: [five_hour]\n  separator: \" | \"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	t.Setenv("CLAUDE_LIMITS_FORMATS_PRESET", "24hour")
+	t.Setenv("CLAUDE_LIMITS_CACHE_TTL", "120")
+	t.Setenv("CLAUDE_LIMITS_API_TIMEOUT", "10s")
+	t.Setenv("CLAUDE_LIMITS_COLORS_OK", "#00ff00")
+	t.Setenv("CLAUDE_LIMITS_COMPACT_WINDOWS", "[seven_day, seven_day_opus]")
+	t.Setenv("CLAUDE_LIMITS_PROFILES", "{work: {credentials: /tmp/work.json}}")
+	t.Setenv("CLAUDE_LIMITS_UPDATE_CHECK", "false")
+	t.Setenv("CLAUDE_LIMITS_LOCALE", "")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Formats.Preset != "24hour" || cfg.Formats.Time != "3:04 PM" {
+		t.Errorf("formats = %+v, want preset overridden and time kept", cfg.Formats)
+	}
+	if cfg.Cache.TTL == nil || *cfg.Cache.TTL != 120 || !cfg.CacheEnabled() {
+		t.Errorf("cache = %+v, want ttl 120 and enabled", cfg.Cache)
+	}
+	if cfg.API.Timeout != 10*time.Second {
+		t.Errorf("api.timeout = %v", cfg.API.Timeout)
+	}
+	if cfg.Colors.OK != "#00ff00" {
+		t.Errorf("colors.ok = %q; strings should not be parsed as YAML", cfg.Colors.OK)
+	}
+	if strings.Join(cfg.Compact.Windows, ",") != "seven_day,seven_day_opus" || cfg.Compact.Separator != " | " {
+		t.Errorf("compact = %+v, want windows replaced", cfg.Compact)
+	}
+	if cfg.Profiles["work"].Credentials != "/tmp/work.json" {
+		t.Errorf("profiles = %+v", cfg.Profiles)
+	}
+	if cfg.UpdateCheckEnabled() {
+		t.Error("CLAUDE_LIMITS_UPDATE_CHECK=false should disable the check")
+	}
+}
+
+func TestLoadEnvWithoutFile(t *testing.T) {
+	t.Setenv("CLAUDE_LIMITS_CACHE", "false")
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.CacheEnabled() {
+		t.Error("CLAUDE_LIMITS_CACHE=false should apply without a config file")
+	}
+}
+
+func TestEnvProblems(t *testing.T) {
+	t.Setenv("CLAUDE_LIMITS_CACHE_TTL", "soon")
+	t.Setenv("CLAUDE_LIMITS_FORMATS_PRESET", "eu")
+
+	problems := EnvProblems()
+	if len(problems) != 1 || !strings.HasPrefix(problems[0].Message, "CLAUDE_LIMITS_CACHE_TTL: cannot unmarshal") {
+		t.Fatalf("problems = %v", problems)
+	}
+
+	// A bad variable is skipped, not fatal
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Cache.TTL != nil || cfg.Formats.Preset != "eu" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
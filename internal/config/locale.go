@@ -0,0 +1,14 @@
+package config
+
+import "github.com/benjaminabbitt/claude-limits/internal/format"
+
+// localePreset is the format preset used by locales with a 24-hour clock
+// when no preset is configured
+const localePreset = "eu"
+
+// ResolvedLocale returns the configured locale. Unknown names fall back to
+// English; 'config validate' reports them.
+func (c *Config) ResolvedLocale() format.Locale {
+	l, _ := format.LookupLocale(c.Locale)
+	return l
+}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a commented config file listing every setting, written by
+// 'config init'
+//
+//go:embed template.yaml
+var Template []byte
+
+// Problem is a mistake found in a config file
+type Problem struct {
+	Line    int // 1-based; 0 if unknown
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Validate checks config file contents for unknown keys, values of the wrong
+// type, unknown presets, invalid time layouts, and inconsistent settings.
+// Load ignores such mistakes; Validate reports them. It returns an error
+// only if data isn't YAML at all.
+func Validate(data []byte) ([]Problem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+
+	var problems []Problem
+	checkKeys(root, reflect.TypeOf(Config{}), "", &problems)
+
+	var cfg Config
+	if err := root.Decode(&cfg); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+		for _, msg := range typeErr.Errors {
+			problems = append(problems, typeProblem(msg))
+		}
+	}
+
+	problems = append(problems, checkValues(root, &cfg)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+// typeLinePattern matches the "line N: " prefix of yaml type errors
+var typeLinePattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+func typeProblem(msg string) Problem {
+	if m := typeLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return Problem{Line: line, Message: m[2]}
+	}
+	return Problem{Message: msg}
+}
+
+// checkKeys reports mapping keys that don't correspond to a field of t
+func checkKeys(node *yaml.Node, t reflect.Type, path string, problems *[]Problem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				*problems = append(*problems, Problem{
+					Line:    key.Line,
+					Message: unknownKeyMessage(key.Value, path, fields),
+				})
+				continue
+			}
+			checkKeys(value, field.Type, joinKey(path, key.Value), problems)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkKeys(node.Content[i+1], t.Elem(), joinKey(path, node.Content[i].Value), problems)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			checkKeys(item, t.Elem(), path+"[]", problems)
+		}
+	}
+}
+
+// yamlFields maps the yaml keys of a struct type to their fields
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+func unknownKeyMessage(key, path string, fields map[string]reflect.StructField) string {
+	where := "at top level"
+	if path != "" {
+		where = "in " + path
+	}
+	msg := fmt.Sprintf("unknown key %q %s", key, where)
+	if suggestion := closestKey(key, fields); suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return msg
+}
+
+// closestKey returns the known key within two edits of key, if any
+func closestKey(key string, fields map[string]reflect.StructField) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lookup returns the value node at a path of mapping keys, or nil
+func lookup(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+			}
+		}
+		node = next
+	}
+	return node
+}
+
+// lineOf returns the line of the node at a path, or 0 if absent
+func lineOf(root *yaml.Node, keys ...string) int {
+	if n := lookup(root, keys...); n != nil {
+		return n.Line
+	}
+	return 0
+}
+
+// checkValues reports settings that parse but can't work
+func checkValues(root *yaml.Node, cfg *Config) []Problem {
+	var problems []Problem
+	add := func(line int, format string, args ...interface{}) {
+		problems = append(problems, Problem{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if p := cfg.Formats.Preset; p != "" {
+		if _, ok := Presets[p]; !ok {
+			add(lineOf(root, "formats", "preset"), "unknown format preset %q (use %s)", p, strings.Join(presetNames(), ", "))
+		}
+	}
+	for _, key := range []string{"datetime", "date", "time"} {
+		n := lookup(root, "formats", key)
+		if n == nil || n.Value == "" {
+			continue
+		}
+		if msg := checkLayout(n.Value); msg != "" {
+			add(n.Line, "formats.%s: %s", key, msg)
+		}
+	}
+
+	if cfg.DefaultProfile != "" {
+		if _, ok := cfg.Profiles[cfg.DefaultProfile]; !ok {
+			add(lineOf(root, "default_profile"), "default_profile %q is not defined in profiles", cfg.DefaultProfile)
+		}
+	}
+	for name := range cfg.Profiles {
+		if !profileNamePattern.MatchString(name) {
+			add(lineOf(root, "profiles", name), "invalid profile name %q: use letters, digits, '-' and '_'", name)
+		}
+	}
+	for _, name := range cfg.Team.Profiles {
+		if _, ok := cfg.Profiles[name]; !ok {
+			add(lineOf(root, "team", "profiles"), "team profile %q is not defined in profiles", name)
+		}
+	}
+
+	checkPercent := func(key string, v float64) {
+		if v < 0 || v > 100 {
+			add(lineOf(root, "alerts", key), "alerts.%s must be between 0 and 100", key)
+		}
+	}
+	checkPercent("warning", cfg.Alerts.Warning)
+	checkPercent("critical", cfg.Alerts.Critical)
+	if cfg.Alerts.Warning > 0 && cfg.Alerts.Critical > 0 && cfg.Alerts.Warning >= cfg.Alerts.Critical {
+		add(lineOf(root, "alerts", "warning"), "alerts.warning (%g) should be below alerts.critical (%g)", cfg.Alerts.Warning, cfg.Alerts.Critical)
+	}
+
+	checkAlertPolicy := func(path []string, warning, critical float64, cooldown, remindBefore time.Duration, onReset string) {
+		name := strings.Join(path, ".")
+		if warning < 0 || warning > 100 || critical < 0 || critical > 100 {
+			add(lineOf(root, path...), "%s: thresholds must be between 0 and 100", name)
+		} else if warning > 0 && critical > 0 && warning >= critical {
+			add(lineOf(root, path...), "%s: warning (%g) should be below critical (%g)", name, warning, critical)
+		}
+		if cooldown < 0 {
+			add(lineOf(root, append(path, "cooldown")...), "%s.cooldown must not be negative", name)
+		}
+		if remindBefore < 0 {
+			add(lineOf(root, append(path, "remind_before")...), "%s.remind_before must not be negative", name)
+		}
+		switch onReset {
+		case "", "constrained", "always", "never":
+		default:
+			add(lineOf(root, append(path, "on_reset")...), "%s.on_reset: unknown reset mode %q (use constrained, always, or never)", name, onReset)
+		}
+	}
+	checkAlertPolicy([]string{"alerts"}, 0, 0, cfg.Alerts.Cooldown, cfg.Alerts.RemindBefore, cfg.Alerts.OnReset)
+	for name, p := range cfg.Alerts.Windows {
+		var cooldown, remindBefore time.Duration
+		if p.Cooldown != nil {
+			cooldown = *p.Cooldown
+		}
+		if p.RemindBefore != nil {
+			remindBefore = *p.RemindBefore
+		}
+		checkAlertPolicy([]string{"alerts", "windows", name}, p.Warning, p.Critical, cooldown, remindBefore, p.OnReset)
+	}
+
+	webhooks := lookup(root, "alerts", "webhooks")
+	for i, w := range cfg.Alerts.Webhooks {
+		line := 0
+		if webhooks != nil && i < len(webhooks.Content) {
+			line = webhooks.Content[i].Line
+		}
+		if w.URL == "" {
+			add(line, "webhook %d has no url", i+1)
+		}
+		switch w.Type {
+		case "", "generic", "slack", "discord":
+		default:
+			add(line, "webhook %d has unknown type %q (use generic, slack, or discord)", i+1, w.Type)
+		}
+	}
+
+	checkThreshold := func(path []string, t Threshold) {
+		line := lineOf(root, path...)
+		name := strings.Join(path, ".")
+		if t.Warning < 0 || t.Warning > 100 || t.Critical < 0 || t.Critical > 100 {
+			add(line, "%s: thresholds must be between 0 and 100", name)
+		} else if t.Warning > 0 && t.Critical > 0 && t.Warning >= t.Critical {
+			add(line, "%s: warning (%g) should be below critical (%g)", name, t.Warning, t.Critical)
+		}
+	}
+	checkThreshold([]string{"thresholds"}, Threshold{Warning: cfg.Thresholds.Warning, Critical: cfg.Thresholds.Critical})
+	for name, t := range cfg.Thresholds.Windows {
+		checkThreshold([]string{"thresholds", "windows", name}, t)
+	}
+
+	if theme := cfg.Colors.Theme; theme != "" {
+		if _, ok := format.Themes[theme]; !ok {
+			add(lineOf(root, "colors", "theme"), "unknown color theme %q (use %s)", theme, strings.Join(format.ThemeNames(), ", "))
+		}
+	}
+	for _, key := range []string{"ok", "warning", "critical", "accent"} {
+		n := lookup(root, "colors", key)
+		if n == nil {
+			continue
+		}
+		if _, err := format.ParseColor(n.Value); err != nil {
+			add(n.Line, "colors.%s: %v", key, err)
+		}
+	}
+
+	if cfg.Locale != "" {
+		if _, ok := format.LookupLocale(cfg.Locale); !ok {
+			add(lineOf(root, "locale"), "unknown locale %q (use %s, or auto)", cfg.Locale, strings.Join(format.LocaleNames(), ", "))
+		}
+	}
+
+	if f := cfg.Output.Format; f != "" && !slices.Contains(OutputFormats, f) {
+		add(lineOf(root, "output", "format"), "unknown output format %q (use %s)", f, strings.Join(OutputFormats, ", "))
+	}
+	if cfg.Cache.TTL != nil && *cfg.Cache.TTL < 0 {
+		add(lineOf(root, "cache", "ttl"), "cache.ttl must not be negative")
+	}
+
+	if cfg.Daemon.Interval < 0 {
+		add(lineOf(root, "daemon", "interval"), "daemon.interval must not be negative")
+	}
+	if cfg.Serve.PollInterval < 0 {
+		add(lineOf(root, "serve", "poll_interval"), "serve.poll_interval must not be negative")
+	}
+
+	if cfg.API.MaxRetries != nil && *cfg.API.MaxRetries < 0 {
+		add(lineOf(root, "api", "max_retries"), "api.max_retries must not be negative")
+	}
+
+	return problems
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// strftimePattern matches layout mistakes from other date libraries
+var strftimePattern = regexp.MustCompile(`%[a-zA-Z]|YYYY|yyyy|HH|hh:mm|MM/DD|DD/MM`)
+
+// checkLayout describes what's wrong with a Go time layout, or returns ""
+func checkLayout(layout string) string {
+	if strftimePattern.MatchString(layout) {
+		return fmt.Sprintf("%q looks like strftime or moment syntax; Go layouts use the reference time, e.g. \"2006-01-02 15:04\"", layout)
+	}
+	// A layout without any elements formats every time as itself
+	probe := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if probe.Format(layout) == layout {
+		return fmt.Sprintf("%q contains no date or time fields; Go layouts use the reference time, e.g. \"15:04\"", layout)
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	problems, err := Validate(Template)
+	if err != nil {
+		t.Fatalf("Validate(Template) failed: %v", err)
+	}
+	for _, p := range problems {
+		t.Errorf("template problem: %s", p)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	content := `formats:
+  preset: "25hour"
+  time: "HH:mm"
+  date: "today"
+formts:
+  preset: eu
+default_profile: home
+profiles:
+  work:
+    credentials: ~/.claude/.credentials.json
+    credential: typo
+team:
+  profiles: [work, ci]
+alerts:
+  warning: 95
+  critical: 80
+  webhooks:
+    - url: https://example.com/hook
+      type: teams
+api:
+  timeout: soon
+`
+	problems, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := []string{
+		`line 2: unknown format preset "25hour"`,
+		`line 3: formats.time: "HH:mm" looks like strftime`,
+		`line 4: formats.date: "today" contains no date or time fields`,
+		`line 5: unknown key "formts" at top level (did you mean "formats"?)`,
+		`line 7: default_profile "home" is not defined`,
+		`line 11: unknown key "credential" in profiles.work (did you mean "credentials"?)`,
+		`line 13: team profile "ci" is not defined`,
+		`line 15: alerts.warning (95) should be below alerts.critical (80)`,
+		`line 18: webhook 1 has unknown type "teams"`,
+		`line 21: cannot unmarshal`,
+	}
+	if len(problems) != len(want) {
+		for _, p := range problems {
+			t.Log(p)
+		}
+		t.Fatalf("got %d problems, want %d", len(problems), len(want))
+	}
+	for i, w := range want {
+		if got := problems[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got, w)
+		}
+	}
+}
+
+func TestValidateInvalidYAML(t *testing.T) {
+	if _, err := Validate([]byte("formats: [unclosed")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	valid := []string{"15:04", "2006-01-02", "Mon 3:04 PM", "Mon, Jan 2 2006 at 15:04 MST"}
+	for _, layout := range valid {
+		if msg := checkLayout(layout); msg != "" {
+			t.Errorf("checkLayout(%q) = %q, want valid", layout, msg)
+		}
+	}
+	for _, layout := range []string{"%H:%M", "YYYY-MM-DD", "now"} {
+		if checkLayout(layout) == "" {
+			t.Errorf("checkLayout(%q) should report a problem", layout)
+		}
+	}
+}
+
+func TestValidateColors(t *testing.T) {
+	content := `thresholds:
+  warning: 90
+  critical: 80
+colors:
+  theme: neon
+  critical: blood-red
+locale: klingon
+`
+	problems, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := []string{
+		"line 2: thresholds: warning (90) should be below critical (80)",
+		`line 5: unknown color theme "neon"`,
+		`line 6: colors.critical: invalid color "blood-red"`,
+		`line 7: unknown locale "klingon"`,
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %v, want %d problems", problems, len(want))
+	}
+	for i, w := range want {
+		if got := problems[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got, w)
+		}
+	}
+}
+
+func TestValidateOutputAndCache(t *testing.T) {
+	content := `output:
+  format: yaml
+  colour: false
+cache:
+  ttl: -5
+`
+	problems, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := []string{
+		`line 2: unknown output format "yaml"`,
+		`line 3: unknown key "colour" in output`,
+		"line 5: cache.ttl must not be negative",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %v, want %d problems", problems, len(want))
+	}
+	for i, w := range want {
+		if got := problems[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got, w)
+		}
+	}
+
+	if problems, _ := Validate([]byte("cache: false\n")); len(problems) != 0 {
+		t.Errorf("cache: false should stay valid, got %v", problems)
+	}
+}
+
+func TestValidateAlertPolicies(t *testing.T) {
+	content := `alerts:
+  cooldown: 1h
+  on_reset: sometimes
+  windows:
+    opus:
+      warning: 90
+      critical: 80
+    5h:
+      cooldown: 30m
+      on_reset: always
+    wk:
+      remind_before: -1h
+`
+	problems, err := Validate([]byte(content))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := []string{
+		`line 3: alerts.on_reset: unknown reset mode "sometimes"`,
+		"line 6: alerts.windows.opus: warning (90) should be below critical (80)",
+		"line 12: alerts.windows.wk.remind_before must not be negative",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %v, want %d problems", problems, len(want))
+	}
+	for i, w := range want {
+		if got := problems[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("problem %d = %q, want prefix %q", i, got, w)
+		}
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if c := cfg.Alerts.Windows["5h"].Cooldown; c == nil || *c != 30*time.Minute {
+		t.Errorf("alerts.windows.5h.cooldown = %v, want 30m", c)
+	}
+}
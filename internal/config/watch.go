@@ -0,0 +1,69 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce lets an editor finish writing, or swapping in, a file
+// before it's read
+const watchDebounce = 250 * time.Millisecond
+
+// Watch reloads the config at path (see ResolvePath) whenever its contents
+// change, calling onChange with the result of Load, until ctx is done.
+// The file's directory is watched, so editors that replace the file, and a
+// file created after Watch starts, are picked up. Returns an error if
+// watching can't start, e.g. because the directory doesn't exist.
+func Watch(ctx context.Context, path string, onChange func(*Config, error)) error {
+	path = ResolvePath(path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	last, _ := os.ReadFile(path)
+	go func() {
+		defer watcher.Close()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// Any change in the directory may swap the file, as with
+				// Kubernetes ConfigMap symlinks; contents decide
+				debounce = time.After(watchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onChange(nil, err)
+			case <-debounce:
+				debounce = nil
+				data, err := os.ReadFile(path)
+				if err != nil && !os.IsNotExist(err) {
+					onChange(nil, err)
+					continue
+				}
+				if bytes.Equal(data, last) {
+					continue
+				}
+				last = data
+				onChange(Load(path))
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("locale: en\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan *Config, 10)
+	err := Watch(ctx, path, func(c *Config, err error) {
+		if err != nil {
+			t.Errorf("reload error: %v", err)
+			return
+		}
+		reloads <- c
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	next := func() *Config {
+		t.Helper()
+		select {
+		case c := <-reloads:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reload")
+			return nil
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("locale: de\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c := next(); c.Locale != "de" {
+		t.Errorf("locale = %q, want de", c.Locale)
+	}
+
+	// Editors often write a temporary file and rename it over the original
+	tmp := filepath.Join(dir, ".config.yaml.swp")
+	if err := os.WriteFile(tmp, []byte("locale: fr\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	if c := next(); c.Locale != "fr" {
+		t.Errorf("locale = %q, want fr", c.Locale)
+	}
+
+	// Unrelated files in the directory don't reload an unchanged config
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-reloads:
+		t.Errorf("unexpected reload: %+v", c)
+	case <-time.After(3 * watchDebounce):
+	}
+}
+
+func TestWatchMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "config.yaml")
+	if err := Watch(context.Background(), path, func(*Config, error) {}); err == nil {
+		t.Error("expected an error watching a missing directory")
+	}
+}
@@ -0,0 +1,164 @@
+// Package debugdump builds redacted diagnostic bundles to attach to bug
+// reports. Secrets are masked before anything is written, so a bundle is
+// safe to share publicly.
+package debugdump
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces masked values
+const Redacted = "REDACTED"
+
+// secretKeys are config keys whose values may hold credentials: webhook
+// URLs embed tokens, and proxy URLs may carry a password
+var secretKeys = map[string]bool{
+	"url":   true,
+	"proxy": true,
+}
+
+// MaxErrors is how many recent errors the error log keeps
+const MaxErrors = 20
+
+// File is a named entry in a bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// RedactConfig returns the YAML config with secret values masked. Comments
+// and layout are kept so the bundle matches the user's file.
+func RedactConfig(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		return data, nil
+	}
+	redactNode(&doc)
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+func redactNode(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if secretKeys[key.Value] && value.Kind == yaml.ScalarNode && value.Value != "" {
+				value.Value = Redacted
+				value.Tag = "!!str"
+				value.Style = 0
+				continue
+			}
+			redactNode(value)
+		}
+		return
+	}
+	for _, c := range n.Content {
+		redactNode(c)
+	}
+}
+
+// SanitizeJSON masks every string in a JSON document except timestamps,
+// keeping its shape, numbers, and reset times. Unknown API fields may hold
+// account details, so only values needed to reproduce rendering survive.
+func SanitizeJSON(raw []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	out, err := json.MarshalIndent(sanitize(v), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return out, nil
+}
+
+func sanitize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = sanitize(e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = sanitize(e)
+		}
+		return v
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return v
+		}
+		return Redacted
+	}
+	return v
+}
+
+// Write writes files to w as a gzipped tarball, each stamped with modTime
+func Write(w io.Writer, files []File, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.Name,
+			Mode:    0600,
+			Size:    int64(len(f.Data)),
+			ModTime: modTime.Truncate(time.Second),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// ErrorLogPath returns the default path of the recent errors log
+func ErrorLogPath() string {
+	return cache.StatePath("errors.log")
+}
+
+// RecordError appends a timestamped, redacted error to the log at path,
+// keeping the last MaxErrors entries. Failures are ignored, since the log
+// only aids bug reports.
+func RecordError(path string, err error, now time.Time) {
+	var lines []string
+	if data, readErr := os.ReadFile(path); readErr == nil {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	msg := strings.ReplaceAll(redact.String(err.Error()), "\n", " ")
+	lines = append(lines, now.UTC().Format(time.RFC3339)+" "+msg)
+	if len(lines) > MaxErrors {
+		lines = lines[len(lines)-MaxErrors:]
+	}
+
+	if cache.EnsureDir(filepath.Dir(path)) != nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), cache.FileMode)
+}
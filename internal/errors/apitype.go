@@ -0,0 +1,35 @@
+package errors
+
+import "net/http"
+
+// apiTypeSentinels maps the error types in API response bodies to sentinels
+var apiTypeSentinels = map[string]error{
+	"authentication_error": ErrAuthRequired,
+	"permission_error":     ErrPermissionDenied,
+	"rate_limit_error":     ErrRateLimited,
+	"overloaded_error":     ErrOverloaded,
+}
+
+// statusSentinels maps statuses to sentinels for responses without a type
+var statusSentinels = map[int]error{
+	http.StatusUnauthorized:    ErrAuthRequired,
+	http.StatusForbidden:       ErrPermissionDenied,
+	http.StatusTooManyRequests: ErrRateLimited,
+	529:                        ErrOverloaded,
+}
+
+// Sentinel returns the sentinel error for the API error's type, or for its
+// status when the response had no known type, or nil if neither has one
+func (e *APIError) Sentinel() error {
+	if sentinel, ok := apiTypeSentinels[e.Type]; ok {
+		return sentinel
+	}
+	return statusSentinels[e.StatusCode]
+}
+
+// Is matches the API error's sentinel, so errors.Is(err, ErrAuthRequired)
+// holds for a rejected token
+func (e *APIError) Is(target error) bool {
+	sentinel := e.Sentinel()
+	return sentinel != nil && sentinel == target
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Sentinel errors for programmatic error handling
@@ -11,9 +12,25 @@ var (
 	ErrCredentialsNotFound = errors.New("credentials not found")
 	ErrTokenExpired      = errors.New("access token expired")
 	ErrCacheExpired      = errors.New("cache expired")
+	ErrNotModified       = errors.New("not modified")
 	ErrNoMatch           = errors.New("no match found")
+	ErrInvalidPath       = errors.New("invalid path")
+	ErrPathNotFound      = errors.New("path not found")
 	ErrRequestFailed     = errors.New("request failed")
 	ErrResponseParse     = errors.New("failed to parse response")
+	ErrThresholdExceeded = errors.New("usage threshold exceeded")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrOverloaded        = errors.New("API overloaded")
+)
+
+// Process exit codes. These are a stable contract for scripts and CI gates.
+const (
+	ExitOK        = 0 // success
+	ExitError     = 1 // any error not covered below, including invalid flags
+	ExitThreshold = 2 // a --fail-at threshold was reached
+	ExitAuth      = 3 // credentials missing, unreadable, or rejected
+	ExitAPI       = 4 // the usage API request failed
 )
 
 // AuthError represents an authentication-related error
@@ -38,11 +55,18 @@ func NewAuthError(source string, err error) *AuthError {
 // APIError represents an API request error with status code
 type APIError struct {
 	StatusCode int
+	// Type is the error type from the response body, such as
+	// "authentication_error", if it had one
+	Type       string
 	Message    string
 	Retriable  bool
+	RetryAfter time.Duration // server-requested wait from Retry-After, if any
 }
 
 func (e *APIError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("API error (status %d): %s (retry after %s)", e.StatusCode, e.Message, e.RetryAfter)
+	}
 	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
 }
 
@@ -94,6 +118,75 @@ func NewQueryError(query string, err error) *QueryError {
 	return &QueryError{Query: query, Err: err}
 }
 
+// ThresholdError reports a window whose utilization reached a limit
+type ThresholdError struct {
+	Window      string
+	Utilization float64
+	Limit       float64
+}
+
+func (e *ThresholdError) Error() string {
+	return fmt.Sprintf("%v: %s at %.0f%% (limit %.0f%%)", ErrThresholdExceeded, e.Window, e.Utilization, e.Limit)
+}
+
+func (e *ThresholdError) Unwrap() error {
+	return ErrThresholdExceeded
+}
+
+// StatusError ends the process with a specific exit code once a command has
+// printed its own result, such as a monitoring check's state. main prints
+// nothing further for it.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode maps an error to the process exit code for it
+func ExitCode(err error) int {
+	var authErr *AuthError
+	var apiErr *APIError
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &statusErr):
+		return statusErr.Code
+	case errors.Is(err, ErrThresholdExceeded):
+		return ExitThreshold
+	case errors.As(err, &authErr),
+		errors.Is(err, ErrAuthRequired),
+		errors.Is(err, ErrCredentialsNotFound),
+		errors.Is(err, ErrTokenExpired):
+		return ExitAuth
+	case errors.Is(err, ErrPermissionDenied):
+		return ExitAuth
+	case errors.As(err, &apiErr):
+		return ExitAPI
+	case errors.Is(err, ErrRequestFailed), errors.Is(err, ErrResponseParse):
+		return ExitAPI
+	}
+	return ExitError
+}
+
+// Code names the class of an error for logs and scripts, matching its exit
+// code: "threshold", "auth", "api", or "error" ("ok" for nil)
+func Code(err error) string {
+	switch ExitCode(err) {
+	case ExitOK:
+		return "ok"
+	case ExitThreshold:
+		return "threshold"
+	case ExitAuth:
+		return "auth"
+	case ExitAPI:
+		return "api"
+	}
+	return "error"
+}
+
 // Is checks if target error matches any of our sentinel errors
 func Is(err, target error) bool {
 	return errors.Is(err, target)
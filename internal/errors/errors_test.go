@@ -2,7 +2,10 @@ package errors
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAuthError(t *testing.T) {
@@ -119,3 +122,137 @@ func TestIsAndAs(t *testing.T) {
 		t.Errorf("AuthError.Source = %q, want %q", authErr.Source, "credentials")
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"threshold", &ThresholdError{Window: "five_hour", Utilization: 92, Limit: 90}, ExitThreshold},
+		{"auth error", NewAuthError("credentials", errors.New("unreadable")), ExitAuth},
+		{"wrapped sentinel", fmt.Errorf("loading: %w", ErrCredentialsNotFound), ExitAuth},
+		{"401", NewAPIError(401, "Unauthorized", false), ExitAuth},
+		{"403", NewAPIError(403, "Forbidden", false), ExitAuth},
+		{"authentication_error type", &APIError{StatusCode: 400, Type: "authentication_error"}, ExitAuth},
+		{"429 wrapped", fmt.Errorf("request failed after 3 retries: %w", NewAPIError(429, "Too Many Requests", true)), ExitAPI},
+		{"network", fmt.Errorf("%w: dial tcp: refused", ErrRequestFailed), ExitAPI},
+		{"status", &StatusError{Code: 1}, 1},
+		{"status over sentinel", fmt.Errorf("%w: %w", &StatusError{Code: 3}, ErrRequestFailed), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorSentinel(t *testing.T) {
+	tests := []struct {
+		err  *APIError
+		want error
+	}{
+		{&APIError{StatusCode: 401}, ErrAuthRequired},
+		{&APIError{StatusCode: 400, Type: "authentication_error"}, ErrAuthRequired},
+		{&APIError{StatusCode: 403, Type: "permission_error"}, ErrPermissionDenied},
+		{&APIError{StatusCode: 429, Type: "rate_limit_error"}, ErrRateLimited},
+		{&APIError{StatusCode: 529}, ErrOverloaded},
+		// The body's type wins over the status
+		{&APIError{StatusCode: 401, Type: "rate_limit_error"}, ErrRateLimited},
+		{&APIError{StatusCode: 500, Type: "api_error"}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Sentinel(); got != tt.want {
+			t.Errorf("%+v.Sentinel() = %v, want %v", tt.err, got, tt.want)
+		}
+		if tt.want != nil && !errors.Is(fmt.Errorf("fetch: %w", tt.err), tt.want) {
+			t.Errorf("errors.Is(%+v, %v) = false", tt.err, tt.want)
+		}
+	}
+	if errors.Is(&APIError{StatusCode: 500}, ErrAuthRequired) {
+		t.Error("a 500 shouldn't match ErrAuthRequired")
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "ok"},
+		{errors.New("boom"), "error"},
+		{&ThresholdError{Window: "five_hour", Utilization: 92, Limit: 90}, "threshold"},
+		{fmt.Errorf("loading: %w", ErrTokenExpired), "auth"},
+		{NewAPIError(503, "Service Unavailable", true), "api"},
+	}
+
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestThresholdError(t *testing.T) {
+	err := &ThresholdError{Window: "seven_day", Utilization: 81.4, Limit: 80}
+	if !errors.Is(err, ErrThresholdExceeded) {
+		t.Error("ThresholdError should unwrap to ErrThresholdExceeded")
+	}
+	if got := err.Error(); got != "usage threshold exceeded: seven_day at 81% (limit 80%)" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	apiErr := NewAPIError(429, "rate limited", true)
+	apiErr.RetryAfter = 30 * time.Second
+
+	tests := []struct {
+		err  error
+		want Report
+	}{
+		{fmt.Errorf("fetch: %w", apiErr), Report{Type: "api", Message: "fetch: API error (status 429): rate limited (retry after 30s)", ExitCode: ExitAPI, Status: 429, RetryAfter: 30, Hint: "The API is rate limiting requests; wait a while, or poll less often with a longer --cache."}},
+		{NewAPIError(400, "bad request", false), Report{Type: "api", Message: "API error (status 400): bad request", ExitCode: ExitAPI, Status: 400}},
+		{NewAuthError("credentials", ErrAuthRequired), Report{Type: "auth", Message: "authentication error (credentials): authentication required", ExitCode: ExitAuth, Hint: "Sign in with Claude Code by running `claude`."}},
+		{errors.New("bad flag"), Report{Type: "error", Message: "bad flag", ExitCode: ExitError}},
+	}
+	for _, tt := range tests {
+		if got := NewReport(tt.err); got != tt.want {
+			t.Errorf("NewReport(%v) = %+v, want %+v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("fetch: %w", NewAPIError(401, "unauthorized", false)), "run `claude` to sign in again"},
+		{NewAPIError(403, "forbidden", false), "claude-limits whoami"},
+		{NewAPIError(503, "unavailable", true), "try again shortly"},
+		{NewAuthError("credentials", fmt.Errorf("%w at /nope", ErrCredentialsNotFound)), "running `claude`"},
+		{fmt.Errorf("%w: dial tcp: timeout", ErrRequestFailed), "--proxy"},
+		{WithHint(NewAPIError(401, "unauthorized", false), "use the other account"), "use the other account"},
+		// An empty hint defers to the wrapped error's
+		{WithHint(NewAPIError(503, "unavailable", true), ""), "try again shortly"},
+		{NewAPIError(400, "bad request", false), ""},
+		{errors.New("plain"), ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got := Hint(tt.err)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("Hint(%v) = %q, want it to contain %q", tt.err, got, tt.want)
+		}
+	}
+
+	if WithHint(nil, "hint") != nil {
+		t.Error("WithHint(nil) should be nil")
+	}
+}
@@ -0,0 +1,83 @@
+package errors
+
+import "errors"
+
+// Hinter is implemented by errors that can suggest how to fix them
+type Hinter interface {
+	Hint() string
+}
+
+// hintError attaches a hint to an error
+type hintError struct {
+	err  error
+	hint string
+}
+
+func (e *hintError) Error() string {
+	return e.err.Error()
+}
+
+func (e *hintError) Unwrap() error {
+	return e.err
+}
+
+func (e *hintError) Hint() string {
+	return e.hint
+}
+
+// WithHint attaches a remediation hint to err, returned by Hint
+func WithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &hintError{err: err, hint: hint}
+}
+
+// Hint returns a suggestion for fixing err: that of the outermost error in
+// its chain with a hint, else one for a failed request. It's empty when
+// there's nothing to suggest.
+func Hint(err error) string {
+	for e := err; e != nil; {
+		var h Hinter
+		if !errors.As(e, &h) {
+			break
+		}
+		if hint := h.Hint(); hint != "" {
+			return hint
+		}
+		e = errors.Unwrap(h.(error))
+	}
+	if errors.Is(err, ErrRequestFailed) {
+		return "Check your network connection, or set a proxy with --proxy."
+	}
+	return ""
+}
+
+// Hint suggests how to get credentials the API accepts
+func (e *AuthError) Hint() string {
+	switch {
+	case errors.Is(e.Err, ErrCredentialsNotFound):
+		return "Sign in with Claude Code by running `claude`. If Claude Code keeps its config elsewhere, set CLAUDE_CONFIG_DIR or --claude-dir, or the profile's credentials path in the config file."
+	case errors.Is(e.Err, ErrTokenExpired):
+		return "The access token has expired; run `claude` to refresh it."
+	case errors.Is(e.Err, ErrAuthRequired):
+		return "Sign in with Claude Code by running `claude`."
+	}
+	return ""
+}
+
+// Hint suggests what to do about the response's error type or status
+func (e *APIError) Hint() string {
+	switch e.Sentinel() {
+	case ErrAuthRequired:
+		return "The access token was rejected and has likely expired; run `claude` to sign in again."
+	case ErrPermissionDenied:
+		return "The account can't read this usage; check the signed-in account and organization with `claude-limits whoami`."
+	case ErrRateLimited:
+		return "The API is rate limiting requests; wait a while, or poll less often with a longer --cache."
+	}
+	if e.StatusCode >= 500 {
+		return "The API is having trouble; try again shortly."
+	}
+	return ""
+}
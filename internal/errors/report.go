@@ -0,0 +1,38 @@
+package errors
+
+import "errors"
+
+// Report is the structured form of an error, printed on stderr when the
+// output format is JSON so scripts can branch on the failure mode
+type Report struct {
+	// Type is the error's class, as from Code: "threshold", "auth", "api",
+	// or "error"
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+	// Status is the HTTP status of a failed API request
+	Status int `json:"status,omitempty"`
+	// APIType is the error type the API gave, e.g. "rate_limit_error"
+	APIType string `json:"api_type,omitempty"`
+	// RetryAfter is the wait in seconds the API asked for before retrying
+	RetryAfter float64 `json:"retry_after,omitempty"`
+	// Hint suggests how to fix the error, as from Hint
+	Hint string `json:"hint,omitempty"`
+}
+
+// NewReport describes err as a Report
+func NewReport(err error) Report {
+	report := Report{
+		Type:     Code(err),
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+		Hint:     Hint(err),
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		report.Status = apiErr.StatusCode
+		report.APIType = apiErr.Type
+		report.RetryAfter = apiErr.RetryAfter.Seconds()
+	}
+	return report
+}
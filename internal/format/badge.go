@@ -0,0 +1,128 @@
+package format
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/raster"
+)
+
+// badgeColors are shields.io's colors for each threshold level
+var badgeColors = map[string]string{LevelOK: "#4c1", LevelWarning: "#dfb317", LevelCritical: "#e05d44"}
+
+// Badge layout, in pixels
+const (
+	badgeLabelColor   = "#555"
+	badgeUnknownColor = "#9f9f9f"
+	badgeHeight       = 20
+	badgePadding      = 5
+)
+
+// Badge is a shields-style badge: a gray label beside a colored message
+type Badge struct {
+	Label   string
+	Message string
+	// Color is the message's background, e.g. "#4c1"
+	Color string
+}
+
+// NewBadge builds a badge of the windows' utilization, e.g. label "claude"
+// and message "5h 45% | wk 23%", colored by the highest threshold level
+// among them. Windows missing from usage are left out; with none left, the
+// message is "no data" on gray.
+func NewBadge(usage *models.Usage, label string, windows []string, thresholds Colors) Badge {
+	badge := Badge{Label: label, Message: "no data", Color: badgeUnknownColor}
+	var parts []string
+	worst := ""
+	for _, name := range windows {
+		w := usage.Window(name)
+		if w == nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", WindowLabel(name), w.Utilization))
+		level := thresholds.ThresholdsFor(name, DefaultThresholds).Level(w.Utilization)
+		if worst == "" || level == LevelCritical || (level == LevelWarning && worst == LevelOK) {
+			worst = level
+		}
+	}
+	if len(parts) > 0 {
+		badge.Message = strings.Join(parts, " | ")
+		badge.Color = badgeColors[worst]
+	}
+	return badge
+}
+
+// SVG renders the badge in shields' flat style
+func (b Badge) SVG() string {
+	labelWidth := badgeTextWidth(b.Label) + 2*badgePadding
+	messageWidth := badgeTextWidth(b.Message) + 2*badgePadding
+	width := labelWidth + messageWidth
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+
+	var s strings.Builder
+	fmt.Fprintf(&s, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="%s: %s">`+"\n", width, badgeHeight, label, message)
+	fmt.Fprintf(&s, "<title>%s: %s</title>\n", label, message)
+	s.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&s, `<clipPath id="r"><rect width="%d" height="%d" rx="3" fill="#fff"/></clipPath>`+"\n", width, badgeHeight)
+	fmt.Fprintf(&s, `<g clip-path="url(#r)"><rect width="%d" height="%d" fill="%s"/><rect x="%d" width="%d" height="%d" fill="%s"/><rect width="%d" height="%d" fill="url(#s)"/></g>`+"\n",
+		labelWidth, badgeHeight, badgeLabelColor, labelWidth, messageWidth, badgeHeight, html.EscapeString(b.Color), width, badgeHeight)
+	s.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	for _, text := range []struct {
+		x     float64
+		value string
+	}{{float64(labelWidth) / 2, label}, {float64(labelWidth) + float64(messageWidth)/2, message}} {
+		fmt.Fprintf(&s, `<text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%.1f" y="14">%s</text>`+"\n", text.x, text.value, text.x, text.value)
+	}
+	s.WriteString("</g>\n</svg>\n")
+	return s.String()
+}
+
+// WritePNG renders the badge as a PNG, scale device pixels per badge pixel
+func (b Badge) WritePNG(w io.Writer, scale int) error {
+	labelColor, err := raster.ParseHex(badgeLabelColor)
+	if err != nil {
+		return err
+	}
+	messageColor, err := raster.ParseHex(b.Color)
+	if err != nil {
+		return err
+	}
+	white, _ := raster.ParseHex("#fff")
+
+	labelWidth := raster.TextWidth(b.Label) + 2*badgePadding
+	messageWidth := raster.TextWidth(b.Message) + 2*badgePadding
+	textY := (badgeHeight - raster.GlyphHeight) / 2
+
+	c := raster.New(labelWidth+messageWidth, badgeHeight, scale)
+	c.Fill(0, 0, labelWidth, badgeHeight, labelColor)
+	c.Fill(labelWidth, 0, messageWidth, badgeHeight, messageColor)
+	c.Text(badgePadding, textY, b.Label, white)
+	c.Text(labelWidth+badgePadding, textY, b.Message, white)
+	c.RoundCorners(3)
+	return c.WritePNG(w)
+}
+
+// badgeTextWidth estimates the width of s in 11px Verdana, which SVG badges
+// are set in, so the badge fits its text without measuring fonts
+func badgeTextWidth(s string) int {
+	width := 0.0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("ijlI|!.,:;' ", r):
+			width += 3.8
+		case strings.ContainsRune("frt()", r):
+			width += 4.6
+		case strings.ContainsRune("mwMW%", r):
+			width += 10.8
+		case r >= 'A' && r <= 'Z':
+			width += 7.8
+		default:
+			width += 7
+		}
+	}
+	return int(math.Ceil(width))
+}
@@ -0,0 +1,65 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestNewBadge(t *testing.T) {
+	var usage models.Usage
+	raw := `{"five_hour": {"utilization": 45}, "seven_day": {"utilization": 83}, "seven_day_opus": {"utilization": 96}}`
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		t.Fatal(err)
+	}
+	windows := []string{models.WindowFiveHour, models.WindowSevenDay}
+
+	tests := []struct {
+		name    string
+		windows []string
+		want    Badge
+	}{
+		{"worst level", windows, Badge{"claude", "5h 45% | wk 83%", "#dfb317"}},
+		{"ok", windows[:1], Badge{"claude", "5h 45%", "#4c1"}},
+		{"critical", []string{models.WindowSevenDayOpus, models.WindowFiveHour}, Badge{"claude", "opus 96% | 5h 45%", "#e05d44"}},
+		{"missing", []string{models.WindowSevenDaySonnet}, Badge{"claude", "no data", "#9f9f9f"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewBadge(&usage, "claude", tt.windows, Colors{}); got != tt.want {
+				t.Errorf("NewBadge = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	svg := Badge{Label: "claude", Message: "5h <45%>", Color: "#4c1"}.SVG()
+	for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, `fill="#4c1"`, `aria-label="claude: 5h &lt;45%&gt;"`, "</svg>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestBadgePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (Badge{Label: "claude", Message: "5h 45%", Color: "#4c1"}).WritePNG(&buf, 2); err != nil {
+		t.Fatalf("WritePNG failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("PNG doesn't decode: %v", err)
+	}
+	if got := img.Bounds().Dy(); got != 2*badgeHeight {
+		t.Errorf("height = %d, want %d", got, 2*badgeHeight)
+	}
+
+	if err := (Badge{Label: "claude", Message: "x", Color: "green"}).WritePNG(&buf, 1); err == nil {
+		t.Error("expected error for a color that isn't hex")
+	}
+}
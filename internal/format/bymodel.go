@@ -0,0 +1,131 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Thresholds are the utilization percentages at which usage turns yellow and red
+type Thresholds struct {
+	Warn     float64
+	Critical float64
+}
+
+// DefaultThresholds color the overall windows
+var DefaultThresholds = Thresholds{Warn: 80, Critical: 95}
+
+// DefaultModelThresholds color per-model windows. They warn earlier because
+// Max users hit the Opus cap long before the overall weekly cap.
+var DefaultModelThresholds = Thresholds{Warn: 60, Critical: 85}
+
+// Threshold levels a utilization percentage can be at
+const (
+	LevelOK       = "ok"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Level returns the threshold level of a utilization percentage
+func (t Thresholds) Level(value float64) string {
+	switch {
+	case value >= t.Critical:
+		return LevelCritical
+	case value >= t.Warn:
+		return LevelWarning
+	default:
+		return LevelOK
+	}
+}
+
+// Color returns the color for a utilization percentage
+func (t Thresholds) Color(value float64, colors Colors) string {
+	switch t.Level(value) {
+	case LevelCritical:
+		return colors.Red
+	case LevelWarning:
+		return colors.Yellow
+	default:
+		return colors.Green
+	}
+}
+
+// ModelUsage is one row of the weekly per-model breakdown
+type ModelUsage struct {
+	Model       string     `json:"model"`
+	Window      string     `json:"window"`
+	Utilization float64    `json:"utilization"`
+	ResetsAt    *time.Time `json:"resets_at"`
+}
+
+// modelWindows are the weekly windows shown by ByModel, overall first
+var modelWindows = []struct{ model, window string }{
+	{"All models", models.WindowSevenDay},
+	{"Opus", models.WindowSevenDayOpus},
+	{"Sonnet", models.WindowSevenDaySonnet},
+}
+
+// ModelBreakdown returns the weekly windows present in usage, overall first
+func ModelBreakdown(usage *models.Usage) []ModelUsage {
+	var rows []ModelUsage
+	for _, mw := range modelWindows {
+		if w := usage.Window(mw.window); w != nil {
+			rows = append(rows, ModelUsage{
+				Model:       mw.model,
+				Window:      mw.window,
+				Utilization: w.Utilization,
+				ResetsAt:    w.ResetsAt,
+			})
+		}
+	}
+	return rows
+}
+
+const modelBarWidth = 20
+
+// ByModel renders weekly usage per model with a bar for each, and calls out
+// a model whose cap will run out before the overall one
+func ByModel(usage *models.Usage, colors Colors, formats Formats) string {
+	rows := ModelBreakdown(usage)
+	if len(rows) == 0 {
+		return "No weekly usage windows in the response"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%sWeekly Usage by Model%s\n", colors.Bold, colors.Cyan, colors.Reset)
+	b.WriteString(strings.Repeat("═", 50) + "\n")
+
+	for _, row := range rows {
+		fallback := DefaultModelThresholds
+		if row.Window == models.WindowSevenDay {
+			fallback = DefaultThresholds
+		}
+		color := colors.ThresholdsFor(row.Window, fallback).Color(row.Utilization, colors)
+
+		fmt.Fprintf(&b, "  %-11s %s%3.0f%%  %s%s", row.Model, color, row.Utilization, bar(row.Utilization, modelBarWidth), colors.Reset)
+		if row.ResetsAt != nil {
+			fmt.Fprintf(&b, "  resets %s", ResetTime(*row.ResetsAt, "Mon "+formats.Time, formats))
+		}
+		b.WriteString("\n")
+	}
+
+	if overall := usage.Window(models.WindowSevenDay); overall != nil {
+		for _, row := range rows {
+			if row.Window != models.WindowSevenDay && row.Utilization > overall.Utilization {
+				fmt.Fprintf(&b, "\n%s will hit its cap first: %.0f%% used vs %.0f%% overall\n", row.Model, row.Utilization, overall.Utilization)
+				break
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// bar renders a percentage as a fixed-width bar
+func bar(value float64, width int) string {
+	filled := int(value / 100 * float64(width))
+	filled = max(0, min(width, filled))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
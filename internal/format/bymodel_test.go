@@ -0,0 +1,60 @@
+package format
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestModelBreakdown(t *testing.T) {
+	var usage models.Usage
+	data := `{"five_hour": {"utilization": 10}, "seven_day": {"utilization": 34},
+		"seven_day_opus": {"utilization": 78}}`
+	if err := json.Unmarshal([]byte(data), &usage); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := ModelBreakdown(&usage)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Model != "All models" || rows[1].Model != "Opus" || rows[1].Utilization != 78 {
+		t.Errorf("rows = %+v", rows)
+	}
+
+	out := ByModel(&usage, Colors{}, Formats{Time: "15:04"})
+	if !strings.Contains(out, "Opus will hit its cap first: 78% used vs 34% overall") {
+		t.Errorf("missing Opus callout:\n%s", out)
+	}
+	if strings.Contains(out, "Sonnet") {
+		t.Errorf("absent Sonnet window rendered:\n%s", out)
+	}
+}
+
+func TestThresholdsColor(t *testing.T) {
+	colors := NewANSIColors()
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{59, Green},
+		{60, Yellow},
+		{85, Red},
+	}
+	for _, tt := range tests {
+		if got := DefaultModelThresholds.Color(tt.value, colors); got != tt.want {
+			t.Errorf("Color(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := bar(50, 10); got != "█████░░░░░" {
+		t.Errorf("bar(50, 10) = %q", got)
+	}
+	if got := bar(150, 4); got != "████" {
+		t.Errorf("bar(150, 4) = %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Themes are the named palettes selectable with the colors.theme setting
+var Themes = map[string]Colors{
+	"default": NewANSIColors(),
+	"bright": {
+		Bold:   Bold,
+		Cyan:   "\033[96m",
+		Yellow: "\033[93m",
+		Green:  "\033[92m",
+		Red:    "\033[91m",
+		Reset:  Reset,
+	},
+	// Blue, orange, and vermillion stay distinct with red-green color blindness
+	"colorblind": {
+		Bold:   Bold,
+		Cyan:   Cyan,
+		Yellow: "\033[38;5;214m",
+		Green:  "\033[38;5;33m",
+		Red:    "\033[38;5;166m",
+		Reset:  Reset,
+	},
+	// No hues: warnings are bold and critical values are reversed
+	"mono": {
+		Bold:   Bold,
+		Cyan:   Bold,
+		Yellow: Bold,
+		Green:  "",
+		Red:    "\033[1;7m",
+		Reset:  Reset,
+	},
+}
+
+// ThemeNames returns the theme names in sorted order
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colorCodes maps color names to ANSI SGR codes
+var colorCodes = map[string]int{
+	"black": 30, "red": 31, "green": 32, "yellow": 33,
+	"blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+}
+
+// ParseColor converts a color spec to an ANSI escape sequence. A spec is one
+// or more space-separated parts: a name ("red", "bright-red"), "bold",
+// "underline", a 256-color index ("208"), or a hex RGB value ("#ff8800").
+// "none" means no color.
+func ParseColor(spec string) (string, error) {
+	return ParseColorLevel(spec, ColorTrue)
+}
+
+// ParseColorLevel is like ParseColor, reducing 256-color and hex values to
+// the nearest color a terminal with the given level can show
+func ParseColorLevel(spec string, level ColorLevel) (string, error) {
+	var codes []string
+	for _, part := range strings.Fields(strings.ToLower(spec)) {
+		code, err := colorCode(part, level)
+		if err != nil {
+			return "", err
+		}
+		if code != "" {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
+
+func colorCode(part string, level ColorLevel) (string, error) {
+	switch part {
+	case "none":
+		return "", nil
+	case "bold":
+		return "1", nil
+	case "underline":
+		return "4", nil
+	}
+
+	if code, ok := colorCodes[part]; ok {
+		return strconv.Itoa(code), nil
+	}
+	if name, ok := strings.CutPrefix(part, "bright-"); ok {
+		if code, ok := colorCodes[name]; ok {
+			return strconv.Itoa(code + 60), nil
+		}
+	}
+	if n, err := strconv.Atoi(part); err == nil && n >= 0 && n <= 255 {
+		if level >= Color256 {
+			return "38;5;" + part, nil
+		}
+		r, g, b := xterm256ToRGB(n)
+		return strconv.Itoa(rgbTo16(r, g, b)), nil
+	}
+	if hex, ok := strings.CutPrefix(part, "#"); ok && len(hex) == 6 {
+		if rgb, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			r, g, b := uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)
+			switch {
+			case level >= ColorTrue:
+				return fmt.Sprintf("38;2;%d;%d;%d", r, g, b), nil
+			case level == Color256:
+				return "38;5;" + strconv.Itoa(rgbTo256(r, g, b)), nil
+			default:
+				return strconv.Itoa(rgbTo16(r, g, b)), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("invalid color %q (use a name like red or bright-red, a 256-color index, or #rrggbb)", part)
+}
+
+// cubeLevels are the channel intensities of the 256-color palette's 6x6x6 cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// basicRGB approximates the 16 standard colors
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+	{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+	{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// xterm256ToRGB returns the RGB value of a 256-color palette index
+func xterm256ToRGB(n int) (uint8, uint8, uint8) {
+	switch {
+	case n < 16:
+		c := basicRGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	default:
+		gray := uint8(8 + 10*(n-232))
+		return gray, gray, gray
+	}
+}
+
+// rgbTo256 returns the nearest index in the 256-color palette's color cube
+func rgbTo256(r, g, b uint8) int {
+	level := func(v uint8) int {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		default:
+			return (int(v) - 35) / 40
+		}
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+// rgbTo16 returns the SGR foreground code of the nearest of the 16 standard colors
+func rgbTo16(r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i, c := range basicRGB {
+		dr, dg, db := int(r)-int(c[0]), int(g)-int(c[1]), int(b)-int(c[2])
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 8 {
+		return 30 + best
+	}
+	return 90 + best - 8
+}
+
+// ThresholdsFor returns the thresholds for a window: its own, then the
+// default in Thresholds[""], then fallback
+func (c Colors) ThresholdsFor(window string, fallback Thresholds) Thresholds {
+	if t, ok := c.Thresholds[window]; ok {
+		return t
+	}
+	if t, ok := c.Thresholds[""]; ok {
+		return t
+	}
+	return fallback
+}
+
+// UtilizationColor returns the color for a window's utilization percentage
+func (c Colors) UtilizationColor(window string, value float64) string {
+	return c.ThresholdsFor(window, DefaultThresholds).Color(value, c)
+}
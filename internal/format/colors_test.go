@@ -0,0 +1,95 @@
+package format
+
+import "testing"
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"red", "\033[31m", false},
+		{"bright-red", "\033[91m", false},
+		{"bold yellow", "\033[1;33m", false},
+		{"208", "\033[38;5;208m", false},
+		{"#ff8800", "\033[38;2;255;136;0m", false},
+		{"none", "", false},
+		{"purple", "", true},
+		{"256", "", true},
+		{"#ff88", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColor(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseColor(%q) = %q, want error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColor(%q) failed: %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUtilizationColorThresholds(t *testing.T) {
+	colors := NewANSIColors()
+	colors.Thresholds = map[string]Thresholds{
+		"":               {Warn: 70, Critical: 90},
+		"seven_day_opus": {Warn: 50, Critical: 75},
+	}
+
+	tests := []struct {
+		window string
+		value  float64
+		want   string
+	}{
+		{"five_hour", 69, Green},
+		{"five_hour", 70, Yellow},
+		{"seven_day_opus", 60, Yellow},
+		{"seven_day_opus", 80, Red},
+	}
+	for _, tt := range tests {
+		if got := colors.UtilizationColor(tt.window, tt.value); got != tt.want {
+			t.Errorf("UtilizationColor(%s, %v) = %q, want %q", tt.window, tt.value, got, tt.want)
+		}
+	}
+
+	// Without configured thresholds, the fallback applies
+	if got := NewANSIColors().ThresholdsFor("five_hour", DefaultModelThresholds); got != DefaultModelThresholds {
+		t.Errorf("ThresholdsFor = %+v, want fallback", got)
+	}
+}
+
+func TestParseColorLevel(t *testing.T) {
+	tests := []struct {
+		spec  string
+		level ColorLevel
+		want  string
+	}{
+		{"#ff8800", Color256, "\033[38;5;208m"},
+		{"#ff8800", Color16, "\033[93m"},
+		{"208", Color16, "\033[93m"},
+		{"21", Color16, "\033[94m"},
+		{"bold #000000", Color16, "\033[1;30m"},
+		{"red", Color16, "\033[31m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColorLevel(tt.spec, tt.level)
+			if err != nil {
+				t.Fatalf("ParseColorLevel(%q) failed: %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColorLevel(%q, %d) = %q, want %q", tt.spec, tt.level, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Compact defaults
+const (
+	DefaultCompactSeparator = " | "
+	compactUnknown          = "?"
+)
+
+// DefaultCompactWindows are the windows shown by compact output when none are configured
+var DefaultCompactWindows = []string{models.WindowFiveHour, models.WindowSevenDay}
+
+// windowLabels are the short names used in one-line output
+var windowLabels = map[string]string{
+	models.WindowFiveHour:          "5h",
+	models.WindowSevenDay:          "wk",
+	models.WindowSevenDayOpus:      "opus",
+	models.WindowSevenDaySonnet:    "sonnet",
+	models.WindowSevenDayOAuthApps: "apps",
+}
+
+// WindowLabel returns the short label for a window, or its name if it has none
+func WindowLabel(name string) string {
+	if label, ok := windowLabels[name]; ok {
+		return label
+	}
+	return name
+}
+
+// WindowName resolves a short label such as "5h" or "opus" to its window
+// name. Anything else is returned unchanged.
+func WindowName(label string) string {
+	for name, l := range windowLabels {
+		if l == label {
+			return name
+		}
+	}
+	return label
+}
+
+// CompactOptions selects what compact output shows
+type CompactOptions struct {
+	Windows    []string // windows to show, in order; empty uses DefaultCompactWindows
+	Separator  string   // between windows; empty uses DefaultCompactSeparator
+	HideResets bool     // omit reset times
+
+	// Trends are sparklines shown after each window's percentage, keyed by window name
+	Trends map[string]string
+}
+
+// Compact renders usage on a single line for status bars, e.g.
+// "5h: 62% (resets 14:30) | wk: 34% (resets Tue 08:00)".
+// Windows missing from the response render as "?".
+func Compact(usage *models.Usage, colors Colors, formats Formats, opts CompactOptions) string {
+	windows := opts.Windows
+	if len(windows) == 0 {
+		windows = DefaultCompactWindows
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = DefaultCompactSeparator
+	}
+
+	parts := make([]string, 0, len(windows))
+	for _, name := range windows {
+		parts = append(parts, WindowLabel(name)+": "+compactWindow(usage.Window(name), name, colors, formats, opts))
+	}
+	return strings.Join(parts, sep)
+}
+
+func compactWindow(w *models.Window, name string, colors Colors, formats Formats, opts CompactOptions) string {
+	if w == nil {
+		return compactUnknown + "%"
+	}
+
+	s := fmt.Sprintf("%s%d%%%s", colors.UtilizationColor(name, w.Utilization), int64(w.Utilization), colors.Reset)
+	if trend := opts.Trends[name]; trend != "" {
+		s += " " + trend
+	}
+	if opts.HideResets || w.ResetsAt == nil {
+		return s
+	}
+
+	// Weekly windows reset days away, so include the weekday
+	layout := formats.Time
+	if name != models.WindowFiveHour {
+		layout = "Mon " + layout
+	}
+	return s + " (resets " + ResetTime(*w.ResetsAt, layout, formats) + ")"
+}
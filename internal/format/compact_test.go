@@ -0,0 +1,79 @@
+package format
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func compactUsage(t *testing.T) *models.Usage {
+	t.Helper()
+	resets := time.Date(2030, 1, 1, 14, 30, 0, 0, time.Local).Format(time.RFC3339)
+	weekly := time.Date(2030, 1, 1, 8, 0, 0, 0, time.Local).Format(time.RFC3339)
+	data := `{"five_hour": {"utilization": 62.4, "resets_at": "` + resets + `"},
+		"seven_day": {"utilization": 34, "resets_at": "` + weekly + `"}}`
+
+	var usage models.Usage
+	if err := json.Unmarshal([]byte(data), &usage); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return &usage
+}
+
+func TestCompact(t *testing.T) {
+	usage := compactUsage(t)
+	formats := Formats{Time: "15:04"}
+
+	tests := []struct {
+		name string
+		opts CompactOptions
+		want string
+	}{
+		{"defaults", CompactOptions{}, "5h: 62% (resets 14:30) | wk: 34% (resets Tue 08:00)"},
+		{"hide resets", CompactOptions{HideResets: true}, "5h: 62% | wk: 34%"},
+		{"separator", CompactOptions{HideResets: true, Separator: " / "}, "5h: 62% / wk: 34%"},
+		{"trend", CompactOptions{HideResets: true, Trends: map[string]string{models.WindowFiveHour: "▁▄█"}}, "5h: 62% ▁▄█ | wk: 34%"},
+		{"selection", CompactOptions{Windows: []string{models.WindowSevenDay, models.WindowSevenDayOpus}, HideResets: true}, "wk: 34% | opus: ?%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compact(usage, Colors{}, formats, tt.opts)
+			if got != tt.want {
+				t.Errorf("Compact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactRelative(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.Local)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	usage := compactUsage(t)
+	got := Compact(usage, Colors{}, Formats{Time: "15:04", Relative: true}, CompactOptions{Windows: []string{models.WindowFiveHour}})
+	if want := "5h: 62% (resets in 2h 30m)"; got != want {
+		t.Errorf("Compact() = %q, want %q", got, want)
+	}
+}
+
+func TestWindowLabel(t *testing.T) {
+	if got := WindowLabel(models.WindowFiveHour); got != "5h" {
+		t.Errorf("WindowLabel(five_hour) = %q", got)
+	}
+	if got := WindowLabel("future_window"); got != "future_window" {
+		t.Errorf("WindowLabel(future_window) = %q", got)
+	}
+}
+
+func TestWindowName(t *testing.T) {
+	if got := WindowName("opus"); got != models.WindowSevenDayOpus {
+		t.Errorf("WindowName(opus) = %q", got)
+	}
+	if got := WindowName(models.WindowSevenDay); got != models.WindowSevenDay {
+		t.Errorf("WindowName(seven_day) = %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// ComparisonRow is one account in a comparison table
+type ComparisonRow struct {
+	Label string
+	Usage *models.Usage
+	// Err is shown in place of usage that couldn't be fetched
+	Err error
+}
+
+// comparisonGap separates comparison table columns
+const comparisonGap = "  "
+
+// WriteComparison writes a table with a row per account and a column per
+// window, each cell the window's utilization and when it resets, e.g.
+//
+//	ACCOUNT   5H               WK
+//	build-1   62% (in 2h 14m)  34% (in 3d 4h)
+//
+// Windows missing from an account show "-". With no windows, every window
+// any account has is shown.
+func WriteComparison(w io.Writer, rows []ComparisonRow, windows []string, colors Colors) error {
+	if len(windows) == 0 {
+		windows = comparisonWindows(rows)
+	}
+
+	header := []string{"ACCOUNT"}
+	for _, name := range windows {
+		header = append(header, strings.ToUpper(WindowLabel(name)))
+	}
+	table := [][]string{header}
+	for _, row := range rows {
+		cells := []string{row.Label}
+		if row.Err != nil {
+			cells = append(cells, colors.Red+"error: "+row.Err.Error()+colors.Reset)
+			table = append(table, cells)
+			continue
+		}
+		for _, name := range windows {
+			cells = append(cells, comparisonCell(row.Usage.Window(name), name, colors))
+		}
+		table = append(table, cells)
+	}
+
+	// Error messages span the window columns, so don't widen them
+	widths := make([]int, len(header))
+	for i, cells := range table {
+		for j, cell := range cells {
+			if i > 0 && rows[i-1].Err != nil && j > 0 {
+				continue
+			}
+			widths[j] = max(widths[j], visibleWidth(cell))
+		}
+	}
+
+	for i, cells := range table {
+		var line strings.Builder
+		for j, cell := range cells {
+			if j == len(cells)-1 {
+				line.WriteString(cell)
+				break
+			}
+			line.WriteString(padRight(cell, widths[j]) + comparisonGap)
+		}
+		if i == 0 {
+			fmt.Fprint(w, colors.Bold+line.String()+colors.Reset)
+		} else {
+			fmt.Fprint(w, line.String())
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// comparisonCell renders a window as its utilization and time to reset
+func comparisonCell(w *models.Window, name string, colors Colors) string {
+	if w == nil {
+		return "-"
+	}
+	s := fmt.Sprintf("%s%.0f%%%s", colors.UtilizationColor(name, w.Utilization), w.Utilization, colors.Reset)
+	if w.ResetsAt != nil {
+		s += " (" + Relative(*w.ResetsAt) + ")"
+	}
+	return s
+}
+
+// comparisonWindows returns every window any row has, in order first seen
+func comparisonWindows(rows []ComparisonRow) []string {
+	var windows []string
+	for _, row := range rows {
+		if row.Usage == nil {
+			continue
+		}
+		for _, w := range row.Usage.Windows() {
+			if !slices.Contains(windows, w.Name) {
+				windows = append(windows, w.Name)
+			}
+		}
+	}
+	return windows
+}
@@ -0,0 +1,42 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestWriteComparison(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	parse := func(raw string) *models.Usage {
+		var u models.Usage
+		if err := json.Unmarshal([]byte(raw), &u); err != nil {
+			t.Fatal(err)
+		}
+		return &u
+	}
+	rows := []ComparisonRow{
+		{Label: "build-1", Usage: parse(`{"five_hour": {"utilization": 62, "resets_at": "2030-01-01T14:14:00Z"}, "seven_day": {"utilization": 34}}`)},
+		{Label: "build-22", Usage: parse(`{"five_hour": {"utilization": 5}, "seven_day_opus": {"utilization": 90}}`)},
+		{Label: "ci", Err: errors.New("credentials not found")},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteComparison(&buf, rows, nil, Colors{}); err != nil {
+		t.Fatalf("WriteComparison failed: %v", err)
+	}
+	want := "ACCOUNT   5H               WK   OPUS\n" +
+		"build-1   62% (in 2h 14m)  34%  -\n" +
+		"build-22  5%               -    90%\n" +
+		"ci        error: credentials not found\n"
+	if buf.String() != want {
+		t.Errorf("WriteComparison =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
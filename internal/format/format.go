@@ -2,10 +2,13 @@
 package format
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +33,13 @@ type Colors struct {
 	Green  string
 	Red    string
 	Reset  string
+
+	// Level is the terminal's color support, which custom colors are reduced to
+	Level ColorLevel
+
+	// Thresholds pick the utilization colors, keyed by window name, with ""
+	// as the default for every window. Nil uses DefaultThresholds.
+	Thresholds map[string]Thresholds
 }
 
 // Formats holds the configurable date/time format strings
@@ -37,6 +47,16 @@ type Formats struct {
 	Datetime string // Format for full datetime (e.g., "Mon, Jan 2 2006 at 3:04 PM MST")
 	Date     string // Format for date only (e.g., "Mon, Jan 2 2006")
 	Time     string // Format for time only (e.g., "3:04 PM")
+
+	// Relative renders reset times as countdowns (e.g., "in 2h 14m")
+	Relative bool
+
+	// RawDurations leaves duration fields such as resets_in_seconds as
+	// numbers instead of rendering them like "2h 14m"
+	RawDurations bool
+
+	// Locale translates dates, numbers, and table labels; the zero value is English
+	Locale Locale
 }
 
 // DefaultFormats returns the default format configuration
@@ -48,11 +68,29 @@ func DefaultFormats() Formats {
 	}
 }
 
-// NewColors creates a Colors configuration based on terminal and user preferences
+// NewColors creates a Colors configuration for stdout based on terminal
+// capability and user preferences; see DetectColorLevel
 func NewColors(noColor bool) Colors {
-	if !IsTerminal() || noColor {
+	return NewColorsFor(os.Stdout, noColor)
+}
+
+// NewColorsFor is like NewColors for output written to f, e.g. os.Stderr
+func NewColorsFor(f *os.File, noColor bool) Colors {
+	if noColor {
 		return Colors{}
 	}
+	level := DetectColorLevel(f)
+	if level == ColorNone {
+		return Colors{}
+	}
+	colors := NewANSIColors()
+	colors.Level = level
+	return colors
+}
+
+// NewANSIColors returns the full ANSI palette regardless of whether stdout is
+// a terminal, for consumers such as status lines that render escape codes
+func NewANSIColors() Colors {
 	return Colors{
 		Bold:   Bold,
 		Cyan:   Cyan,
@@ -60,16 +98,20 @@ func NewColors(noColor bool) Colors {
 		Green:  Green,
 		Red:    Red,
 		Reset:  Reset,
+		Level:  ColorTrue,
 	}
 }
 
 // IsTerminal returns true if stdout is a terminal
 func IsTerminal() bool {
-	fi, err := os.Stdout.Stat()
-	if err != nil {
-		return false
-	}
-	return (fi.Mode() & os.ModeCharDevice) != 0
+	return isTerminal(os.Stdout)
+}
+
+// IsANSITerminal returns true if stdout is a terminal that interprets ANSI
+// escape sequences, such as cursor movement. On Windows this turns on
+// virtual terminal processing where the console supports it.
+func IsANSITerminal() bool {
+	return isTerminal(os.Stdout) && vtEnabled(os.Stdout)
 }
 
 // JSON formats usage data as indented JSON
@@ -79,6 +121,33 @@ func JSON(usage *models.Usage) (string, error) {
 
 // Table formats usage data as a human-readable table
 func Table(usage *models.Usage, colors Colors, formats Formats) error {
+	return TableWithDeltas(usage, nil, colors, formats)
+}
+
+// TableWithDeltas formats usage data as a table, annotating numeric fields with
+// the change since prev. A nil prev renders the plain table.
+func TableWithDeltas(usage, prev *models.Usage, colors Colors, formats Formats) error {
+	return TableWithOptions(usage, colors, formats, TableOptions{Prev: prev})
+}
+
+// TableOptions holds optional annotations for table output
+type TableOptions struct {
+	// Prev annotates numeric fields with the change since this snapshot
+	Prev *models.Usage
+	// Trends are sparklines appended to fields, keyed by underscore-joined field path
+	Trends map[string]string
+	// Width truncates rows to this many columns, e.g. TerminalWidth(); 0
+	// leaves them whole
+	Width int
+}
+
+// TableWithOptions formats usage data as a table with optional annotations
+func TableWithOptions(usage *models.Usage, colors Colors, formats Formats, opts TableOptions) error {
+	return WriteTable(os.Stdout, usage, colors, formats, opts)
+}
+
+// WriteTable is TableWithOptions writing to w instead of stdout
+func WriteTable(w io.Writer, usage *models.Usage, colors Colors, formats Formats, opts TableOptions) error {
 	var data map[string]interface{}
 	if err := json.Unmarshal(usage.Raw, &data); err != nil {
 		// Fall back to JSON output on parse error
@@ -86,21 +155,238 @@ func Table(usage *models.Usage, colors Colors, formats Formats) error {
 		if err != nil {
 			return err
 		}
-		fmt.Println(j)
+		_, err = fmt.Fprintln(w, j)
+		return err
+	}
+
+	notes := make(map[string]string)
+	for path, d := range Deltas(opts.Prev, usage) {
+		notes[path] = FormatDelta(d, colors)
+	}
+	for path, trend := range opts.Trends {
+		if note, ok := notes[path]; ok {
+			notes[path] = note + "  " + trend
+		} else {
+			notes[path] = trend
+		}
+	}
+
+	// Rows are buffered so a failing writer is reported once
+	var buf bytes.Buffer
+	t := &tableWriter{w: &buf, notes: notes, colors: colors, formats: formats, width: opts.Width, labels: make(map[int]int)}
+	known, other := splitKnown(data)
+	t.measure(known, 0)
+	t.measure(other, 2)
+	limits := rateLimitRows(usage.RateLimits)
+	t.measure(limits, 2)
+
+	rule := ruleWidth
+	if opts.Width > 0 && opts.Width < rule {
+		rule = opts.Width
+	}
+	buf.WriteString("\n")
+	t.line(fmt.Sprintf("%s%s%s%s", colors.Bold, colors.Cyan, formats.Locale.TableTitle(), colors.Reset))
+	buf.WriteString(strings.Repeat("═", rule) + "\n")
+
+	t.rows(known, "", "")
+
+	// Fields the API added since are shown apart, rather than mixed in
+	// with the windows
+	if len(other) > 0 {
+		buf.WriteString("\n")
+		t.line(fmt.Sprintf("%s%s:%s", colors.Bold, formats.Locale.Label("other"), colors.Reset))
+		t.rows(other, "  ", "")
+	}
+
+	// Rate-limit headers get their own section, since they can describe
+	// limits the body doesn't
+	if len(limits) > 0 {
+		buf.WriteString("\n")
+		t.line(fmt.Sprintf("%s%s:%s", colors.Bold, formats.Locale.Label("rate_limits"), colors.Reset))
+		t.rows(limits, "  ", "rate_limits")
+	}
+
+	buf.WriteString("\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// splitKnown splits decoded usage into the fields claude-limits
+// understands and the rest, dropping null ones
+func splitKnown(data map[string]interface{}) (known, other map[string]interface{}) {
+	known = make(map[string]interface{}, len(data))
+	other = make(map[string]interface{})
+	for key, value := range data {
+		switch {
+		case models.IsKnownField(key):
+			known[key] = value
+		case value != nil:
+			other[key] = value
+		}
+	}
+	return known, other
+}
+
+// rateLimitRows shapes rate limits like the decoded body, so they render
+// with the same labels and reset times. Counts are strings so that
+// "remaining" isn't taken for a duration.
+func rateLimitRows(limits []models.RateLimit) map[string]interface{} {
+	rows := make(map[string]interface{}, len(limits))
+	for _, limit := range limits {
+		fields := make(map[string]interface{}, len(limit.Other)+3)
+		for field, value := range limit.Other {
+			fields[field] = value
+		}
+		if limit.Limit != nil {
+			fields["limit"] = strconv.FormatInt(*limit.Limit, 10)
+		}
+		if limit.Remaining != nil {
+			fields["remaining"] = strconv.FormatInt(*limit.Remaining, 10)
+		}
+		if limit.Reset != nil {
+			fields["resets_at"] = limit.Reset.Format(time.RFC3339)
+		}
+		rows[limit.Name] = fields
+	}
+	return rows
+}
+
+// Deltas returns the change in every numeric field between prev and curr,
+// keyed by the underscore-joined field path. Fields that are unchanged or
+// absent from either snapshot are omitted.
+func Deltas(prev, curr *models.Usage) map[string]float64 {
+	if prev == nil || curr == nil {
+		return nil
+	}
+
+	var prevData, currData map[string]interface{}
+	if json.Unmarshal(prev.Raw, &prevData) != nil || json.Unmarshal(curr.Raw, &currData) != nil {
 		return nil
 	}
 
-	fmt.Println()
-	fmt.Printf("%s%sClaude.ai Usage%s\n", colors.Bold, colors.Cyan, colors.Reset)
-	fmt.Println(strings.Repeat("═", 50))
+	before := make(map[string]float64)
+	flattenNumbers(prevData, "", before)
+	after := make(map[string]float64)
+	flattenNumbers(currData, "", after)
+
+	deltas := make(map[string]float64)
+	for path, v := range after {
+		if old, ok := before[path]; ok && v != old {
+			deltas[path] = v - old
+		}
+	}
+	return deltas
+}
+
+// flattenNumbers collects numeric leaf values into out keyed by path
+func flattenNumbers(data map[string]interface{}, prefix string, out map[string]float64) {
+	for key, value := range data {
+		path := joinPath(prefix, key)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenNumbers(v, path, out)
+		case float64:
+			out[path] = v
+		}
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+// FormatDelta renders a change indicator such as "▲ +3" or "▼ -1.50"
+func FormatDelta(d float64, colors Colors) string {
+	var numStr string
+	if d == float64(int64(d)) {
+		numStr = fmt.Sprintf("%+d", int64(d))
+	} else {
+		numStr = fmt.Sprintf("%+.2f", d)
+	}
+
+	if d > 0 {
+		return fmt.Sprintf("%s▲ %s%s", colors.Red, numStr, colors.Reset)
+	}
+	return fmt.Sprintf("%s▼ %s%s", colors.Green, numStr, colors.Reset)
+}
+
+// tableWriter renders usage data as indented key/value rows. notes are
+// appended to numeric values, keyed by underscore-joined field path.
+type tableWriter struct {
+	w       io.Writer
+	notes   map[string]string
+	colors  Colors
+	formats Formats
+	// width is the terminal width rows are truncated to, or 0
+	width int
+	// labels is the widest label at each indentation, so the values of
+	// sibling rows line up however long their keys are
+	labels map[int]int
+}
+
+// measure records the widest label at each indentation
+func (t *tableWriter) measure(data map[string]interface{}, indent int) {
+	for key, value := range data {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			t.measure(v, indent+2)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					t.measure(m, indent+4)
+				}
+			}
+		case nil:
+		case string:
+			if v == "" {
+				continue
+			}
+			t.measureLabel(key, indent)
+		default:
+			t.measureLabel(key, indent)
+		}
+	}
+}
+
+func (t *tableWriter) measureLabel(key string, indent int) {
+	if w := visibleWidth(t.formats.Locale.Label(key) + ":"); w > t.labels[indent] {
+		t.labels[indent] = w
+	}
+}
 
-	printDataRecursive(data, "", colors, formats)
+// labelWidth returns the label column width at an indentation. On a narrow
+// terminal labels get at most half the room, so values stay readable.
+func (t *tableWriter) labelWidth(indent int) int {
+	width := t.labels[indent]
+	if width < minLabelWidth {
+		width = minLabelWidth
+	}
+	if t.width > 0 {
+		if limit := (t.width - indent) / 2; width > limit {
+			width = limit
+		}
+	}
+	return width
+}
 
-	fmt.Println()
-	return nil
+// line writes one row, truncated to the terminal width
+func (t *tableWriter) line(s string) {
+	if t.width > 0 {
+		s = truncate(s, t.width)
+	}
+	fmt.Fprintln(t.w, s)
 }
 
-func printDataRecursive(data map[string]interface{}, indent string, colors Colors, formats Formats) {
+// row writes a label and its value, with the label padded to its column
+func (t *tableWriter) row(indent string, label, value string) {
+	width := t.labelWidth(len(indent))
+	t.line(indent + padRight(truncate(label+":", width), width) + " " + value)
+}
+
+func (t *tableWriter) rows(data map[string]interface{}, indent, prefix string) {
 	// Sort keys for deterministic output
 	keys := make([]string, 0, len(data))
 	for k := range data {
@@ -108,39 +394,44 @@ func printDataRecursive(data map[string]interface{}, indent string, colors Color
 	}
 	sort.Strings(keys)
 
+	colors := t.colors
 	for _, key := range keys {
 		value := data[key]
-		displayKey := FormatKey(key)
+		displayKey := t.formats.Locale.Label(key)
+		path := joinPath(prefix, key)
 
 		switch v := value.(type) {
 		case map[string]interface{}:
-			fmt.Printf("%s%s%s:%s\n", indent, colors.Bold, displayKey, colors.Reset)
-			printDataRecursive(v, indent+"  ", colors, formats)
+			t.line(fmt.Sprintf("%s%s%s:%s", indent, colors.Bold, displayKey, colors.Reset))
+			t.rows(v, indent+"  ", path)
 		case []interface{}:
-			fmt.Printf("%s%s%s:%s\n", indent, colors.Bold, displayKey, colors.Reset)
+			t.line(fmt.Sprintf("%s%s%s:%s", indent, colors.Bold, displayKey, colors.Reset))
 			for i, item := range v {
 				if m, ok := item.(map[string]interface{}); ok {
-					fmt.Printf("%s  %s[%d]%s\n", indent, colors.Cyan, i+1, colors.Reset)
-					printDataRecursive(m, indent+"    ", colors, formats)
+					t.line(fmt.Sprintf("%s  %s[%d]%s", indent, colors.Cyan, i+1, colors.Reset))
+					t.rows(m, indent+"    ", fmt.Sprintf("%s_%d", path, i+1))
 				} else {
-					fmt.Printf("%s  • %v\n", indent, item)
+					t.line(fmt.Sprintf("%s  • %v", indent, item))
 				}
 			}
 		case float64:
-			valueStr := FormatNumber(v, key, colors)
-			fmt.Printf("%s%-22s %s\n", indent, displayKey+":", valueStr)
+			valueStr := formatNumber(v, key, prefix, colors, t.formats.Locale)
+			if unit, ok := durationUnit(key); ok && !t.formats.RawDurations {
+				valueStr = humanDuration(time.Duration(v * float64(unit)))
+			}
+			if note, ok := t.notes[path]; ok {
+				valueStr += "  " + note
+			}
+			t.row(indent, displayKey, valueStr)
 		case string:
 			if v == "" {
 				continue // Skip empty strings
 			}
-			formatted := FormatStringWithFormats(v, key, formats)
-			fmt.Printf("%s%-22s %s\n", indent, displayKey+":", formatted)
-		case bool:
-			fmt.Printf("%s%-22s %t\n", indent, displayKey+":", v)
+			t.row(indent, displayKey, FormatStringWithFormats(v, key, t.formats))
 		case nil:
 			// Skip nil values
 		default:
-			fmt.Printf("%s%-22s %v\n", indent, displayKey+":", v)
+			t.row(indent, displayKey, fmt.Sprintf("%v", v))
 		}
 	}
 }
@@ -158,6 +449,12 @@ func FormatKey(key string) string {
 
 // FormatNumber formats a numeric value with optional colorization for utilization fields
 func FormatNumber(v float64, key string, colors Colors) string {
+	return formatNumber(v, key, "", colors, Locale{})
+}
+
+// formatNumber is FormatNumber using the thresholds of a window and the
+// decimal separator of a locale
+func formatNumber(v float64, key, window string, colors Colors, locale Locale) string {
 	keyLower := strings.ToLower(key)
 	isUtilization := strings.Contains(keyLower, "utilization") ||
 		strings.Contains(keyLower, "percent") ||
@@ -168,11 +465,11 @@ func FormatNumber(v float64, key string, colors Colors) string {
 	if v == float64(int64(v)) {
 		numStr = fmt.Sprintf("%d", int64(v))
 	} else {
-		numStr = fmt.Sprintf("%.2f", v)
+		numStr = locale.number(fmt.Sprintf("%.2f", v))
 	}
 
 	if isUtilization && colors.Reset != "" {
-		color := GetUtilizationColor(v, colors)
+		color := colors.UtilizationColor(window, v)
 		return fmt.Sprintf("%s%s%s", color, numStr, colors.Reset)
 	}
 
@@ -181,14 +478,7 @@ func FormatNumber(v float64, key string, colors Colors) string {
 
 // GetUtilizationColor returns the appropriate color based on utilization percentage
 func GetUtilizationColor(value float64, colors Colors) string {
-	switch {
-	case value >= 95:
-		return colors.Red
-	case value >= 80:
-		return colors.Yellow
-	default:
-		return colors.Green
-	}
+	return colors.UtilizationColor("", value)
 }
 
 // FormatString formats a string value, converting ISO datetimes to local format.
@@ -197,7 +487,9 @@ func FormatString(v, key string) string {
 	return FormatStringWithFormats(v, key, DefaultFormats())
 }
 
-// FormatStringWithFormats formats a string value using the provided format settings.
+// FormatStringWithFormats formats a string value using the provided format
+// settings. With fmts.Relative, reset times render as countdowns, and
+// fmts.Locale translates month and day names.
 func FormatStringWithFormats(v, key string, fmts Formats) string {
 	if !isDatetimeField(key) {
 		return v
@@ -213,17 +505,47 @@ func FormatStringWithFormats(v, key string, fmts Formats) string {
 
 	for _, inputFmt := range inputFormats {
 		if t, err := time.Parse(inputFmt, v); err == nil {
+			if fmts.Relative && isResetField(key) {
+				return fmts.Locale.Relative(t)
+			}
 			local := t.Local()
 			if inputFmt == "2006-01-02" {
-				return local.Format(fmts.Date)
+				return fmts.Locale.FormatTime(local, fmts.Date)
 			}
-			return local.Format(fmts.Datetime)
+			return fmts.Locale.FormatTime(local, fmts.Datetime)
 		}
 	}
 
 	return v
 }
 
+// durationUnit returns the unit of a field holding a duration, judged by its
+// name: "_seconds" and "remaining" fields count seconds, "_minutes" minutes.
+// Percentages such as "percent_remaining" aren't durations.
+func durationUnit(key string) (time.Duration, bool) {
+	keyLower := strings.ToLower(key)
+	switch {
+	case strings.Contains(keyLower, "percent"), strings.Contains(keyLower, "utilization"):
+		return 0, false
+	case strings.HasSuffix(keyLower, "_seconds"), strings.HasSuffix(keyLower, "remaining"):
+		return time.Second, true
+	case strings.HasSuffix(keyLower, "_minutes"):
+		return time.Minute, true
+	}
+	return 0, false
+}
+
+// humanDuration formats d like Duration, with seconds when under a minute
+func humanDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + humanDuration(-d)
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Round(time.Second)/time.Second))
+	}
+	return Duration(d)
+}
+
 // isDatetimeField returns true if the field name suggests it contains a datetime
 func isDatetimeField(key string) bool {
 	keyLower := strings.ToLower(key)
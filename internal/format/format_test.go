@@ -1,7 +1,11 @@
 package format
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
 )
 
 func TestFormatKey(t *testing.T) {
@@ -154,3 +158,123 @@ func TestNewColors(t *testing.T) {
 		t.Error("NewColors(true) should return empty colors")
 	}
 }
+
+func TestDeltas(t *testing.T) {
+	prev := &models.Usage{Raw: []byte(`{"five_hour":{"utilization":40,"resets_at":"x"},"seven_day":{"utilization":10}}`)}
+	curr := &models.Usage{Raw: []byte(`{"five_hour":{"utilization":45.5,"resets_at":"y"},"seven_day":{"utilization":10}}`)}
+
+	deltas := Deltas(prev, curr)
+	if len(deltas) != 1 {
+		t.Fatalf("Deltas returned %d entries, want 1: %v", len(deltas), deltas)
+	}
+	if d := deltas["five_hour_utilization"]; d != 5.5 {
+		t.Errorf("five_hour_utilization delta = %v, want 5.5", d)
+	}
+
+	if Deltas(nil, curr) != nil {
+		t.Error("Deltas with nil prev should return nil")
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	colors := Colors{}
+	tests := []struct {
+		delta    float64
+		expected string
+	}{
+		{3, "▲ +3"},
+		{-2, "▼ -2"},
+		{1.5, "▲ +1.50"},
+	}
+
+	for _, tt := range tests {
+		result := FormatDelta(tt.delta, colors)
+		if result != tt.expected {
+			t.Errorf("FormatDelta(%v) = %q, want %q", tt.delta, result, tt.expected)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	usage := &models.Usage{Raw: []byte(`{"five_hour":{"utilization":45,"resets_at":null},"plan":"max"}`)}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, usage, Colors{}, DefaultFormats(), TableOptions{}); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+
+	// Fields claude-limits doesn't know are set apart
+	want := "\nClaude.ai Usage\n" + strings.Repeat("═", 50) + "\n" +
+		"Five Hour:\n" +
+		"  Utilization:           45\n" +
+		"\nOther:\n" +
+		"  Plan:                  max\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTable output:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteTableKnownFieldsOnly(t *testing.T) {
+	usage := &models.Usage{Raw: []byte(`{"five_hour":{"utilization":45},"extra_usage":{"is_enabled":true},"future":null}`)}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, usage, Colors{}, DefaultFormats(), TableOptions{}); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	if got := buf.String(); strings.Contains(got, "Other:") || !strings.Contains(got, "Extra Usage:") {
+		t.Errorf("WriteTable output:\n%s\nwant extra_usage with the windows and no Other section", got)
+	}
+}
+
+func TestWriteTableRateLimits(t *testing.T) {
+	limit, remaining := int64(50), int64(49)
+	usage := &models.Usage{
+		Raw: []byte(`{"plan":"max"}`),
+		RateLimits: []models.RateLimit{
+			{Name: "requests", Limit: &limit, Remaining: &remaining},
+			{Name: "unified", Other: map[string]string{"status": "allowed"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, usage, Colors{}, DefaultFormats(), TableOptions{}); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+
+	want := "Plan:                  max\n\n" +
+		"Rate Limits:\n" +
+		"  Requests:\n" +
+		"    Limit:                 50\n" +
+		"    Remaining:             49\n" +
+		"  Unified:\n" +
+		"    Status:                allowed\n\n"
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("WriteTable output:\n%q\nwant suffix:\n%q", got, want)
+	}
+}
+
+func TestWriteTableDurations(t *testing.T) {
+	usage := &models.Usage{Raw: []byte(`{"resets_in_seconds":8040,"window_minutes":300,"remaining":42,"percent_remaining":55}`)}
+
+	tests := []struct {
+		raw  bool
+		want []string
+	}{
+		{false, []string{"Resets In Seconds:     2h 14m\n", "Window Minutes:        5h 0m\n", "Remaining:             42s\n", "Percent Remaining:     55\n"}},
+		{true, []string{"Resets In Seconds:     8040\n", "Window Minutes:        300\n", "Remaining:             42\n"}},
+	}
+
+	for _, tt := range tests {
+		formats := DefaultFormats()
+		formats.RawDurations = tt.raw
+		var buf bytes.Buffer
+		if err := WriteTable(&buf, usage, Colors{}, formats, TableOptions{}); err != nil {
+			t.Fatalf("WriteTable: %v", err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("raw=%v: table missing %q:\n%s", tt.raw, want, buf.String())
+			}
+		}
+	}
+}
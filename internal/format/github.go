@@ -0,0 +1,38 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// GitHubOutputs renders usage as GitHub Actions step outputs, a "name=value"
+// line each: <window>_utilization, <window>_remaining, and
+// <window>_resets_at (RFC 3339, empty when unknown) for every window, then
+// max_utilization and most_constrained, the window with the highest
+// utilization
+func GitHubOutputs(usage *models.Usage) string {
+	var b strings.Builder
+	for _, w := range usage.Windows() {
+		resets := ""
+		if w.ResetsAt != nil {
+			resets = w.ResetsAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(&b, "%s_utilization=%s\n", w.Name, outputNumber(w.Utilization))
+		fmt.Fprintf(&b, "%s_remaining=%s\n", w.Name, outputNumber(w.Remaining()))
+		fmt.Fprintf(&b, "%s_resets_at=%s\n", w.Name, resets)
+	}
+	if most, ok := usage.MostConstrainedWindow(); ok {
+		fmt.Fprintf(&b, "max_utilization=%s\n", outputNumber(most.Utilization))
+		fmt.Fprintf(&b, "most_constrained=%s\n", most.Name)
+	}
+	return b.String()
+}
+
+// outputNumber formats a percentage without trailing zeros
+func outputNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,33 @@
+package format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestGitHubOutputs(t *testing.T) {
+	var usage models.Usage
+	raw := `{"five_hour": {"utilization": 62.5, "resets_at": "2030-01-01T14:14:00+02:00"},
+		"seven_day": {"utilization": 34, "resets_at": null}}`
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "five_hour_utilization=62.5\n" +
+		"five_hour_remaining=37.5\n" +
+		"five_hour_resets_at=2030-01-01T12:14:00Z\n" +
+		"seven_day_utilization=34\n" +
+		"seven_day_remaining=66\n" +
+		"seven_day_resets_at=\n" +
+		"max_utilization=62.5\n" +
+		"most_constrained=five_hour\n"
+	if got := GitHubOutputs(&usage); got != want {
+		t.Errorf("GitHubOutputs =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := GitHubOutputs(&models.Usage{}); got != "" {
+		t.Errorf("GitHubOutputs(empty) = %q, want empty", got)
+	}
+}
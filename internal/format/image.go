@@ -0,0 +1,93 @@
+package format
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/raster"
+)
+
+// imagePalette colors the image like a dark terminal, keeping the
+// threshold colors that screenshots of one lose once recompressed
+var imagePalette = struct {
+	background, text, dim, empty color.RGBA
+	levels                       map[string]color.RGBA
+}{
+	background: color.RGBA{0x1e, 0x1e, 0x2e, 0xff},
+	text:       color.RGBA{0xe6, 0xe6, 0xe6, 0xff},
+	dim:        color.RGBA{0x8b, 0x94, 0x9e, 0xff},
+	empty:      color.RGBA{0x3a, 0x3f, 0x4b, 0xff},
+	levels: map[string]color.RGBA{
+		LevelOK:       {0x3f, 0xb9, 0x50, 0xff},
+		LevelWarning:  {0xd2, 0x99, 0x22, 0xff},
+		LevelCritical: {0xf8, 0x51, 0x49, 0xff},
+	},
+}
+
+// Image layout, in pixels before scaling
+const (
+	imagePadding  = 10
+	imageRow      = 14
+	imageGap      = 10
+	imageBarWidth = 100
+)
+
+// WriteImage renders usage as a PNG for posting in chats: a row per window
+// with its utilization, a bar colored by threshold level, and reset time.
+// Each pixel of the built-in font is scale pixels square. Only ASCII
+// renders; other characters, as in some locales' dates, show as '?'.
+func WriteImage(w io.Writer, usage *models.Usage, thresholds Colors, formats Formats, scale int) error {
+	windows := usage.Windows()
+	if len(windows) == 0 {
+		return fmt.Errorf("usage has no rate-limit windows to render")
+	}
+
+	type row struct {
+		name, used, resets string
+		utilization        float64
+		level              string
+	}
+	title := "Claude.ai Usage"
+	rows := make([]row, len(windows))
+	nameWidth, resetsWidth := 0, 0
+	for i, w := range windows {
+		r := row{
+			name:        FormatKey(w.Name),
+			used:        fmt.Sprintf("%3.0f%%", w.Utilization),
+			utilization: w.Utilization,
+			level:       thresholds.ThresholdsFor(w.Name, DefaultThresholds).Level(w.Utilization),
+		}
+		if w.ResetsAt != nil {
+			r.resets = "resets " + ResetTime(*w.ResetsAt, formats.Datetime, formats)
+		}
+		nameWidth = max(nameWidth, raster.TextWidth(r.name))
+		resetsWidth = max(resetsWidth, raster.TextWidth(r.resets))
+		rows[i] = r
+	}
+
+	usedX := imagePadding + nameWidth + imageGap
+	barX := usedX + raster.TextWidth("100%") + imageGap
+	resetsX := barX + imageBarWidth + imageGap
+	width := max(resetsX+resetsWidth, imagePadding+raster.TextWidth(title)) + imagePadding
+	// The title takes two rows, the second for the rule under it
+	height := 2*imagePadding + (len(rows)+2)*imageRow - (imageRow - raster.GlyphHeight)
+
+	c := raster.New(width, height, scale)
+	c.Fill(0, 0, width, height, imagePalette.background)
+	c.Text(imagePadding, imagePadding, title, imagePalette.text)
+	c.Fill(imagePadding, imagePadding+imageRow, width-2*imagePadding, 1, imagePalette.dim)
+
+	for i, r := range rows {
+		y := imagePadding + (i+2)*imageRow
+		level := imagePalette.levels[r.level]
+		c.Text(imagePadding, y, r.name, imagePalette.text)
+		c.Text(usedX, y, r.used, level)
+		c.Fill(barX, y, imageBarWidth, raster.GlyphHeight, imagePalette.empty)
+		filled := int(min(max(r.utilization, 0), 100) / 100 * imageBarWidth)
+		c.Fill(barX, y, filled, raster.GlyphHeight, level)
+		c.Text(resetsX, y, r.resets, imagePalette.dim)
+	}
+	return c.WritePNG(w)
+}
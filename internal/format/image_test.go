@@ -0,0 +1,44 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/raster"
+)
+
+func TestWriteImage(t *testing.T) {
+	var usage models.Usage
+	raw := `{"five_hour": {"utilization": 45, "resets_at": "2030-01-01T14:30:00Z"}, "seven_day": {"utilization": 97}}`
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteImage(&buf, &usage, Colors{}, DefaultFormats(), 2); err != nil {
+		t.Fatalf("WriteImage failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("PNG doesn't decode: %v", err)
+	}
+
+	// Each window's bar starts in its threshold color
+	nameWidth := raster.TextWidth("Seven Day")
+	barX := imagePadding + nameWidth + imageGap + raster.TextWidth("100%") + imageGap
+	for i, want := range []string{LevelOK, LevelCritical} {
+		y := imagePadding + (i+2)*imageRow
+		got := color.RGBAModel.Convert(img.At(2*barX+1, 2*y+1))
+		if got != imagePalette.levels[want] {
+			t.Errorf("row %d bar = %v, want %s color", i, got, want)
+		}
+	}
+
+	if err := WriteImage(&buf, &models.Usage{}, Colors{}, DefaultFormats(), 1); err == nil {
+		t.Error("expected error for usage without windows")
+	}
+}
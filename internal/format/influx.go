@@ -0,0 +1,51 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// InfluxMeasurement is the measurement name of line-protocol output
+const InfluxMeasurement = "claude_limits"
+
+// influxTagEscaper escapes tag keys and values for line protocol
+var influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// Influx renders usage as InfluxDB line protocol, one line per window, e.g.
+//
+//	claude_limits,window=five_hour utilization=45,resets_in_seconds=3600i 1736951400000000000
+//
+// tags are added to every line. resets_in_seconds is omitted for windows
+// without a reset time. Lines are stamped with now in nanoseconds.
+func Influx(usage *models.Usage, now time.Time, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var extra strings.Builder
+	for _, k := range keys {
+		if tags[k] == "" {
+			continue
+		}
+		fmt.Fprintf(&extra, ",%s=%s", influxTagEscaper.Replace(k), influxTagEscaper.Replace(tags[k]))
+	}
+
+	var b strings.Builder
+	for _, w := range usage.Windows() {
+		fmt.Fprintf(&b, "%s,window=%s%s utilization=%s",
+			InfluxMeasurement, influxTagEscaper.Replace(w.Name), extra.String(),
+			strconv.FormatFloat(w.Utilization, 'f', -1, 64))
+		if w.ResetsAt != nil {
+			fmt.Fprintf(&b, ",resets_in_seconds=%di", int64(w.ResetsIn(now).Seconds()))
+		}
+		fmt.Fprintf(&b, " %d\n", now.UnixNano())
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,29 @@
+package format
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestInflux(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	data := `{"five_hour": {"utilization": 45.5, "resets_at": "2030-01-01T13:00:00Z"},
+		"seven_day": {"utilization": 23, "resets_at": "2029-12-31T00:00:00Z"},
+		"seven_day_opus": {"utilization": 10, "resets_at": null}}`
+
+	var usage models.Usage
+	if err := json.Unmarshal([]byte(data), &usage); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := Influx(&usage, now, map[string]string{"profile": "my work", "empty": ""})
+	want := `claude_limits,window=five_hour,profile=my\ work utilization=45.5,resets_in_seconds=3600i 1893499200000000000
+claude_limits,window=seven_day,profile=my\ work utilization=23,resets_in_seconds=0i 1893499200000000000
+claude_limits,window=seven_day_opus,profile=my\ work utilization=10 1893499200000000000`
+	if got != want {
+		t.Errorf("Influx() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package format
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table layout limits
+const (
+	// minLabelWidth is the narrowest label column, so short keys line up
+	// as they always have
+	minLabelWidth = 22
+	// ruleWidth is the width of the rule under the table title
+	ruleWidth = 50
+	// ellipsis marks truncated text
+	ellipsis = "…"
+)
+
+// TerminalWidth returns the width of the terminal on stdout in columns, or 0
+// if stdout isn't a terminal. COLUMNS is used when the size can't be read.
+func TerminalWidth() int {
+	if !isTerminal(os.Stdout) {
+		return 0
+	}
+	if w := terminalWidth(os.Stdout); w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+// visibleWidth returns how many columns s occupies, skipping ANSI escape
+// sequences
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if end := escapeEnd(s, i); end > i {
+			i = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
+
+// truncate shortens s to at most width columns, ending it with an ellipsis.
+// Escape sequences are kept, and colors are reset after a cut so they don't
+// leak into the next line.
+func truncate(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	n := 0
+	escaped := false
+	for i := 0; i < len(s); {
+		if end := escapeEnd(s, i); end > i {
+			b.WriteString(s[i:end])
+			escaped = true
+			i = end
+			continue
+		}
+		if n == width-1 {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+size])
+		i += size
+		n++
+	}
+	b.WriteString(ellipsis)
+	if escaped {
+		b.WriteString(Reset)
+	}
+	return b.String()
+}
+
+// padRight pads s with spaces to width columns
+func padRight(s string, width int) string {
+	if pad := width - visibleWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// escapeEnd returns the end of the ANSI CSI sequence starting at s[i], or i
+// if none starts there
+func escapeEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i] != '\033' || s[i+1] != '[' {
+		return i
+	}
+	for j := i + 2; j < len(s); j++ {
+		if c := s[j]; c >= 0x40 && c <= 0x7e {
+			return j + 1
+		}
+	}
+	return i
+}
@@ -0,0 +1,84 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{Green + "45" + Reset, 2},
+		{"\033[38;2;255;136;0m▲ +3\033[0m", 4},
+		{"Zurücksetzen:", 13},
+	}
+	for _, tt := range tests {
+		if got := visibleWidth(tt.input); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"too long", 5, "too …"},
+		{"ünïcödé", 4, "ünï…"},
+		{Green + "coloured" + Reset, 4, Green + "col…" + Reset},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.input, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTableLongKeys(t *testing.T) {
+	usage := &models.Usage{Raw: []byte(`{"a":1,"a_very_long_field_name_indeed":2}`)}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, usage, Colors{}, DefaultFormats(), TableOptions{}); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+
+	// Values line up one space after the longest label
+	for _, want := range []string{
+		"A:" + strings.Repeat(" ", 29) + "1\n",
+		"A Very Long Field Name Indeed: 2\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("table missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteTableWidth(t *testing.T) {
+	usage := &models.Usage{Raw: []byte(`{"five_hour":{"utilization":45,"note":"a value far too long for a narrow terminal"}}`)}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, usage, NewANSIColors(), DefaultFormats(), TableOptions{Width: 30}); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if w := visibleWidth(line); w > 30 {
+			t.Errorf("line %q is %d columns wide, want at most 30", line, w)
+		}
+	}
+	if !strings.Contains(buf.String(), "a value far …") {
+		t.Errorf("long value not ellipsized:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,282 @@
+package format
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale holds the translations and conventions for non-English output. The
+// zero value is English.
+type Locale struct {
+	Name string
+
+	Months      [12]string // January to December
+	ShortMonths [12]string
+	Days        [7]string // Sunday to Saturday
+	ShortDays   [7]string
+
+	// Decimal is the decimal separator, e.g. ","
+	Decimal string
+	// Clock24 is true where times are usually written with a 24-hour clock
+	Clock24 bool
+
+	// Title heads table output
+	Title string
+	// Labels translate field names, keyed by the API's snake_case key
+	Labels map[string]string
+
+	// In, Ago, and Now phrase relative times; In and Ago take a duration
+	In, Ago, Now string
+}
+
+// Locales are the supported locales, keyed by language code
+var Locales = map[string]Locale{
+	"de": {
+		Name:        "de",
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		Decimal:     ",",
+		Clock24:     true,
+		Title:       "Claude.ai-Nutzung",
+		Labels: map[string]string{
+			"five_hour":            "5 Stunden",
+			"seven_day":            "7 Tage",
+			"seven_day_opus":       "7 Tage Opus",
+			"seven_day_sonnet":     "7 Tage Sonnet",
+			"seven_day_oauth_apps": "7 Tage OAuth-Apps",
+			"utilization":          "Auslastung",
+			"resets_at":            "Zurückgesetzt am",
+			"extra_usage":          "Zusatznutzung",
+			"is_enabled":           "Aktiviert",
+			"monthly_limit":        "Monatslimit",
+			"used_credits":         "Verbrauchte Credits",
+			"other":                "Sonstiges",
+		},
+		In:  "in %s",
+		Ago: "vor %s",
+		Now: "jetzt",
+	},
+	"es": {
+		Name:        "es",
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Decimal:     ",",
+		Clock24:     true,
+		Title:       "Uso de Claude.ai",
+		Labels: map[string]string{
+			"five_hour":            "5 horas",
+			"seven_day":            "7 días",
+			"seven_day_opus":       "7 días Opus",
+			"seven_day_sonnet":     "7 días Sonnet",
+			"seven_day_oauth_apps": "7 días apps OAuth",
+			"utilization":          "Uso",
+			"resets_at":            "Se restablece",
+			"extra_usage":          "Uso adicional",
+			"is_enabled":           "Activado",
+			"monthly_limit":        "Límite mensual",
+			"used_credits":         "Créditos usados",
+			"other":                "Otros",
+		},
+		In:  "en %s",
+		Ago: "hace %s",
+		Now: "ahora",
+	},
+	"fr": {
+		Name:        "fr",
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Decimal:     ",",
+		Clock24:     true,
+		Title:       "Utilisation de Claude.ai",
+		Labels: map[string]string{
+			"five_hour":            "5 heures",
+			"seven_day":            "7 jours",
+			"seven_day_opus":       "7 jours Opus",
+			"seven_day_sonnet":     "7 jours Sonnet",
+			"seven_day_oauth_apps": "7 jours apps OAuth",
+			"utilization":          "Utilisation",
+			"resets_at":            "Réinitialisation",
+			"extra_usage":          "Utilisation supplémentaire",
+			"is_enabled":           "Activé",
+			"monthly_limit":        "Limite mensuelle",
+			"used_credits":         "Crédits utilisés",
+			"other":                "Autres",
+		},
+		In:  "dans %s",
+		Ago: "il y a %s",
+		Now: "maintenant",
+	},
+	"it": {
+		Name:        "it",
+		Months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		Decimal:     ",",
+		Clock24:     true,
+		Title:       "Utilizzo di Claude.ai",
+		Labels: map[string]string{
+			"five_hour":            "5 ore",
+			"seven_day":            "7 giorni",
+			"seven_day_opus":       "7 giorni Opus",
+			"seven_day_sonnet":     "7 giorni Sonnet",
+			"seven_day_oauth_apps": "7 giorni app OAuth",
+			"utilization":          "Utilizzo",
+			"resets_at":            "Si azzera",
+			"extra_usage":          "Utilizzo extra",
+			"is_enabled":           "Attivo",
+			"monthly_limit":        "Limite mensile",
+			"used_credits":         "Crediti usati",
+			"other":                "Altro",
+		},
+		In:  "tra %s",
+		Ago: "%s fa",
+		Now: "ora",
+	},
+	"pt": {
+		Name:        "pt",
+		Months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		ShortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		Days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		ShortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		Decimal:     ",",
+		Clock24:     true,
+		Title:       "Uso do Claude.ai",
+		Labels: map[string]string{
+			"five_hour":            "5 horas",
+			"seven_day":            "7 dias",
+			"seven_day_opus":       "7 dias Opus",
+			"seven_day_sonnet":     "7 dias Sonnet",
+			"seven_day_oauth_apps": "7 dias apps OAuth",
+			"utilization":          "Uso",
+			"resets_at":            "Reinicia em",
+			"extra_usage":          "Uso extra",
+			"is_enabled":           "Ativado",
+			"monthly_limit":        "Limite mensal",
+			"used_credits":         "Créditos usados",
+			"other":                "Outros",
+		},
+		In:  "em %s",
+		Ago: "há %s",
+		Now: "agora",
+	},
+}
+
+// LocaleNames returns the supported locale names in sorted order, including "en"
+func LocaleNames() []string {
+	names := []string{"en"}
+	for name := range Locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupLocale finds a locale by name. Region and encoding suffixes are
+// ignored ("de_DE.UTF-8" is "de"), and "auto" reads LC_ALL, LC_MESSAGES, or
+// LANG. English, the empty name, and POSIX "C" return the zero Locale.
+func LookupLocale(name string) (Locale, bool) {
+	if strings.EqualFold(name, "auto") {
+		name = envLocale()
+	}
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "", "en", "c", "posix":
+		return Locale{}, true
+	}
+	l, ok := Locales[lang]
+	return l, ok
+}
+
+// envLocale returns the locale named by the environment, as setlocale would
+func envLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// englishNames matches the month and day names Go's time package writes
+var englishNames = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec|Sunday|Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sun|Mon|Tue|Wed|Thu|Fri|Sat)\b`)
+
+// FormatTime formats t with a Go time layout, translating month and day names
+func (l Locale) FormatTime(t time.Time, layout string) string {
+	s := t.Format(layout)
+	if l.Name == "" {
+		return s
+	}
+	return englishNames.ReplaceAllStringFunc(s, func(name string) string {
+		for m := time.January; m <= time.December; m++ {
+			switch name {
+			case m.String():
+				return l.Months[m-1]
+			case m.String()[:3]:
+				return l.ShortMonths[m-1]
+			}
+		}
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			switch name {
+			case d.String():
+				return l.Days[d]
+			case d.String()[:3]:
+				return l.ShortDays[d]
+			}
+		}
+		return name
+	})
+}
+
+// Label returns the display name for a field key, translated if known
+func (l Locale) Label(key string) string {
+	if label, ok := l.Labels[key]; ok {
+		return label
+	}
+	return FormatKey(key)
+}
+
+// TableTitle returns the heading of table output
+func (l Locale) TableTitle() string {
+	if l.Title == "" {
+		return "Claude.ai Usage"
+	}
+	return l.Title
+}
+
+// Relative formats t as a countdown like Relative, in the locale's language
+func (l Locale) Relative(t time.Time) string {
+	if l.Name == "" {
+		return Relative(t)
+	}
+	d := t.Sub(now()).Round(time.Minute)
+	switch {
+	case d == 0:
+		return l.Now
+	case d < 0:
+		return fmt.Sprintf(l.Ago, Duration(-d))
+	default:
+		return fmt.Sprintf(l.In, Duration(d))
+	}
+}
+
+// number swaps the decimal point in a formatted number for the locale's separator
+func (l Locale) number(s string) string {
+	if l.Decimal == "" {
+		return s
+	}
+	return strings.Replace(s, ".", l.Decimal, 1)
+}
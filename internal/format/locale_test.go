@@ -0,0 +1,116 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"", "", true},
+		{"en_US.UTF-8", "", true},
+		{"C", "", true},
+		{"de", "de", true},
+		{"fr_FR.UTF-8", "fr", true},
+		{"pt-BR", "pt", true},
+		{"xx", "", false},
+	}
+
+	for _, tt := range tests {
+		l, ok := LookupLocale(tt.name)
+		if ok != tt.wantOK || l.Name != tt.want {
+			t.Errorf("LookupLocale(%q) = %q, %v; want %q, %v", tt.name, l.Name, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLookupLocaleAuto(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+
+	if l, _ := LookupLocale("auto"); l.Name != "es" {
+		t.Errorf("LookupLocale(auto) = %q, want es", l.Name)
+	}
+}
+
+func TestLocaleFormatTime(t *testing.T) {
+	ts := time.Date(2030, 3, 5, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{"en", "Mon, 2 Jan 2006 15:04", "Tue, 5 Mar 2030 14:30"},
+		{"de", "Mon, 2 Jan 2006 15:04", "Di., 5 März 2030 14:30"},
+		{"fr", "Monday 2 January 2006", "mardi 5 mars 2030"},
+		{"es", "Mon 2 Jan", "mar 5 mar"},
+	}
+
+	for _, tt := range tests {
+		l, _ := LookupLocale(tt.locale)
+		if got := l.FormatTime(ts, tt.layout); got != tt.want {
+			t.Errorf("%s FormatTime = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleRelative(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	de := Locales["de"]
+	if got := de.Relative(base.Add(90 * time.Minute)); got != "in 1h 30m" {
+		t.Errorf("Relative = %q", got)
+	}
+	if got := Locales["fr"].Relative(base.Add(-5 * time.Minute)); got != "il y a 5m" {
+		t.Errorf("Relative = %q", got)
+	}
+}
+
+func TestLocaleLabels(t *testing.T) {
+	de := Locales["de"]
+	if got := de.Label("utilization"); got != "Auslastung" {
+		t.Errorf("Label(utilization) = %q", got)
+	}
+	// Unknown keys fall back to title case
+	if got := de.Label("new_field"); got != "New Field" {
+		t.Errorf("Label(new_field) = %q", got)
+	}
+	if got := (Locale{}).TableTitle(); got != "Claude.ai Usage" {
+		t.Errorf("TableTitle = %q", got)
+	}
+}
+
+func TestLocaleLabelsComplete(t *testing.T) {
+	// Every key the table renders, including the heading for unrecognized
+	// fields
+	keys := []string{
+		"five_hour", "seven_day", "seven_day_opus", "seven_day_sonnet", "seven_day_oauth_apps",
+		"utilization", "resets_at", "extra_usage", "is_enabled", "monthly_limit", "used_credits", "other",
+	}
+	for name, locale := range Locales {
+		for _, key := range keys {
+			if _, ok := locale.Labels[key]; !ok {
+				t.Errorf("locale %s has no label for %q", name, key)
+			}
+		}
+	}
+}
+
+func TestFormatNumberLocale(t *testing.T) {
+	got := formatNumber(12.5, "credits", "", Colors{}, Locales["de"])
+	if got != "12,50" {
+		t.Errorf("formatNumber = %q, want 12,50", got)
+	}
+	if got := formatNumber(12, "credits", "", Colors{}, Locales["de"]); strings.Contains(got, ",") {
+		t.Errorf("whole numbers have no decimals, got %q", got)
+	}
+}
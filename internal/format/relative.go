@@ -0,0 +1,80 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// now is the clock for relative times, replaced in tests
+var now = time.Now
+
+// Duration formats d to the nearest minute, e.g. "2d 3h", "5h 12m", "4m"
+func Duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// ParseDuration parses a duration like time.ParseDuration, plus a leading
+// count of days or weeks, e.g. "30d", "2w", or "1d12h"
+func ParseDuration(s string) (time.Duration, error) {
+	if i := strings.IndexAny(s, "dw"); i > 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := 24 * time.Hour
+		if s[i] == 'w' {
+			unit *= 7
+		}
+		d := time.Duration(n) * unit
+		if rest := s[i+1:]; rest != "" {
+			more, err := time.ParseDuration(rest)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			d += more
+		}
+		return d, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Relative formats t as a countdown, e.g. "in 2h 14m", or "5m ago" once past
+func Relative(t time.Time) string {
+	d := t.Sub(now()).Round(time.Minute)
+	switch {
+	case d == 0:
+		return "now"
+	case d < 0:
+		return Duration(-d) + " ago"
+	default:
+		return "in " + Duration(d)
+	}
+}
+
+// ResetTime renders a reset time with layout, or as a countdown when
+// formats.Relative is set
+func ResetTime(t time.Time, layout string, formats Formats) string {
+	if formats.Relative {
+		return formats.Locale.Relative(t)
+	}
+	return formats.Locale.FormatTime(t.Local(), layout)
+}
+
+// isResetField returns true if the field name holds a reset time
+func isResetField(key string) bool {
+	return strings.Contains(strings.ToLower(key), "reset")
+}
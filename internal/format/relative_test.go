@@ -0,0 +1,89 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{4 * time.Minute, "4m"},
+		{5*time.Hour + 12*time.Minute, "5h 12m"},
+		{51 * time.Hour, "2d 3h"},
+		{29 * time.Second, "0m"},
+	}
+
+	for _, tt := range tests {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRelative(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{base.Add(2*time.Hour + 14*time.Minute), "in 2h 14m"},
+		{base.Add(20 * time.Second), "now"},
+		{base.Add(-5 * time.Minute), "5m ago"},
+	}
+
+	for _, tt := range tests {
+		if got := Relative(tt.t); got != tt.want {
+			t.Errorf("Relative(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeFormats(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	resets := base.Add(90 * time.Minute).Format(time.RFC3339)
+	formats := Formats{Datetime: "15:04", Relative: true}
+
+	if got := FormatStringWithFormats(resets, "resets_at", formats); got != "in 1h 30m" {
+		t.Errorf("resets_at = %q, want countdown", got)
+	}
+	// Only reset times become countdowns
+	if got := FormatStringWithFormats(resets, "created_at", formats); got == "in 1h 30m" {
+		t.Errorf("created_at = %q, want absolute time", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"xd", 0, true},
+		{"1d12", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
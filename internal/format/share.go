@@ -0,0 +1,91 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// ShareStyles are the styles Share renders
+var ShareStyles = []string{"markdown", "text"}
+
+// shareBarWidth is the width of each window's bar in a share
+const shareBarWidth = 10
+
+// shareMarkers mark a window's threshold level in markdown, since shares
+// can't carry terminal colors. Text tables leave them out: emoji widths
+// vary between fonts and would misalign the columns.
+var shareMarkers = map[string]string{LevelOK: "🟢", LevelWarning: "🟡", LevelCritical: "🔴"}
+
+// Share renders usage to post in team chats or issues: each window's
+// utilization, a bar, and time to reset, in the "markdown" table or a
+// "text" table that screenshots well. Only the rate-limit windows are
+// included, so nothing identifying the account (organization IDs, emails,
+// tokens) is, and reset times are relative so they don't give away the
+// time zone. thresholds picks each window's marker.
+func Share(usage *models.Usage, style string, thresholds Colors) (string, error) {
+	title := "Claude usage, " + now().UTC().Format("2006-01-02 15:04 UTC")
+	header := []string{"Window", "Used", "", "Resets"}
+	// Each row is the header's cells plus the window's marker
+	var rows [][]string
+	for _, w := range usage.Windows() {
+		resets := "-"
+		if w.ResetsAt != nil {
+			resets = Relative(*w.ResetsAt)
+		}
+		rows = append(rows, []string{
+			FormatKey(w.Name),
+			fmt.Sprintf("%.0f%%", w.Utilization),
+			bar(w.Utilization, shareBarWidth),
+			resets,
+			shareMarkers[thresholds.ThresholdsFor(w.Name, DefaultThresholds).Level(w.Utilization)],
+		})
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("usage has no rate-limit windows to share")
+	}
+
+	var b strings.Builder
+	switch style {
+	case "markdown":
+		fmt.Fprintf(&b, "**%s**\n\n", title)
+		fmt.Fprintln(&b, "| "+strings.Join(header, " | ")+" |")
+		fmt.Fprintln(&b, "|:--|--:|:--|:--|")
+		for _, row := range rows {
+			row[2] = row[4] + " `" + row[2] + "`"
+			fmt.Fprintln(&b, "| "+strings.Join(row[:4], " | ")+" |")
+		}
+	case "text":
+		widths := make([]int, len(header))
+		for _, row := range append([][]string{header}, rows...) {
+			for i, cell := range row[:len(header)] {
+				widths[i] = max(widths[i], visibleWidth(cell))
+			}
+		}
+		rule := func(left, mid, right string) {
+			parts := make([]string, len(widths))
+			for i, width := range widths {
+				parts[i] = strings.Repeat("─", width+2)
+			}
+			fmt.Fprintln(&b, left+strings.Join(parts, mid)+right)
+		}
+		line := func(cells []string) {
+			for i, cell := range cells[:len(header)] {
+				fmt.Fprintf(&b, "│ %s ", padRight(cell, widths[i]))
+			}
+			fmt.Fprintln(&b, "│")
+		}
+		fmt.Fprintln(&b, title)
+		rule("┌", "┬", "┐")
+		line(header)
+		rule("├", "┼", "┤")
+		for _, row := range rows {
+			line(row)
+		}
+		rule("└", "┴", "┘")
+	default:
+		return "", fmt.Errorf("unknown share style %q (use %s)", style, strings.Join(ShareStyles, " or "))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
@@ -0,0 +1,65 @@
+package format
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestShare(t *testing.T) {
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	t.Cleanup(func() { now = time.Now })
+
+	var usage models.Usage
+	raw := `{"five_hour": {"utilization": 62, "resets_at": "2030-01-01T14:14:00Z"},
+		"seven_day": {"utilization": 97, "resets_at": null},
+		"organization": {"uuid": "org-1234", "email": "dev@example.com"}}`
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := Share(&usage, "markdown", Colors{})
+	if err != nil {
+		t.Fatalf("Share(markdown) failed: %v", err)
+	}
+	wantMD := "**Claude usage, 2030-01-01 12:00 UTC**\n\n" +
+		"| Window | Used |  | Resets |\n" +
+		"|:--|--:|:--|:--|\n" +
+		"| Five Hour | 62% | 🟢 `██████░░░░` | in 2h 14m |\n" +
+		"| Seven Day | 97% | 🔴 `█████████░` | - |"
+	if md != wantMD {
+		t.Errorf("Share(markdown) =\n%s\nwant\n%s", md, wantMD)
+	}
+
+	text, err := Share(&usage, "text", Colors{})
+	if err != nil {
+		t.Fatalf("Share(text) failed: %v", err)
+	}
+	wantText := "Claude usage, 2030-01-01 12:00 UTC\n" +
+		"┌───────────┬──────┬────────────┬───────────┐\n" +
+		"│ Window    │ Used │            │ Resets    │\n" +
+		"├───────────┼──────┼────────────┼───────────┤\n" +
+		"│ Five Hour │ 62%  │ ██████░░░░ │ in 2h 14m │\n" +
+		"│ Seven Day │ 97%  │ █████████░ │ -         │\n" +
+		"└───────────┴──────┴────────────┴───────────┘"
+	if text != wantText {
+		t.Errorf("Share(text) =\n%s\nwant\n%s", text, wantText)
+	}
+
+	for _, secret := range []string{"org-1234", "dev@example.com"} {
+		if strings.Contains(md+text, secret) {
+			t.Errorf("share leaks %q", secret)
+		}
+	}
+
+	if _, err := Share(&usage, "html", Colors{}); err == nil {
+		t.Error("expected error for unknown style")
+	}
+	if _, err := Share(&models.Usage{}, "markdown", Colors{}); err == nil {
+		t.Error("expected error for usage without windows")
+	}
+}
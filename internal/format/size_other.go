@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package format
+
+import "os"
+
+// terminalWidth returns 0 where the terminal size can't be read, leaving
+// COLUMNS as the only source
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,37 @@
+package format
+
+import "strings"
+
+// sparkBars are the sparkline levels, lowest to highest
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders utilization percentages (0-100) as a bar sparkline of at
+// most width characters, sampling evenly when there are more values than width.
+// Values are scaled absolutely, so a flat line at ▁ means low usage, not no change.
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+
+	if len(values) > width {
+		// Sample evenly, always ending on the latest value
+		sampled := make([]float64, width)
+		last := len(values) - 1
+		for i := range sampled {
+			idx := last
+			if width > 1 {
+				idx = last * i / (width - 1)
+			}
+			sampled[i] = values[idx]
+		}
+		values = sampled
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := int(v / 100 * float64(len(sparkBars)-1))
+		level = min(max(level, 0), len(sparkBars)-1)
+		b.WriteRune(sparkBars[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package format
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"empty", nil, 10, ""},
+		{"scale", []float64{0, 50, 100}, 10, "▁▄█"},
+		{"clamp", []float64{-5, 150}, 10, "▁█"},
+		{"sampled keeps ends", []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 100}, 3, "▁▃█"},
+		{"single width uses latest", []float64{0, 100}, 1, "█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
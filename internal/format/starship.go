@@ -0,0 +1,47 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// starshipIcons are the Nerd Font glyphs that replace window labels in
+// starship output with icons. Windows without one keep their label.
+var starshipIcons = map[string]string{
+	models.WindowFiveHour:     "\uf017",     // nf-fa-clock_o
+	models.WindowSevenDay:     "\uf073",     // nf-fa-calendar
+	models.WindowSevenDayOpus: "\U000f06a9", // nf-md-robot
+}
+
+// Starship renders usage for a starship custom module, e.g. "5h 62% wk 34%":
+// plain text without color codes, since starship styles the module itself.
+// Windows and separator come from opts as in Compact, with a space as the
+// default separator; reset times are never shown. With icons, Nerd Font
+// glyphs replace the window labels.
+func Starship(usage *models.Usage, opts CompactOptions, icons bool) string {
+	windows := opts.Windows
+	if len(windows) == 0 {
+		windows = DefaultCompactWindows
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = " "
+	}
+
+	parts := make([]string, 0, len(windows))
+	for _, name := range windows {
+		label := WindowLabel(name)
+		if icon, ok := starshipIcons[name]; ok && icons {
+			label = icon
+		}
+
+		value := compactUnknown
+		if w := usage.Window(name); w != nil {
+			value = fmt.Sprint(int64(w.Utilization))
+		}
+		parts = append(parts, label+" "+value+"%")
+	}
+	return strings.Join(parts, sep)
+}
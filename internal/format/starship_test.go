@@ -0,0 +1,31 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestStarship(t *testing.T) {
+	usage := compactUsage(t)
+
+	tests := []struct {
+		name  string
+		opts  CompactOptions
+		icons bool
+		want  string
+	}{
+		{"defaults", CompactOptions{}, false, "5h 62% wk 34%"},
+		{"icons", CompactOptions{}, true, "\uf017 62% \uf073 34%"},
+		{"separator", CompactOptions{Separator: " | "}, false, "5h 62% | wk 34%"},
+		{"missing window keeps its label", CompactOptions{Windows: []string{models.WindowSevenDaySonnet}}, true, "sonnet ?%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Starship(usage, tt.opts, tt.icons); got != tt.want {
+				t.Errorf("Starship() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package format
+
+import (
+	"os"
+	"strings"
+)
+
+// ColorLevel is how many colors a terminal can display
+type ColorLevel int
+
+// Color levels, from none to 24-bit
+const (
+	ColorNone ColorLevel = iota
+	Color16
+	Color256
+	ColorTrue
+)
+
+// DetectColorLevel reports the color support of the terminal behind f.
+//
+// NO_COLOR (any value) disables color. CLICOLOR_FORCE (any value but "0")
+// enables it even when f isn't a terminal, e.g. when piping into less -R.
+// Otherwise color needs a terminal that isn't TERM=dumb or CLICOLOR=0; on
+// Windows the console must also process ANSI escape sequences, which is
+// switched on where the console supports it. COLORTERM and
+// TERM then decide between 16, 256, and 24-bit color.
+func DetectColorLevel(f *os.File) ColorLevel {
+	if NoColorEnv() {
+		return ColorNone
+	}
+
+	if force := os.Getenv("CLICOLOR_FORCE"); force == "" || force == "0" {
+		if !isTerminal(f) || os.Getenv("TERM") == "dumb" || os.Getenv("CLICOLOR") == "0" {
+			return ColorNone
+		}
+		if !vtEnabled(f) {
+			return ColorNone
+		}
+	}
+
+	switch colorterm := strings.ToLower(os.Getenv("COLORTERM")); {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return ColorTrue
+	case os.Getenv("WT_SESSION") != "":
+		// Windows Terminal supports 24-bit color but doesn't set COLORTERM
+		return ColorTrue
+	case strings.Contains(os.Getenv("TERM"), "256color"):
+		return Color256
+	}
+	return Color16
+}
+
+// NoColorEnv reports whether NO_COLOR is set (to any value), which
+// disables color even for output that forces ANSI, such as status lines
+func NoColorEnv() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// isTerminal returns true if f is a terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
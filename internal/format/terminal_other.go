@@ -0,0 +1,11 @@
+//go:build !windows
+
+package format
+
+import "os"
+
+// vtEnabled reports whether the terminal behind f interprets ANSI escape
+// sequences, which every non-Windows terminal does
+func vtEnabled(f *os.File) bool {
+	return true
+}
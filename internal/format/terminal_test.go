@@ -0,0 +1,73 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectColorLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want ColorLevel
+	}{
+		{"not a terminal", nil, ColorNone},
+		{"forced", map[string]string{"CLICOLOR_FORCE": "1"}, Color16},
+		{"forced off", map[string]string{"CLICOLOR_FORCE": "0"}, ColorNone},
+		{"NO_COLOR wins", map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, ColorNone},
+		{"256 color", map[string]string{"CLICOLOR_FORCE": "1", "TERM": "xterm-256color"}, Color256},
+		{"truecolor", map[string]string{"CLICOLOR_FORCE": "1", "TERM": "xterm-256color", "COLORTERM": "truecolor"}, ColorTrue},
+		{"windows terminal", map[string]string{"CLICOLOR_FORCE": "1", "WT_SESSION": "abc"}, ColorTrue},
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "TERM", "COLORTERM", "WT_SESSION"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := DetectColorLevel(f); got != tt.want {
+				t.Errorf("DetectColorLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if NoColorEnv() {
+		t.Error("NoColorEnv() = true with NO_COLOR empty, want false")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !NoColorEnv() {
+		t.Error("NoColorEnv() = false with NO_COLOR=1, want true")
+	}
+}
+
+func TestNewColorsForLevel(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "")
+	t.Setenv("WT_SESSION", "")
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	colors := NewColorsFor(f, false)
+	if colors.Reset == "" || colors.Level != Color256 {
+		t.Errorf("NewColorsFor() = %+v, want colors at level %d", colors, Color256)
+	}
+	if colors := NewColorsFor(f, true); colors.Reset != "" {
+		t.Errorf("NewColorsFor(noColor) = %+v, want no colors", colors)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package format
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// vtEnabled reports whether the console behind f interprets ANSI escape
+// sequences, turning on virtual terminal processing if it's off. Consoles
+// that can't (cmd.exe before Windows 10, older PowerShell hosts) would print
+// the sequences as text, so they get plain output.
+func vtEnabled(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		// Not a console (e.g. a mintty pipe); trust the terminal
+		return true
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
+
+// terminalWidth returns the column count of the console window behind f, or 0
+func terminalWidth(f *os.File) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right-info.Window.Left) + 1
+}
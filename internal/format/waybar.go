@@ -0,0 +1,92 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Waybar classes, reflecting the most constrained window
+const (
+	WaybarClassOK       = "ok"
+	WaybarClassWarning  = "warning"
+	WaybarClassCritical = "critical"
+)
+
+// waybarClasses are the classes in increasing severity
+var waybarClasses = []string{WaybarClassOK, WaybarClassWarning, WaybarClassCritical}
+
+// WaybarModule is the JSON a Waybar custom module reads from its exec
+// command (with "return-type": "json")
+type WaybarModule struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Alt        string `json:"alt"`
+	Percentage int    `json:"percentage"`
+}
+
+// Waybar renders usage for a Waybar custom module. The text is the compact
+// line without reset times, the tooltip lists every window with its reset
+// time, and the class is ok, warning, or critical by the most constrained
+// window shown, using colors' thresholds. Percentage is that window's
+// utilization, and alt repeats the class for format-icons.
+func Waybar(usage *models.Usage, colors Colors, formats Formats, opts CompactOptions) WaybarModule {
+	opts.HideResets = true
+	opts.Trends = nil
+	windows := opts.Windows
+	if len(windows) == 0 {
+		windows = DefaultCompactWindows
+	}
+
+	// Pango markup would need escaping, so the text carries no color codes;
+	// style by class in Waybar's CSS instead
+	plain := Colors{Thresholds: colors.Thresholds}
+	module := WaybarModule{Text: Compact(usage, plain, formats, opts)}
+
+	var worst float64
+	var level int
+	for _, name := range windows {
+		w := usage.Window(name)
+		if w == nil {
+			continue
+		}
+		if w.Utilization > worst {
+			worst = w.Utilization
+		}
+		if l := waybarLevel(colors.ThresholdsFor(name, DefaultThresholds), w.Utilization); l > level {
+			level = l
+		}
+	}
+	module.Class = waybarClasses[level]
+	module.Alt = module.Class
+	module.Percentage = int(worst)
+
+	var lines []string
+	for _, w := range usage.Windows() {
+		line := fmt.Sprintf("%s: %d%%", formats.Locale.Label(w.Name), int64(w.Utilization))
+		if w.ResetsAt != nil {
+			layout := formats.Time
+			if w.Name != models.WindowFiveHour {
+				layout = "Mon " + layout
+			}
+			line += ", resets " + ResetTime(*w.ResetsAt, layout, formats)
+		}
+		lines = append(lines, line)
+	}
+	module.Tooltip = strings.Join(lines, "\n")
+	return module
+}
+
+// waybarLevel returns the index of the window's class in waybarClasses
+func waybarLevel(t Thresholds, value float64) int {
+	switch {
+	case value >= t.Critical:
+		return 2
+	case value >= t.Warn:
+		return 1
+	default:
+		return 0
+	}
+}
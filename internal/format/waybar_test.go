@@ -0,0 +1,46 @@
+package format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestWaybar(t *testing.T) {
+	data := `{"five_hour": {"utilization": 45.5, "resets_at": "2030-01-01T13:00:00Z"},
+		"seven_day": {"utilization": 85, "resets_at": "2030-01-07T08:00:00Z"},
+		"seven_day_opus": {"utilization": 99, "resets_at": null}}`
+
+	var usage models.Usage
+	if err := json.Unmarshal([]byte(data), &usage); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	formats := Formats{Time: "15:04"}
+
+	// Opus isn't shown by default, so it doesn't make the class critical
+	got := Waybar(&usage, NewANSIColors(), formats, CompactOptions{})
+	if got.Text != "5h: 45% | wk: 85%" {
+		t.Errorf("Text = %q", got.Text)
+	}
+	if got.Class != WaybarClassWarning || got.Alt != WaybarClassWarning || got.Percentage != 85 {
+		t.Errorf("Class = %q, Alt = %q, Percentage = %d", got.Class, got.Alt, got.Percentage)
+	}
+	five := (*usage.FiveHour.ResetsAt).Local().Format("15:04")
+	week := (*usage.SevenDay.ResetsAt).Local().Format("Mon 15:04")
+	wantTooltip := "Five Hour: 45%, resets " + five + "\nSeven Day: 85%, resets " + week + "\nSeven Day Opus: 99%"
+	if got.Tooltip != wantTooltip {
+		t.Errorf("Tooltip =\n%s\nwant\n%s", got.Tooltip, wantTooltip)
+	}
+
+	got = Waybar(&usage, NewANSIColors(), formats, CompactOptions{Windows: []string{models.WindowSevenDayOpus}})
+	if got.Class != WaybarClassCritical || got.Percentage != 99 {
+		t.Errorf("opus Class = %q, Percentage = %d", got.Class, got.Percentage)
+	}
+
+	// Configured thresholds apply
+	colors := Colors{Thresholds: map[string]Thresholds{"": {Warn: 90, Critical: 99}}}
+	if got := Waybar(&usage, colors, formats, CompactOptions{}); got.Class != WaybarClassOK {
+		t.Errorf("Class with raised thresholds = %q, want ok", got.Class)
+	}
+}
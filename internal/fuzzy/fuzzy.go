@@ -130,23 +130,48 @@ func FlattenData(data map[string]interface{}, prefix string) []KeyValue {
 	return pairs
 }
 
-// FindBestMatch finds the best matching field for a query
-func FindBestMatch(pairs []KeyValue, query string) (*KeyValue, error) {
+// Match is a candidate field for a query with its score
+type Match struct {
+	KeyValue
+	Score int
+}
+
+// FindMatches returns every field matching the query, best first.
+// Equal scores are ordered by path so results are deterministic.
+func FindMatches(pairs []KeyValue, query string) []Match {
 	queryLower := strings.ToLower(query)
-	var bestMatch *KeyValue
-	bestScore := 0
-
-	for i := range pairs {
-		score := Score(queryLower, strings.ToLower(pairs[i].Path))
-		if score > bestScore {
-			bestScore = score
-			bestMatch = &pairs[i]
+	var matches []Match
+
+	for _, kv := range pairs {
+		if score := Score(queryLower, strings.ToLower(kv.Path)); score > 0 {
+			matches = append(matches, Match{KeyValue: kv, Score: score})
 		}
 	}
 
-	if bestMatch == nil || bestScore == 0 {
-		return nil, apierrors.NewQueryError(query, apierrors.ErrNoMatch)
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	return matches
+}
+
+// Tied returns the leading matches that share the best score
+func Tied(matches []Match) []Match {
+	n := 0
+	for n < len(matches) && matches[n].Score == matches[0].Score {
+		n++
 	}
+	return matches[:n]
+}
 
-	return bestMatch, nil
+// FindBestMatch finds the best matching field for a query.
+// Ties go to the alphabetically first path.
+func FindBestMatch(pairs []KeyValue, query string) (*KeyValue, error) {
+	matches := FindMatches(pairs, query)
+	if len(matches) == 0 {
+		return nil, apierrors.NewQueryError(query, apierrors.ErrNoMatch)
+	}
+	return &matches[0].KeyValue, nil
 }
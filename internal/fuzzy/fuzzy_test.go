@@ -128,3 +128,35 @@ func TestFindBestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestFindMatches(t *testing.T) {
+	pairs := []KeyValue{
+		{Path: "seven_day_utilization", Key: "utilization", Value: 23.0},
+		{Path: "five_hour_utilization", Key: "utilization", Value: 75.5},
+		{Path: "seven_day_resets_at", Key: "resets_at", Value: "2030-01-01T00:00:00Z"},
+	}
+
+	matches := FindMatches(pairs, "util")
+	if len(matches) != 2 {
+		t.Fatalf("FindMatches(util) returned %d matches, want 2", len(matches))
+	}
+	// Equal scores are ordered by path
+	if matches[0].Path != "five_hour_utilization" || matches[1].Path != "seven_day_utilization" {
+		t.Errorf("unexpected order: %s, %s", matches[0].Path, matches[1].Path)
+	}
+	if tied := Tied(matches); len(tied) != 2 {
+		t.Errorf("Tied returned %d matches, want 2", len(tied))
+	}
+
+	matches = FindMatches(pairs, "seven_util")
+	if len(matches) == 0 || matches[0].Path != "seven_day_utilization" {
+		t.Fatalf("FindMatches(seven_util) = %+v", matches)
+	}
+	if tied := Tied(matches); len(tied) != 1 {
+		t.Errorf("Tied returned %d matches, want 1", len(tied))
+	}
+
+	if matches := FindMatches(pairs, "nonexistent"); len(matches) != 0 {
+		t.Errorf("FindMatches(nonexistent) = %+v, want none", matches)
+	}
+}
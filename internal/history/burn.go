@@ -0,0 +1,61 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// MaxBurnGap is the longest gap between snapshots counted as active time.
+// Longer gaps, such as a machine asleep overnight, say nothing about pace.
+const MaxBurnGap = time.Hour
+
+// Burn is how much of a window's limit was consumed, and over how much
+// active time
+type Burn struct {
+	// Used is the utilization points consumed; drops, such as resets,
+	// don't count
+	Used float64
+	// Active is the time between snapshots during which utilization rose
+	Active time.Duration
+}
+
+// PerHour returns the utilization points consumed per active hour, or zero
+// if there was no activity
+func (b Burn) PerHour() float64 {
+	if b.Active <= 0 {
+		return 0
+	}
+	return b.Used / b.Active.Hours()
+}
+
+// Burns returns each window's consumption across snapshots in
+// chronological order, keyed by window name
+func Burns(snapshots []Snapshot) map[string]Burn {
+	burns := make(map[string]Burn)
+	last := make(map[string]float64)
+	var prev time.Time
+	for _, snap := range snapshots {
+		var usage models.Usage
+		if err := json.Unmarshal(snap.Usage, &usage); err != nil {
+			continue
+		}
+		gap := snap.Timestamp.Sub(prev)
+		for _, w := range usage.Windows() {
+			before, seen := last[w.Name]
+			last[w.Name] = w.Utilization
+			if !seen || w.Utilization <= before {
+				continue
+			}
+			b := burns[w.Name]
+			b.Used += w.Utilization - before
+			if gap <= MaxBurnGap {
+				b.Active += gap
+			}
+			burns[w.Name] = b
+		}
+		prev = snap.Timestamp
+	}
+	return burns
+}
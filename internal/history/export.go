@@ -0,0 +1,122 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ExportSchema names the snapshot export format
+const ExportSchema = "claude-limits/snapshots"
+
+// ExportVersion is the export format version written by WriteExport.
+// ReadExport reads this version and older.
+const ExportVersion = 1
+
+// maxExportLine bounds one line of an export, i.e. one snapshot
+const maxExportLine = 4 << 20
+
+// ExportHeader is the first line of an export
+type ExportHeader struct {
+	Schema     string    `json:"schema"`
+	Version    int       `json:"version"`
+	Profile    string    `json:"profile,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// WriteExport writes snapshots as JSON lines: a header, then one snapshot
+// per line
+func WriteExport(w io.Writer, profile string, snapshots []Snapshot) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	header := ExportHeader{Schema: ExportSchema, Version: ExportVersion, Profile: profile, ExportedAt: time.Now().UTC()}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for _, snap := range snapshots {
+		// Compact so a snapshot is always one line
+		var usage bytes.Buffer
+		if err := json.Compact(&usage, snap.Usage); err != nil {
+			return fmt.Errorf("snapshot at %s: %w", snap.Timestamp.Format(time.RFC3339), err)
+		}
+		if err := enc.Encode(Snapshot{Timestamp: snap.Timestamp.UTC(), Usage: usage.Bytes()}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadExport reads an export written by WriteExport. Blank lines are
+// skipped.
+func ReadExport(r io.Reader) (ExportHeader, []Snapshot, error) {
+	var header ExportHeader
+	var snapshots []Snapshot
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxExportLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		if header.Schema == "" {
+			if err := json.Unmarshal(data, &header); err != nil || header.Schema != ExportSchema {
+				return header, nil, fmt.Errorf("not a claude-limits snapshot export (line %d)", line)
+			}
+			if header.Version < 1 || header.Version > ExportVersion {
+				return header, nil, fmt.Errorf("unsupported export version %d (this build reads up to %d)", header.Version, ExportVersion)
+			}
+			continue
+		}
+
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return header, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if snap.Timestamp.IsZero() || len(snap.Usage) == 0 || snap.Usage[0] != '{' {
+			return header, nil, fmt.Errorf("line %d: snapshot needs a timestamp and a usage object", line)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, err
+	}
+	if header.Schema == "" {
+		return header, nil, fmt.Errorf("empty export")
+	}
+	return header, snapshots, nil
+}
+
+// Import merges snapshots into the store in one transaction. Snapshots
+// already recorded at the same time are left as they are, so importing the
+// same export twice adds nothing the second time. Returns how many were
+// added.
+func (s *Store) Import(snapshots []Snapshot) (int, error) {
+	added := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(snapshotsBucket)
+		for _, snap := range snapshots {
+			key := timeKey(snap.Timestamp)
+			if bucket.Get(key) != nil {
+				continue
+			}
+			if err := bucket.Put(key, snap.Usage); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import history: %w", err)
+	}
+	return added, nil
+}
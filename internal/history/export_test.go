@@ -0,0 +1,82 @@
+package history
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 123, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := src.Record(usageOf(`{"five_hour": {"utilization": 10}}`), base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	snapshots, err := src.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteExport(&buf, "work", snapshots); err != nil {
+		t.Fatalf("WriteExport failed: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Errorf("export has %d lines, want a header and 3 snapshots:\n%s", lines, buf.String())
+	}
+
+	header, read, err := ReadExport(&buf)
+	if err != nil {
+		t.Fatalf("ReadExport failed: %v", err)
+	}
+	if header.Version != ExportVersion || header.Profile != "work" {
+		t.Errorf("header = %+v", header)
+	}
+
+	dst := openTestStore(t)
+	if err := dst.Record(usageOf(`{"five_hour":{"utilization":99}}`), base); err != nil {
+		t.Fatal(err)
+	}
+	added, err := dst.Import(read)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("added %d snapshots, want 2 (one already present)", added)
+	}
+	if added, _ := dst.Import(read); added != 0 {
+		t.Errorf("second import added %d snapshots, want 0", added)
+	}
+
+	merged, err := dst.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("merged history has %d snapshots, want 3", len(merged))
+	}
+	if !merged[0].Timestamp.Equal(base) || string(merged[0].Usage) != `{"five_hour":{"utilization":99}}` {
+		t.Errorf("existing snapshot was overwritten: %+v", merged[0])
+	}
+	if !merged[2].Timestamp.Equal(base.Add(2*time.Hour)) || string(merged[2].Usage) != `{"five_hour":{"utilization":10}}` {
+		t.Errorf("imported snapshot = %s %s", merged[2].Timestamp, merged[2].Usage)
+	}
+}
+
+func TestReadExportErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "",
+		"no header":      `{"timestamp":"2025-01-01T00:00:00Z","usage":{}}`,
+		"future version": `{"schema":"claude-limits/snapshots","version":99}`,
+		"bad snapshot":   "{\"schema\":\"claude-limits/snapshots\",\"version\":1}\nnot json\n",
+		"missing usage":  "{\"schema\":\"claude-limits/snapshots\",\"version\":1}\n{\"timestamp\":\"2025-01-01T00:00:00Z\"}\n",
+	}
+	for name, input := range tests {
+		if _, _, err := ReadExport(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package history
+
+import (
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Forecast projects a window's utilization forward at its recent pace
+type Forecast struct {
+	Window      string     `json:"window"`
+	Utilization float64    `json:"utilization"`
+	ResetsAt    *time.Time `json:"resets_at,omitempty"`
+	// PerHour is the utilization points consumed per hour across the
+	// snapshots, idle time included, so it's a calendar pace
+	PerHour float64 `json:"per_hour"`
+	// LimitAt is when utilization reaches 100 at PerHour, if that's before
+	// the window resets
+	LimitAt *time.Time `json:"limit_at,omitempty"`
+	// AtReset is the utilization projected for when the window resets,
+	// capped at 100
+	AtReset *float64 `json:"at_reset,omitempty"`
+}
+
+// NewForecast projects the named window of current usage w from snapshots
+// in chronological order, up to now. Without consumption across the
+// snapshots the pace is zero, and nothing is projected.
+func NewForecast(name string, w *models.Window, snapshots []Snapshot, now time.Time) Forecast {
+	f := Forecast{Window: name, Utilization: w.Utilization, ResetsAt: w.ResetsAt}
+	if len(snapshots) < 2 {
+		return f
+	}
+	span := now.Sub(snapshots[0].Timestamp)
+	used := Burns(snapshots)[name].Used
+	if span <= 0 || used <= 0 {
+		return f
+	}
+	f.PerHour = used / span.Hours()
+
+	limitAt := now.Add(time.Duration(w.Remaining() / f.PerHour * float64(time.Hour)))
+	if w.ResetsAt == nil || limitAt.Before(*w.ResetsAt) {
+		f.LimitAt = &limitAt
+	}
+	if w.ResetsAt != nil && w.ResetsAt.After(now) {
+		atReset := min(100, w.Utilization+f.PerHour*w.ResetsAt.Sub(now).Hours())
+		f.AtReset = &atReset
+	}
+	return f
+}
@@ -0,0 +1,54 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func snapshotsOf(t *testing.T, start time.Time, step time.Duration, utils ...float64) []Snapshot {
+	t.Helper()
+	var snapshots []Snapshot
+	for i, u := range utils {
+		data, err := json.Marshal(map[string]any{"seven_day": map[string]any{"utilization": u}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, Snapshot{Timestamp: start.Add(time.Duration(i) * step), Usage: data})
+	}
+	return snapshots
+}
+
+func TestNewForecast(t *testing.T) {
+	now := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+	// 12 points over the last day: half a point an hour
+	snapshots := snapshotsOf(t, now.Add(-24*time.Hour), 6*time.Hour, 40, 43, 46, 49, 52)
+
+	resets := now.Add(48 * time.Hour)
+	f := NewForecast("seven_day", &models.Window{Utilization: 52, ResetsAt: &resets}, snapshots, now)
+	if f.PerHour != 0.5 {
+		t.Errorf("PerHour = %v, want 0.5", f.PerHour)
+	}
+	if f.LimitAt != nil {
+		t.Errorf("LimitAt = %v, want none: 48 points need 96h, after the reset", f.LimitAt)
+	}
+	if f.AtReset == nil || *f.AtReset != 76 {
+		t.Errorf("AtReset = %v, want 76", f.AtReset)
+	}
+
+	later := now.Add(200 * time.Hour)
+	f = NewForecast("seven_day", &models.Window{Utilization: 52, ResetsAt: &later}, snapshots, now)
+	if want := now.Add(96 * time.Hour); f.LimitAt == nil || !f.LimitAt.Equal(want) {
+		t.Errorf("LimitAt = %v, want %v", f.LimitAt, want)
+	}
+	if f.AtReset == nil || *f.AtReset != 100 {
+		t.Errorf("AtReset = %v, want capped at 100", f.AtReset)
+	}
+
+	flat := snapshotsOf(t, now.Add(-time.Hour), time.Hour, 52, 52)
+	if f := NewForecast("seven_day", &models.Window{Utilization: 52}, flat, now); f.PerHour != 0 || f.LimitAt != nil || f.AtReset != nil {
+		t.Errorf("idle forecast = %+v, want no projection", f)
+	}
+}
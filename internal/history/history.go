@@ -0,0 +1,163 @@
+// Package history records usage snapshots to a local database for later querying.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// File permission constants
+const (
+	DirMode  = 0700 // rwx------ for history directory (private)
+	FileMode = 0600 // rw------- for history database (contains API data)
+)
+
+// snapshotsBucket holds snapshots keyed by big-endian Unix nanoseconds,
+// so bolt's byte ordering is also chronological ordering.
+var snapshotsBucket = []byte("snapshots")
+
+// openTimeout bounds how long Open waits for another process holding the database
+const openTimeout = time.Second
+
+// Snapshot is a single recorded usage response
+type Snapshot struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Usage     json.RawMessage `json:"usage"`
+}
+
+// Store is a persistent, time-ordered collection of usage snapshots
+type Store struct {
+	db   *bolt.DB
+	path string
+}
+
+// DefaultPath returns the default history database path under the state directory
+func DefaultPath() string {
+	return PathForProfile("")
+}
+
+// PathForProfile returns the history database path for a named profile.
+// An empty profile returns the default database.
+func PathForProfile(profile string) string {
+	name := "history.db"
+	if profile != "" {
+		name = "history-" + profile + ".db"
+	}
+	return cache.StatePath(name)
+}
+
+// Open opens (creating if needed) the history database at path.
+// If path is empty, uses DefaultPath.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	if err := cache.EnsureDir(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, FileMode, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(snapshotsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+
+	return &Store{db: db, path: path}, nil
+}
+
+// Close releases the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the database file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Record stores a usage snapshot taken at the given time
+func (s *Store) Record(usage *models.Usage, at time.Time) error {
+	if usage == nil || usage.Raw == nil {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(snapshotsBucket).Put(timeKey(at), usage.Raw)
+	})
+}
+
+// Query returns snapshots with from <= timestamp <= to in chronological order.
+// A zero from or to leaves that end of the range open.
+func (s *Store) Query(from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(snapshotsBucket).Cursor()
+
+		var k, v []byte
+		if from.IsZero() {
+			k, v = c.First()
+		} else {
+			k, v = c.Seek(timeKey(from))
+		}
+
+		for ; k != nil; k, v = c.Next() {
+			ts := keyTime(k)
+			if !to.IsZero() && ts.After(to) {
+				break
+			}
+			raw := make(json.RawMessage, len(v))
+			copy(raw, v)
+			snapshots = append(snapshots, Snapshot{Timestamp: ts, Usage: raw})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// Utilization returns each window's utilization across snapshots in order,
+// keyed by window name. Snapshots missing a window are skipped for it.
+func Utilization(snapshots []Snapshot) map[string][]float64 {
+	series := make(map[string][]float64)
+	for _, snap := range snapshots {
+		var usage models.Usage
+		if err := json.Unmarshal(snap.Usage, &usage); err != nil {
+			continue
+		}
+		for _, w := range usage.Windows() {
+			series[w.Name] = append(series[w.Name], w.Utilization)
+		}
+	}
+	return series
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key)))
+}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func usageOf(raw string) *models.Usage {
+	usage := &models.Usage{}
+	_ = json.Unmarshal([]byte(raw), usage)
+	return usage
+}
+
+func TestRecordAndQuery(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		if err := s.Record(usageOf(`{"five_hour":{"utilization":10}}`), base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	all, err := s.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("Query returned %d snapshots, want 5", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if !all[i].Timestamp.After(all[i-1].Timestamp) {
+			t.Errorf("snapshots not in chronological order at %d", i)
+		}
+	}
+
+	ranged, err := s.Query(base.Add(time.Hour), base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(ranged) != 3 {
+		t.Errorf("ranged Query returned %d snapshots, want 3", len(ranged))
+	}
+	if !ranged[0].Timestamp.Equal(base.Add(time.Hour)) {
+		t.Errorf("first timestamp = %v, want %v", ranged[0].Timestamp, base.Add(time.Hour))
+	}
+}
+
+func TestRecordPreservesRaw(t *testing.T) {
+	s := openTestStore(t)
+	raw := `{"five_hour":{"utilization":42.5}}`
+
+	if err := s.Record(usageOf(raw), time.Now()); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	snaps, err := s.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(snaps) != 1 || string(snaps[0].Usage) != raw {
+		t.Errorf("Query returned %v, want single snapshot with %s", snaps, raw)
+	}
+}
+
+func TestRecordNilUsage(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Record(nil, time.Now()); err != nil {
+		t.Errorf("Record(nil) should be a no-op, got %v", err)
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	snapshots := []Snapshot{
+		{Usage: json.RawMessage(`{"five_hour":{"utilization":10},"seven_day":{"utilization":5}}`)},
+		{Usage: json.RawMessage(`not json`)},
+		{Usage: json.RawMessage(`{"five_hour":{"utilization":20}}`)},
+	}
+
+	series := Utilization(snapshots)
+	if got := series[models.WindowFiveHour]; len(got) != 2 || got[0] != 10 || got[1] != 20 {
+		t.Errorf("five_hour series = %v, want [10 20]", got)
+	}
+	if got := series[models.WindowSevenDay]; len(got) != 1 || got[0] != 5 {
+		t.Errorf("seven_day series = %v, want [5]", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	// Wednesday 2025-01-01
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	var snapshots []Snapshot
+	add := func(at time.Time, raw string) {
+		snapshots = append(snapshots, Snapshot{Timestamp: at, Usage: json.RawMessage(raw)})
+	}
+	add(base, `{"five_hour":{"utilization":20},"seven_day":{"utilization":10}}`)
+	add(base.Add(5*time.Hour), `{"five_hour":{"utilization":100},"seven_day":{"utilization":30}}`)
+	add(base.Add(6*time.Hour), `{"five_hour":{"utilization":100},"seven_day":{"utilization":30}}`)
+	add(base.Add(24*time.Hour), `{"five_hour":{"utilization":0},"seven_day":{"utilization":40}}`)
+	add(base.Add(29*time.Hour), `{"five_hour":{"utilization":100},"seven_day":{"utilization":60}}`)
+
+	stats := Stats(snapshots, time.UTC)
+	if len(stats) != 2 || stats[0].Window != "five_hour" || stats[1].Window != "seven_day" {
+		t.Fatalf("Stats windows = %+v, want five_hour then seven_day", stats)
+	}
+
+	five := stats[0]
+	if five.Samples != 5 || five.Average != 64 || five.Peak != 100 {
+		t.Errorf("five_hour samples/average/peak = %d/%v/%v, want 5/64/100", five.Samples, five.Average, five.Peak)
+	}
+	if five.LimitHits != 2 {
+		t.Errorf("five_hour LimitHits = %d, want 2 (staying at the limit counts once)", five.LimitHits)
+	}
+	if hour, ok := five.TypicalHitHour(); !ok || hour != 14 {
+		t.Errorf("TypicalHitHour() = %d, %v, want 14, true", hour, ok)
+	}
+	if days := five.BusiestWeekdays(3); len(days) != 2 || days[0] != time.Thursday || days[1] != time.Wednesday {
+		t.Errorf("BusiestWeekdays() = %v, want [Thursday Wednesday]", days)
+	}
+
+	if _, ok := stats[1].TypicalHitHour(); ok || stats[1].LimitHits != 0 {
+		t.Errorf("seven_day hits = %d, want none", stats[1].LimitHits)
+	}
+}
+
+func TestBurns(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	var snapshots []Snapshot
+	add := func(at time.Time, raw string) {
+		snapshots = append(snapshots, Snapshot{Timestamp: at, Usage: json.RawMessage(raw)})
+	}
+	add(base, `{"five_hour":{"utilization":0},"seven_day":{"utilization":10}}`)
+	add(base.Add(30*time.Minute), `{"five_hour":{"utilization":20},"seven_day":{"utilization":12}}`)
+	add(base.Add(time.Hour), `{"five_hour":{"utilization":40},"seven_day":{"utilization":14}}`)
+	// Overnight: used, but the gap is too long to say over how long
+	add(base.Add(12*time.Hour), `{"five_hour":{"utilization":0},"seven_day":{"utilization":20}}`)
+
+	burns := Burns(snapshots)
+	five := burns["five_hour"]
+	if five.Used != 40 || five.Active != time.Hour || five.PerHour() != 40 {
+		t.Errorf("five_hour burn = %+v (%v/h), want 40 over 1h", five, five.PerHour())
+	}
+	week := burns["seven_day"]
+	if week.Used != 10 || week.Active != time.Hour {
+		t.Errorf("seven_day burn = %+v, want 10 over 1h", week)
+	}
+	if (Burn{Used: 5}).PerHour() != 0 {
+		t.Error("PerHour without active time should be 0")
+	}
+}
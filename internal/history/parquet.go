@@ -0,0 +1,307 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// parquetMagic starts and ends every Parquet file
+const parquetMagic = "PAR1"
+
+// Parquet enum values used by WriteParquet, from the format's parquet.thrift
+const (
+	parquetInt64     = 2 // Type
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0 // FieldRepetitionType
+	parquetOptional = 1
+
+	parquetUTF8            = 0 // ConvertedType
+	parquetTimestampMillis = 9
+
+	parquetPlain = 0 // Encoding
+	parquetRLE   = 3
+
+	parquetDataPage     = 0 // PageType
+	parquetUncompressed = 0 // CompressionCodec
+)
+
+// parquetColumn is one column's schema and plain-encoded values
+type parquetColumn struct {
+	name      string
+	typ       int32
+	converted int32
+	// optional columns have a definition level per row: false for null
+	optional bool
+	defined  []bool
+	values   bytes.Buffer
+}
+
+// WriteParquet writes rows as a Parquet file with a single row group, for
+// pandas, DuckDB, and the like: timestamp and resets_at (null if unknown)
+// as UTC millisecond timestamps, window as a string, and utilization as a
+// double. Pages are plain-encoded and uncompressed.
+func WriteParquet(w io.Writer, rows []Row) error {
+	timestamp := &parquetColumn{name: rowColumns[0], typ: parquetInt64, converted: parquetTimestampMillis}
+	window := &parquetColumn{name: rowColumns[1], typ: parquetByteArray, converted: parquetUTF8}
+	utilization := &parquetColumn{name: rowColumns[2], typ: parquetDouble, converted: -1}
+	resetsAt := &parquetColumn{name: rowColumns[3], typ: parquetInt64, converted: parquetTimestampMillis, optional: true}
+	columns := []*parquetColumn{timestamp, window, utilization, resetsAt}
+
+	for _, r := range rows {
+		binary.Write(&timestamp.values, binary.LittleEndian, r.Timestamp.UnixMilli())
+		binary.Write(&window.values, binary.LittleEndian, uint32(len(r.Window)))
+		window.values.WriteString(r.Window)
+		binary.Write(&utilization.values, binary.LittleEndian, math.Float64bits(r.Utilization))
+		resetsAt.defined = append(resetsAt.defined, r.ResetsAt != nil)
+		if r.ResetsAt != nil {
+			binary.Write(&resetsAt.values, binary.LittleEndian, r.ResetsAt.UnixMilli())
+		}
+	}
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	// Column chunks, each a single data page, then the footer describing them
+	var meta thriftWriter
+	meta.i32(1, 1) // version
+	meta.listBegin(2, thriftStruct, len(columns)+1)
+	meta.elemBegin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.structEnd()
+	for _, c := range columns {
+		meta.elemBegin()
+		meta.i32(1, c.typ)
+		repetition := int32(parquetRequired)
+		if c.optional {
+			repetition = parquetOptional
+		}
+		meta.i32(3, repetition)
+		meta.binary(4, c.name)
+		if c.converted >= 0 {
+			meta.i32(6, c.converted)
+			c.logicalType(&meta)
+		}
+		meta.structEnd()
+	}
+	meta.i64(3, int64(len(rows)))
+
+	if len(rows) == 0 {
+		meta.listBegin(4, thriftStruct, 0)
+	} else {
+		meta.listBegin(4, thriftStruct, 1)
+		meta.elemBegin()
+		meta.listBegin(1, thriftStruct, len(columns))
+		var groupSize int64
+		for _, c := range columns {
+			offset := int64(file.Len())
+			size := c.writeChunk(&file, len(rows))
+			groupSize += size
+
+			meta.elemBegin()
+			meta.i64(2, offset) // file_offset
+			meta.structBegin(3)
+			meta.i32(1, c.typ)
+			encodings := []int32{parquetPlain}
+			if c.optional {
+				encodings = append(encodings, parquetRLE)
+			}
+			meta.listBegin(2, thriftI32, len(encodings))
+			for _, e := range encodings {
+				meta.listI32(e)
+			}
+			meta.listBegin(3, thriftBinary, 1)
+			meta.listBinary(c.name)
+			meta.i32(4, parquetUncompressed)
+			meta.i64(5, int64(len(rows)))
+			meta.i64(6, size)
+			meta.i64(7, size)
+			meta.i64(9, offset) // data_page_offset
+			meta.structEnd()
+			meta.structEnd()
+		}
+		meta.i64(2, groupSize)
+		meta.i64(3, int64(len(rows)))
+		meta.structEnd()
+	}
+	meta.binary(6, "claude-limits")
+	meta.stop()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// logicalType writes the column's LogicalType, the successor to
+// ConvertedType, to a SchemaElement
+func (c *parquetColumn) logicalType(t *thriftWriter) {
+	t.structBegin(10)
+	switch c.converted {
+	case parquetUTF8:
+		t.structBegin(1) // STRING
+		t.structEnd()
+	case parquetTimestampMillis:
+		t.structBegin(8) // TIMESTAMP
+		t.bool(1, true)  // isAdjustedToUTC
+		t.structBegin(2) // unit
+		t.structBegin(1) // MILLIS
+		t.structEnd()
+		t.structEnd()
+		t.structEnd()
+	}
+	t.structEnd()
+}
+
+// writeChunk writes the column as one data page and returns its size
+func (c *parquetColumn) writeChunk(w *bytes.Buffer, numRows int) int64 {
+	var page bytes.Buffer
+	if c.optional {
+		levels := rleBools(c.defined)
+		binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	page.Write(c.values.Bytes())
+
+	var header thriftWriter
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(page.Len())) // uncompressed_page_size
+	header.i32(3, int32(page.Len())) // compressed_page_size
+	header.structBegin(5)
+	header.i32(1, int32(numRows))
+	header.i32(2, parquetPlain)
+	header.i32(3, parquetRLE) // definition_level_encoding
+	header.i32(4, parquetRLE) // repetition_level_encoding
+	header.structEnd()
+	header.stop()
+
+	w.Write(header.buf.Bytes())
+	w.Write(page.Bytes())
+	return int64(header.buf.Len() + page.Len())
+}
+
+// rleBools encodes levels of bit width 1 as runs of the RLE/bit-packing
+// hybrid encoding
+func rleBools(levels []bool) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		if levels[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// Thrift compact protocol types
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift compact protocol structs of Parquet's
+// metadata. Fields must be written in increasing id order within a struct.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.lastID = id
+}
+
+// varint writes a zigzag varint
+func (t *thriftWriter) varint(n int64) {
+	t.buf.Write(binary.AppendVarint(nil, n))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) bool(id int16, v bool) {
+	typ := byte(thriftFalse)
+	if v {
+		typ = thriftTrue
+	}
+	t.field(id, typ)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.listBinary(s)
+}
+
+// structBegin starts a struct field; end it with structEnd
+func (t *thriftWriter) structBegin(id int16) {
+	t.field(id, thriftStruct)
+	t.elemBegin()
+}
+
+// elemBegin starts a struct element of a list; end it with structEnd
+func (t *thriftWriter) elemBegin() {
+	t.stack = append(t.stack, t.lastID)
+	t.lastID = 0
+}
+
+func (t *thriftWriter) structEnd() {
+	t.stop()
+	t.lastID = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// stop ends a struct, including the outermost one
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
+
+// listBegin starts a list field of size elements of typ
+func (t *thriftWriter) listBegin(id int16, typ byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | typ)
+	} else {
+		t.buf.WriteByte(0xf0 | typ)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listBinary(s string) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.buf.WriteString(s)
+}
@@ -0,0 +1,69 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// Row is one window of one snapshot, the tidy shape analysis tools expect
+type Row struct {
+	Timestamp   time.Time  `json:"timestamp"`
+	Window      string     `json:"window"`
+	Utilization float64    `json:"utilization"`
+	ResetsAt    *time.Time `json:"resets_at,omitempty"`
+}
+
+// Rows flattens snapshots into one row per window, in snapshot order.
+// Snapshots that don't decode are skipped.
+func Rows(snapshots []Snapshot) []Row {
+	var rows []Row
+	for _, snap := range snapshots {
+		var usage models.Usage
+		if err := json.Unmarshal(snap.Usage, &usage); err != nil {
+			continue
+		}
+		for _, w := range usage.Windows() {
+			rows = append(rows, Row{
+				Timestamp:   snap.Timestamp.UTC(),
+				Window:      w.Name,
+				Utilization: w.Utilization,
+				ResetsAt:    w.ResetsAt,
+			})
+		}
+	}
+	return rows
+}
+
+// rowColumns names the columns of CSV and Parquet exports
+var rowColumns = []string{"timestamp", "window", "utilization", "resets_at"}
+
+// WriteCSV writes rows as CSV with a header line. Times are RFC 3339 in
+// UTC; an unknown reset time is empty.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(rowColumns); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		resetsAt := ""
+		if r.ResetsAt != nil {
+			resetsAt = r.ResetsAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			r.Timestamp.UTC().Format(time.RFC3339Nano),
+			r.Window,
+			strconv.FormatFloat(r.Utilization, 'f', -1, 64),
+			resetsAt,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
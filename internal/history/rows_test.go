@@ -0,0 +1,79 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func testRows(t *testing.T) []Row {
+	t.Helper()
+	at := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		{Timestamp: at, Usage: json.RawMessage(`{"five_hour": {"utilization": 42.5, "resets_at": "2025-01-15T14:00:00Z"}, "seven_day": {"utilization": 10}}`)},
+		{Timestamp: at.Add(time.Hour), Usage: json.RawMessage(`not json`)},
+	}
+	rows := Rows(snapshots)
+	if len(rows) != 2 {
+		t.Fatalf("Rows() returned %d rows, want 2", len(rows))
+	}
+	return rows
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testRows(t)); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	want := "timestamp,window,utilization,resets_at\n" +
+		"2025-01-15T12:00:00Z,five_hour,42.5,2025-01-15T14:00:00Z\n" +
+		"2025-01-15T12:00:00Z,seven_day,10,\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, testRows(t)); err != nil {
+		t.Fatalf("WriteParquet failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+		t.Fatal("file doesn't start and end with PAR1")
+	}
+	footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footer <= 0 || footer > len(data)-12 {
+		t.Fatalf("footer length %d out of range for a %d byte file", footer, len(data))
+	}
+	meta := data[len(data)-8-footer : len(data)-8]
+	for _, name := range rowColumns {
+		if !bytes.Contains(meta, []byte(name)) {
+			t.Errorf("footer is missing column %q", name)
+		}
+	}
+
+	// Plain-encoded values sit in the column chunks before the footer
+	chunks := data[4 : len(data)-8-footer]
+	for _, want := range [][]byte{
+		binary.LittleEndian.AppendUint64(nil, uint64(time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC).UnixMilli())),
+		append(binary.LittleEndian.AppendUint32(nil, 9), "five_hour"...),
+		binary.LittleEndian.AppendUint64(nil, uint64(time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC).UnixMilli())),
+	} {
+		if !bytes.Contains(chunks, want) {
+			t.Errorf("column chunks missing % x", want)
+		}
+	}
+}
+
+func TestRLEBools(t *testing.T) {
+	got := rleBools([]bool{true, true, true, false, true})
+	want := []byte{3 << 1, 1, 1 << 1, 0, 1 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("rleBools = % x, want % x", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// LimitHit is the utilization at which a window's limit counts as hit
+const LimitHit = 100.0
+
+// WindowStats summarizes a window's recorded utilization
+type WindowStats struct {
+	Window  string  `json:"window"`
+	Samples int     `json:"samples"`
+	Average float64 `json:"average"`
+	Peak    float64 `json:"peak"`
+	// LimitHits counts the times utilization reached LimitHit, however long
+	// it stayed there
+	LimitHits int `json:"limit_hits"`
+	// HitHours counts limit hits by hour of day
+	HitHours [24]int `json:"hit_hours"`
+	// Weekdays sums the utilization consumed on each weekday, Sunday first.
+	// Drops, such as resets, don't count.
+	Weekdays [7]float64 `json:"weekdays"`
+}
+
+// TypicalHitHour returns the hour of day the limit was most often hit, or
+// false if it never was
+func (s WindowStats) TypicalHitHour() (int, bool) {
+	hour, most := 0, 0
+	for h, n := range s.HitHours {
+		if n > most {
+			hour, most = h, n
+		}
+	}
+	return hour, most > 0
+}
+
+// BusiestWeekdays returns up to n weekdays with any usage, busiest first
+func (s WindowStats) BusiestWeekdays(n int) []time.Weekday {
+	var days []time.Weekday
+	for d, used := range s.Weekdays {
+		if used > 0 {
+			days = append(days, time.Weekday(d))
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return s.Weekdays[days[i]] > s.Weekdays[days[j]]
+	})
+	if len(days) > n {
+		days = days[:n]
+	}
+	return days
+}
+
+// Stats summarizes each window across snapshots in chronological order,
+// bucketing hours and weekdays in loc. Windows are in the order first seen.
+func Stats(snapshots []Snapshot, loc *time.Location) []WindowStats {
+	var stats []WindowStats
+	index := make(map[string]int)
+	last := make(map[string]float64)
+
+	for _, snap := range snapshots {
+		var usage models.Usage
+		if err := json.Unmarshal(snap.Usage, &usage); err != nil {
+			continue
+		}
+		at := snap.Timestamp.In(loc)
+		for _, w := range usage.Windows() {
+			i, ok := index[w.Name]
+			if !ok {
+				i = len(stats)
+				index[w.Name] = i
+				stats = append(stats, WindowStats{Window: w.Name})
+			}
+			s := &stats[i]
+
+			u := w.Utilization
+			prev, seen := last[w.Name]
+			s.Average += (u - s.Average) / float64(s.Samples+1)
+			s.Samples++
+			s.Peak = max(s.Peak, u)
+			if u >= LimitHit && (!seen || prev < LimitHit) {
+				s.LimitHits++
+				s.HitHours[at.Hour()]++
+			}
+			if seen && u > prev {
+				s.Weekdays[at.Weekday()] += u - prev
+			}
+			last[w.Name] = u
+		}
+	}
+	return stats
+}
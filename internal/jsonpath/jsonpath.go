@@ -0,0 +1,140 @@
+// Package jsonpath evaluates simple JSONPath expressions against decoded JSON.
+//
+// Supported syntax is the subset useful for extracting single fields:
+//
+//	$                   the root ($ may be omitted)
+//	.name  or  name     object member
+//	['name'] ["name"]   object member with any characters
+//	[n]                 array element; negative n counts from the end
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+// Segment is a single step in a path: an object key or an array index
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// String renders the segment as it appears in a path
+func (s Segment) String() string {
+	if s.IsIndex {
+		return fmt.Sprintf("[%d]", s.Index)
+	}
+	return "." + s.Key
+}
+
+// Parse splits a path expression into segments
+func Parse(path string) ([]Segment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+
+	var segs []Segment
+	for i := 0; i < len(p); {
+		switch {
+		case p[i] == '.':
+			i++
+			end := i
+			for end < len(p) && !strings.ContainsRune(".[]", rune(p[end])) {
+				end++
+			}
+			if end == i {
+				return nil, invalid(path, "empty member name at offset %d", i)
+			}
+			segs = append(segs, Segment{Key: p[i:end]})
+			i = end
+
+		case p[i] == '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, invalid(path, "unclosed '[' at offset %d", i)
+			}
+			inner := strings.TrimSpace(p[i+1 : i+end])
+			seg, err := parseBracket(inner)
+			if err != nil {
+				return nil, invalid(path, "%v", err)
+			}
+			segs = append(segs, seg)
+			i += end + 1
+
+		case i == 0:
+			// Bare leading member, e.g. "five_hour.utilization"
+			p = "." + p
+
+		default:
+			return nil, invalid(path, "unexpected %q at offset %d", p[i], i)
+		}
+	}
+	return segs, nil
+}
+
+func parseBracket(inner string) (Segment, error) {
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return Segment{Key: inner[1 : len(inner)-1]}, nil
+	}
+	if inner == "*" {
+		return Segment{}, fmt.Errorf("wildcards are not supported")
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return Segment{}, fmt.Errorf("bracket must hold a quoted name or an integer index, got %q", inner)
+	}
+	return Segment{Index: n, IsIndex: true}, nil
+}
+
+// Get evaluates path against data decoded by encoding/json
+func Get(data interface{}, path string) (interface{}, error) {
+	segs, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	at := "$"
+	for _, seg := range segs {
+		at += seg.String()
+		switch v := current.(type) {
+		case map[string]interface{}:
+			if seg.IsIndex {
+				return nil, notFound(path, "%s: cannot index an object", at)
+			}
+			next, ok := v[seg.Key]
+			if !ok {
+				return nil, notFound(path, "%s", at)
+			}
+			current = next
+
+		case []interface{}:
+			if !seg.IsIndex {
+				return nil, notFound(path, "%s: cannot select a member of an array", at)
+			}
+			idx := seg.Index
+			if idx < 0 {
+				idx += len(v)
+			}
+			if idx < 0 || idx >= len(v) {
+				return nil, notFound(path, "%s: index out of range (length %d)", at, len(v))
+			}
+			current = v[idx]
+
+		default:
+			return nil, notFound(path, "%s: parent is not an object or array", at)
+		}
+	}
+	return current, nil
+}
+
+func invalid(path, format string, args ...interface{}) error {
+	return apierrors.NewQueryError(path, fmt.Errorf("%w: %s", apierrors.ErrInvalidPath, fmt.Sprintf(format, args...)))
+}
+
+func notFound(path, format string, args ...interface{}) error {
+	return apierrors.NewQueryError(path, fmt.Errorf("%w: %s", apierrors.ErrPathNotFound, fmt.Sprintf(format, args...)))
+}
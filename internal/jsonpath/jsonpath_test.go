@@ -0,0 +1,88 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+)
+
+const doc = `{
+  "five_hour": {"utilization": 45, "resets_at": "2030-01-01T14:30:00Z"},
+  "seven_day": null,
+  "odd.key": true,
+  "items": [1, 2, 3]
+}`
+
+func decode(t *testing.T) interface{} {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return data
+}
+
+func TestGet(t *testing.T) {
+	data := decode(t)
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.five_hour.resets_at", "2030-01-01T14:30:00Z"},
+		{"five_hour.utilization", float64(45)},
+		{".five_hour.utilization", float64(45)},
+		{"$['five_hour']['utilization']", float64(45)},
+		{`$["odd.key"]`, true},
+		{"$.items[1]", float64(2)},
+		{"$.items[-1]", float64(3)},
+		{"$.seven_day", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := Get(data, tt.path)
+		if err != nil {
+			t.Errorf("Get(%q) error: %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Get(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGetRoot(t *testing.T) {
+	got, err := Get(decode(t), "$")
+	if err != nil {
+		t.Fatalf("Get($) error: %v", err)
+	}
+	if _, ok := got.(map[string]interface{}); !ok {
+		t.Errorf("Get($) = %T, want object", got)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	data := decode(t)
+	for _, path := range []string{
+		"$.missing",
+		"$.five_hour.missing",
+		"$.seven_day.utilization",
+		"$.items[3]",
+		"$.items.name",
+		"$.five_hour[0]",
+	} {
+		_, err := Get(data, path)
+		if !apierrors.Is(err, apierrors.ErrPathNotFound) {
+			t.Errorf("Get(%q) error = %v, want ErrPathNotFound", path, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, path := range []string{"$.", "$..x", "$[", "$[abc]", "$[*]", "$.a]"} {
+		_, err := Parse(path)
+		if !apierrors.Is(err, apierrors.ErrInvalidPath) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidPath", path, err)
+		}
+	}
+}
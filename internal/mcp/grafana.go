@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// maxGrafanaBody bounds the size of a Grafana request body
+const maxGrafanaBody = 1 << 20
+
+// grafanaQuery is the body of a JSON datasource /query request
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+	MaxDataPoints int `json:"maxDataPoints"`
+}
+
+// grafanaSeries is one time series: datapoints are [value, unix ms] pairs
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// registerGrafana serves the history database to Grafana's JSON datasource
+// plugin: GET / for the connection test, POST /search listing each window
+// recorded, and POST /query with each window's utilization over time.
+func registerGrafana(mux *http.ServeMux, historyPath string) {
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /search", func(w http.ResponseWriter, r *http.Request) {
+		series, err := windowSeries(historyPath, time.Time{}, time.Time{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		names := make([]string, 0, len(series))
+		for name := range series {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeJSON(w, names)
+	})
+	mux.HandleFunc("POST /query", func(w http.ResponseWriter, r *http.Request) {
+		var q grafanaQuery
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGrafanaBody)).Decode(&q); err != nil {
+			http.Error(w, "invalid query: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		series, err := windowSeries(historyPath, q.Range.From, q.Range.To)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result := make([]grafanaSeries, 0, len(q.Targets))
+		for _, t := range q.Targets {
+			if t.Target == "" {
+				continue
+			}
+			points := downsample(series[t.Target], q.MaxDataPoints)
+			if points == nil {
+				points = [][2]float64{}
+			}
+			result = append(result, grafanaSeries{Target: t.Target, Datapoints: points})
+		}
+		writeJSON(w, result)
+	})
+}
+
+// windowSeries reads each window's utilization from history between from
+// and to, as datapoints keyed by window name
+func windowSeries(historyPath string, from, to time.Time) (map[string][][2]float64, error) {
+	store, err := history.Open(historyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	snapshots, err := store.Query(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[string][][2]float64)
+	for _, snap := range snapshots {
+		var usage models.Usage
+		if err := json.Unmarshal(snap.Usage, &usage); err != nil {
+			continue
+		}
+		ms := float64(snap.Timestamp.UnixMilli())
+		for _, w := range usage.Windows() {
+			series[w.Name] = append(series[w.Name], [2]float64{w.Utilization, ms})
+		}
+	}
+	return series, nil
+}
+
+// downsample keeps at most max evenly spaced points, always including the
+// latest. A max of zero or less keeps every point.
+func downsample(points [][2]float64, max int) [][2]float64 {
+	if max <= 0 || len(points) <= max {
+		return points
+	}
+	if max == 1 {
+		return points[len(points)-1:]
+	}
+	out := make([][2]float64, 0, max)
+	step := float64(len(points)-1) / float64(max-1)
+	for i := range max {
+		out = append(out, points[int(float64(i)*step+0.5)])
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
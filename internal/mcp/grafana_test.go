@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func grafanaServer(t *testing.T) (*httptest.Server, time.Time) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := history.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, raw := range []string{
+		`{"five_hour":{"utilization":10},"seven_day":{"utilization":5}}`,
+		`{"five_hour":{"utilization":20}}`,
+		`{"five_hour":{"utilization":30},"seven_day":{"utilization":6}}`,
+	} {
+		var usage models.Usage
+		_ = json.Unmarshal([]byte(raw), &usage)
+		if err := store.Record(&usage, base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	srv := httptest.NewServer(NewServer(fakeUsage(t), WithPollInterval(0), WithHistoryPath(path)).Handler(""))
+	t.Cleanup(srv.Close)
+	return srv, base
+}
+
+func TestGrafanaSearch(t *testing.T) {
+	srv, _ := grafanaServer(t)
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET / = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL+"/search", "application/json", strings.NewReader(`{"target":""}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var names []string
+	if err := json.NewDecoder(resp.Body).Decode(&names); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != models.WindowFiveHour || names[1] != models.WindowSevenDay {
+		t.Errorf("search = %v", names)
+	}
+}
+
+func TestGrafanaQuery(t *testing.T) {
+	srv, base := grafanaServer(t)
+
+	body := `{
+		"range": {"from": "2025-01-01T12:30:00Z", "to": "2025-01-01T15:00:00Z"},
+		"targets": [{"target": "five_hour", "refId": "A"}, {"target": "seven_day", "refId": "B"}, {"target": "nope", "refId": "C"}],
+		"maxDataPoints": 500
+	}`
+	resp, err := http.Post(srv.URL+"/query", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var series []grafanaSeries
+	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(series) != 3 {
+		t.Fatalf("got %d series, want 3: %+v", len(series), series)
+	}
+	want := [][2]float64{
+		{20, float64(base.Add(time.Hour).UnixMilli())},
+		{30, float64(base.Add(2 * time.Hour).UnixMilli())},
+	}
+	if got := series[0].Datapoints; series[0].Target != "five_hour" || len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("five_hour = %+v, want %v", series[0], want)
+	}
+	if got := series[1].Datapoints; len(got) != 1 || got[0][0] != 6 {
+		t.Errorf("seven_day = %+v", series[1])
+	}
+	if series[2].Target != "nope" || len(series[2].Datapoints) != 0 {
+		t.Errorf("unknown target = %+v, want no datapoints", series[2])
+	}
+
+	resp, err = http.Post(srv.URL+"/query", "application/json", strings.NewReader(`not json`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid query = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	points := make([][2]float64, 10)
+	for i := range points {
+		points[i] = [2]float64{float64(i), float64(i)}
+	}
+
+	if got := downsample(points, 0); len(got) != 10 {
+		t.Errorf("max 0 kept %d points, want all", len(got))
+	}
+	if got := downsample(points, 1); len(got) != 1 || got[0][0] != 9 {
+		t.Errorf("max 1 = %v, want the latest point", got)
+	}
+	got := downsample(points, 4)
+	if len(got) != 4 || got[0][0] != 0 || got[3][0] != 9 {
+		t.Errorf("max 4 = %v, want 4 points from first to last", got)
+	}
+}
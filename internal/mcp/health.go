@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// readyWindow is how recently usage must have been fetched for the server
+// to be ready, or twice the poll interval if that's longer
+const readyWindow = 5 * time.Minute
+
+// health tracks fetches for the /healthz and /readyz endpoints
+type health struct {
+	// lastFetch is when usage was last fetched successfully, in Unix
+	// nanoseconds, or zero
+	lastFetch atomic.Int64
+	// interval is the current poll interval
+	interval atomic.Int64
+	// check is another way to be ready, e.g. a fresh cache entry
+	check func() bool
+}
+
+func newHealth(interval time.Duration, check func() bool) *health {
+	h := &health{check: check}
+	h.interval.Store(int64(interval))
+	return h
+}
+
+// track wraps getUsage to note its successful fetches
+func (h *health) track(getUsage UsageFunc) UsageFunc {
+	return func(ctx context.Context) (*models.Usage, error) {
+		usage, err := getUsage(ctx)
+		if err == nil {
+			h.lastFetch.Store(time.Now().UnixNano())
+		}
+		return usage, err
+	}
+}
+
+// ready reports why the server isn't ready, or nil if it is: usage was
+// fetched within the ready window, or the check passes
+func (h *health) ready() error {
+	window := max(readyWindow, 2*time.Duration(h.interval.Load()))
+	if last := h.lastFetch.Load(); last != 0 && time.Since(time.Unix(0, last)) <= window {
+		return nil
+	}
+	if h.check != nil && h.check() {
+		return nil
+	}
+	return fmt.Errorf("no usage fetched in the last %s", window)
+}
+
+// register adds the health endpoints to mux. They stay open without a
+// bearer token, so orchestrators can probe them.
+func (h *health) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := h.ready(); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ready")
+	})
+}
+
+// limitDuration times out requests after d, except to stream, the
+// long-lived SSE path
+func limitDuration(d time.Duration, stream string, next http.Handler) http.Handler {
+	limited := http.TimeoutHandler(next, d, "request exceeded the maximum duration")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == stream {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limited.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Lookbacks of the history tools, in hours
+const (
+	defaultLookbackHours = 24
+	maxLookbackHours     = 30 * 24
+)
+
+// registerHistoryTools adds the tools backed by the history database at path
+func registerHistoryTools(s *server.MCPServer, getUsage UsageFunc, path string) {
+	s.AddTool(mcp.NewTool("get_usage_history",
+		mcp.WithDescription("Get recorded utilization over time, one point per window per snapshot, oldest first"),
+		mcp.WithNumber("hours", mcp.Description("How far back to look, in hours"), mcp.DefaultNumber(defaultLookbackHours), mcp.Min(1), mcp.Max(maxLookbackHours)),
+		mcp.WithString("window", mcp.Description("Only this window, by name or short label, e.g. 'seven_day' or 'wk'")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), usageHistoryHandler(path))
+
+	s.AddTool(mcp.NewTool("forecast_limit",
+		mcp.WithDescription("Project when a usage window's limit will be hit at the recent pace, or how much of it will be used by the time it resets"),
+		mcp.WithString("window", mcp.Description("Window to forecast, by name or short label, e.g. 'seven_day' or '5h'"), mcp.DefaultString(models.WindowSevenDay)),
+		mcp.WithNumber("hours", mcp.Description("How much recent history sets the pace, in hours"), mcp.DefaultNumber(defaultLookbackHours), mcp.Min(1), mcp.Max(maxLookbackHours)),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), forecastHandler(getUsage, path))
+}
+
+func usageHistoryHandler(path string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		hours := lookbackHours(request)
+		snapshots, err := recentSnapshots(path, hours, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		window, _ := request.Params.Arguments["window"].(string)
+		window = format.WindowName(window)
+		rows := []history.Row{}
+		for _, row := range history.Rows(snapshots) {
+			if window == "" || row.Window == window {
+				rows = append(rows, row)
+			}
+		}
+		summary := fmt.Sprintf("%d point(s) from %d snapshot(s) over the last %.0fh", len(rows), len(snapshots), hours)
+		return structuredResult(summary, HistoryURI, rows)
+	}
+}
+
+func forecastHandler(getUsage UsageFunc, path string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		window, _ := request.Params.Arguments["window"].(string)
+		if window == "" {
+			window = models.WindowSevenDay
+		}
+		window = format.WindowName(window)
+
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+		w := usage.Window(window)
+		if w == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("usage response has no %s window", window)), nil
+		}
+
+		now := time.Now()
+		hours := lookbackHours(request)
+		snapshots, err := recentSnapshots(path, hours, now)
+		if err != nil {
+			return nil, err
+		}
+		f := history.NewForecast(window, w, snapshots, now)
+		return structuredResult(forecastSummary(f, hours), "usage://forecast/"+window, f)
+	}
+}
+
+// forecastSummary states a forecast in a sentence, e.g. "seven_day: 52%
+// used, rising 0.5 points/hour; on pace for 76% when it resets at ..."
+func forecastSummary(f history.Forecast, hours float64) string {
+	s := fmt.Sprintf("%s: %.0f%% used", f.Window, f.Utilization)
+	switch {
+	case f.PerHour == 0:
+		return s + fmt.Sprintf(", with no recorded use in the last %.0fh to project from", hours)
+	case f.LimitAt != nil:
+		return s + fmt.Sprintf(", rising %.2f points/hour; the limit is hit around %s, before it resets", f.PerHour, f.LimitAt.UTC().Format(time.RFC3339))
+	case f.AtReset != nil:
+		return s + fmt.Sprintf(", rising %.2f points/hour; on pace for %.0f%% when it resets at %s", f.PerHour, *f.AtReset, f.ResetsAt.UTC().Format(time.RFC3339))
+	}
+	return s + fmt.Sprintf(", rising %.2f points/hour", f.PerHour)
+}
+
+// lookbackHours returns the request's hours argument, clamped
+func lookbackHours(request mcp.CallToolRequest) float64 {
+	hours, ok := request.Params.Arguments["hours"].(float64)
+	if !ok {
+		return defaultLookbackHours
+	}
+	return min(max(hours, 1), maxLookbackHours)
+}
+
+// recentSnapshots reads the snapshots recorded in the hours before now
+func recentSnapshots(path string, hours float64, now time.Time) ([]history.Snapshot, error) {
+	store, err := history.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.Query(now.Add(-time.Duration(hours*float64(time.Hour))), time.Time{})
+}
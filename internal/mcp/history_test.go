@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// recordHistory writes snapshots of usage JSON an hour apart, ending an
+// hour ago, and returns the database path
+func recordHistory(t *testing.T, raws ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := history.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	start := time.Now().Add(-time.Duration(len(raws)) * time.Hour)
+	for i, raw := range raws {
+		var usage models.Usage
+		if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Record(&usage, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestUsageHistoryHandler(t *testing.T) {
+	path := recordHistory(t,
+		`{"five_hour":{"utilization":10},"seven_day":{"utilization":5}}`,
+		`{"five_hour":{"utilization":20},"seven_day":{"utilization":6}}`,
+	)
+
+	var rows []history.Row
+	resourceJSON(t, callTool(t, usageHistoryHandler(path), nil), &rows)
+	if len(rows) != 4 {
+		t.Errorf("got %d rows, want 4", len(rows))
+	}
+
+	result := callTool(t, usageHistoryHandler(path), map[string]any{"window": "5h"})
+	resourceJSON(t, result, &rows)
+	if len(rows) != 2 || rows[0].Window != models.WindowFiveHour || rows[1].Utilization != 20 {
+		t.Errorf("rows = %+v, want the two five_hour points", rows)
+	}
+
+	// Only the last snapshot is within the hour
+	resourceJSON(t, callTool(t, usageHistoryHandler(path), map[string]any{"window": "5h", "hours": 1.5}), &rows)
+	if len(rows) != 1 {
+		t.Errorf("got %d rows within 1.5h, want 1", len(rows))
+	}
+}
+
+func TestForecastHandler(t *testing.T) {
+	// seven_day goes 5 -> 11 in the two hours to now, 3 points an hour;
+	// usage is 23 and resets in 2030, so the limit comes first
+	path := recordHistory(t,
+		`{"seven_day":{"utilization":5}}`,
+		`{"seven_day":{"utilization":11}}`,
+	)
+
+	result := callTool(t, forecastHandler(fakeUsage(t), path), nil)
+	var f history.Forecast
+	resourceJSON(t, result, &f)
+	if f.Window != models.WindowSevenDay || f.Utilization != 23 {
+		t.Errorf("forecast = %+v, want seven_day at 23", f)
+	}
+	if f.PerHour < 2.9 || f.PerHour > 3.1 {
+		t.Errorf("PerHour = %v, want about 3", f.PerHour)
+	}
+	if f.LimitAt == nil || f.AtReset == nil || *f.AtReset != 100 {
+		t.Errorf("forecast = %+v, want the limit reached before reset", f)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "limit is hit around") {
+		t.Errorf("summary = %q", text)
+	}
+}
+
+func TestForecastHandlerNoHistory(t *testing.T) {
+	path := recordHistory(t)
+	result := callTool(t, forecastHandler(fakeUsage(t), path), map[string]any{"window": "5h"})
+	var f history.Forecast
+	resourceJSON(t, result, &f)
+	if f.PerHour != 0 || f.LimitAt != nil || f.AtReset != nil {
+		t.Errorf("forecast = %+v, want no projection", f)
+	}
+}
+
+func TestForecastHandlerMissingWindow(t *testing.T) {
+	result := callTool(t, forecastHandler(fakeUsage(t), recordHistory(t)), map[string]any{"window": "sonnet"})
+	if !result.IsError {
+		t.Error("expected an error result for a missing window")
+	}
+}
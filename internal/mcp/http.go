@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultListenAddr is the default address for the HTTP transport
+const DefaultListenAddr = "localhost:8765"
+
+// shutdownTimeout bounds how long in-flight requests get to finish on shutdown
+const shutdownTimeout = 5 * time.Second
+
+// Handler returns an http.Handler serving MCP over SSE (GET /sse, POST /message),
+// health checks (GET /healthz, /readyz), and with a history database, Grafana
+// JSON datasource endpoints (/, /search, /query). If authToken is non-empty,
+// every request but the health checks must carry
+// "Authorization: Bearer <authToken>".
+func (s *Server) Handler(authToken string) http.Handler {
+	sse := server.NewSSEServer(s.mcp, server.WithKeepAlive(true))
+	mux := http.NewServeMux()
+	mux.Handle(sse.CompleteSsePath(), sse)
+	mux.Handle(sse.CompleteMessagePath(), sse)
+	if s.opts.historyPath != "" {
+		registerGrafana(mux, s.opts.historyPath)
+	}
+
+	var h http.Handler = mux
+	if authToken != "" {
+		h = requireBearer(authToken, mux)
+	}
+	root := http.NewServeMux()
+	s.health.register(root)
+	root.Handle("/", h)
+	if s.opts.maxDuration > 0 {
+		return limitDuration(s.opts.maxDuration, sse.CompleteSsePath(), root)
+	}
+	return root
+}
+
+// ListenAndServe serves MCP over HTTP on addr until ctx is cancelled,
+// polling for usage changes in the background
+func (s *Server) ListenAndServe(ctx context.Context, addr, authToken string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.serveListener(ctx, ln, authToken)
+}
+
+func (s *Server) serveListener(ctx context.Context, ln net.Listener, authToken string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go s.poller.run(ctx, s.opts.pollInterval)
+
+	srv := &http.Server{
+		Handler:           s.Handler(authToken),
+		ReadHeaderTimeout: 10 * time.Second,
+		// SSE streams end when their request context does, so derive it from ctx
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// requireBearer rejects requests without the expected bearer token
+func requireBearer(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
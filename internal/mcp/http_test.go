@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+func TestHandlerRequiresBearerToken(t *testing.T) {
+	srv := NewServer(fakeUsage(t), WithPollInterval(0))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.serveListener(ctx, ln, "secret") }()
+
+	url := "http://" + ln.Addr().String() + "/sse"
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET without token: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET with token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status with token = %d, want 200", resp.StatusCode)
+	}
+
+	// The first SSE event announces the message endpoint
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "event: endpoint") {
+		t.Errorf("first SSE line = %q, %v", line, err)
+	}
+
+	// Cancelling shuts down cleanly even with an open stream
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve returned %v", err)
+		}
+	case <-time.After(shutdownTimeout + time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	srv := NewServer(fakeUsage(t), WithPollInterval(0))
+	h := srv.Handler("secret")
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// Probes need no token
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", code)
+	}
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before any fetch = %d, want 503", code)
+	}
+
+	if _, err := srv.getUsage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz after a fetch = %d, want 200", code)
+	}
+}
+
+func TestReadyCheck(t *testing.T) {
+	failing := func(ctx context.Context) (*models.Usage, error) {
+		return nil, errors.New("unavailable")
+	}
+	cached := false
+	srv := NewServer(failing, WithPollInterval(0), WithReadyCheck(func() bool { return cached }))
+	_, _ = srv.getUsage(context.Background())
+	if err := srv.health.ready(); err == nil {
+		t.Error("ready after a failed fetch")
+	}
+	cached = true
+	if err := srv.health.ready(); err != nil {
+		t.Errorf("not ready with a passing check: %v", err)
+	}
+}
+
+func TestLimitDuration(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	h := limitDuration(10*time.Millisecond, "/sse", slow)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/query", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("slow request = %d, want 503", rec.Code)
+	}
+
+	// The SSE stream is exempt
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	h.ServeHTTP(rec, req.WithContext(ctx))
+	if rec.Code != http.StatusOK {
+		t.Errorf("stream = %d, want 200", rec.Code)
+	}
+}
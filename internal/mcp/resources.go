@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource URIs
+const (
+	CurrentURI = "usage://current"
+	HistoryURI = "usage://history"
+)
+
+// historyWindow is how far back usage://history reaches
+const historyWindow = 24 * time.Hour
+
+// registerResources adds usage resources to the server.
+// usage://history is only registered when a history database is configured.
+func registerResources(s *server.MCPServer, getUsage UsageFunc, historyPath string) {
+	s.AddResource(mcp.NewResource(CurrentURI, "Current usage",
+		mcp.WithResourceDescription("Current Claude.ai usage for your Pro/Max subscription"),
+		mcp.WithMIMEType("application/json"),
+	), currentHandler(getUsage))
+
+	if historyPath != "" {
+		s.AddResource(mcp.NewResource(HistoryURI, "Usage history",
+			mcp.WithResourceDescription("Usage snapshots recorded over the last 24 hours"),
+			mcp.WithMIMEType("application/json"),
+		), historyHandler(historyPath))
+	}
+}
+
+func currentHandler(getUsage UsageFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+
+		json, err := usage.ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize usage: %w", err)
+		}
+
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      CurrentURI,
+			MIMEType: "application/json",
+			Text:     json,
+		}}, nil
+	}
+}
+
+func historyHandler(path string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		store, err := history.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer store.Close()
+
+		snapshots, err := store.Query(time.Now().Add(-historyWindow), time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		if snapshots == nil {
+			snapshots = []history.Snapshot{}
+		}
+
+		data, err := json.MarshalIndent(snapshots, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize history: %w", err)
+		}
+
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      HistoryURI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}}, nil
+	}
+}
+
+// poller periodically fetches usage and notifies clients when it changes.
+// mcp-go does not route resources/subscribe, so updates are sent to every
+// initialized session rather than to explicit subscribers.
+type poller struct {
+	server      *server.MCPServer
+	getUsage    UsageFunc
+	historyPath string
+	// record is whether polled usage is written to historyPath
+	record bool
+	last   json.RawMessage
+	// intervals holds a new interval for run, set by setInterval
+	intervals chan time.Duration
+}
+
+func newPoller(s *server.MCPServer, getUsage UsageFunc, historyPath string) *poller {
+	return &poller{server: s, getUsage: getUsage, historyPath: historyPath, record: true, intervals: make(chan time.Duration, 1)}
+}
+
+// run polls every interval until ctx is cancelled. A zero interval pauses
+// polling until setInterval sets another.
+func (p *poller) run(ctx context.Context, interval time.Duration) {
+	for {
+		// A nil timer channel never fires, leaving polling paused
+		var timer *time.Timer
+		var tick <-chan time.Time
+		if interval > 0 {
+			if err := p.poll(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("usage poll failed", "error", err)
+			}
+			timer = time.NewTimer(interval)
+			tick = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-tick:
+		case interval = <-p.intervals:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// setInterval changes run's interval, polling at once if it's positive.
+// Only the latest interval set before run picks it up is kept.
+func (p *poller) setInterval(d time.Duration) {
+	select {
+	case <-p.intervals:
+	default:
+	}
+	p.intervals <- d
+}
+
+// poll fetches usage once, recording it to history and notifying clients
+// of each resource whose content changed
+func (p *poller) poll(ctx context.Context) error {
+	usage, err := p.getUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	if p.historyPath != "" {
+		if p.record {
+			if err := p.recordHistory(usage); err != nil {
+				return err
+			}
+		}
+		p.notify(HistoryURI)
+	}
+
+	if !bytes.Equal(p.last, usage.Raw) {
+		p.last = usage.Raw
+		p.notify(CurrentURI)
+	}
+	return nil
+}
+
+func (p *poller) recordHistory(usage *models.Usage) error {
+	store, err := history.Open(p.historyPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	return store.Record(usage, time.Now())
+}
+
+func (p *poller) notify(uri string) {
+	p.server.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+}
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeSession is an initialized client session that buffers notifications
+type fakeSession struct {
+	ch chan mcp.JSONRPCNotification
+}
+
+func (s *fakeSession) Initialize()                                         {}
+func (s *fakeSession) Initialized() bool                                   { return true }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.ch }
+func (s *fakeSession) SessionID() string                                   { return "test" }
+
+// updatedURIs drains the session's resource-updated notifications
+func (s *fakeSession) updatedURIs() []string {
+	var uris []string
+	for {
+		select {
+		case n := <-s.ch:
+			if n.Method == mcp.MethodNotificationResourceUpdated {
+				uris = append(uris, n.Params.AdditionalFields["uri"].(string))
+			}
+		default:
+			return uris
+		}
+	}
+}
+
+func TestCurrentHandler(t *testing.T) {
+	contents, err := currentHandler(fakeUsage(t))(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("currentHandler: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.URI != CurrentURI || text.MIMEType != "application/json" {
+		t.Errorf("unexpected contents: %+v", text)
+	}
+
+	var usage models.Usage
+	if err := json.Unmarshal([]byte(text.Text), &usage); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if usage.FiveHour == nil || usage.FiveHour.Utilization != 45 {
+		t.Errorf("unexpected usage: %s", text.Text)
+	}
+}
+
+func TestPollNotifiesAndRecordsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	srv := NewServer(fakeUsage(t), WithHistoryPath(path))
+
+	session := &fakeSession{ch: make(chan mcp.JSONRPCNotification, 10)}
+	if err := srv.MCPServer().RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+
+	if err := srv.poller.poll(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	got := session.updatedURIs()
+	if len(got) != 2 || got[0] != HistoryURI || got[1] != CurrentURI {
+		t.Errorf("first poll notified %v, want [%s %s]", got, HistoryURI, CurrentURI)
+	}
+
+	// Unchanged usage doesn't re-notify usage://current
+	if err := srv.poller.poll(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	got = session.updatedURIs()
+	if len(got) != 1 || got[0] != HistoryURI {
+		t.Errorf("second poll notified %v, want [%s]", got, HistoryURI)
+	}
+
+	contents, err := historyHandler(path)(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("historyHandler: %v", err)
+	}
+	var snapshots []history.Snapshot
+	if err := json.Unmarshal([]byte(contents[0].(mcp.TextResourceContents).Text), &snapshots); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("expected 2 recorded snapshots, got %d", len(snapshots))
+	}
+}
+
+func TestPollUsageWithoutRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	polled := 0
+	poll := fakeUsage(t)
+	srv := NewServer(fakeUsage(t), WithHistoryPath(path), WithRecording(false),
+		WithPollUsage(func(ctx context.Context) (*models.Usage, error) {
+			polled++
+			return poll(ctx)
+		}))
+
+	if err := srv.poller.poll(context.Background()); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if polled != 1 {
+		t.Errorf("poll usage called %d times, want 1", polled)
+	}
+
+	contents, err := historyHandler(path)(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("historyHandler: %v", err)
+	}
+	if text := contents[0].(mcp.TextResourceContents).Text; text != "[]" {
+		t.Errorf("history = %s, want nothing recorded", text)
+	}
+}
+
+func TestSetPollInterval(t *testing.T) {
+	polls := make(chan struct{}, 10)
+	getUsage := fakeUsage(t)
+	srv := NewServer(func(ctx context.Context) (*models.Usage, error) {
+		polls <- struct{}{}
+		return getUsage(ctx)
+	}, WithPollInterval(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.poller.run(ctx, srv.opts.pollInterval)
+		close(done)
+	}()
+
+	select {
+	case <-polls:
+		t.Fatal("polled with a zero interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	srv.SetPollInterval(10 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-polls:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("poll %d didn't happen after SetPollInterval", i+1)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("run didn't stop when its context was cancelled")
+	}
+}
+
+func TestHistoryHandlerEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	contents, err := historyHandler(path)(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("historyHandler: %v", err)
+	}
+	if text := contents[0].(mcp.TextResourceContents).Text; text != "[]" {
+		t.Errorf("expected empty array, got %s", text)
+	}
+}
@@ -2,47 +2,145 @@ package mcp
 
 import (
 	"context"
-	"fmt"
+	"os"
+	"time"
 
-	"github.com/benjaminabbitt/claude-limits/internal/api"
 	"github.com/benjaminabbitt/claude-limits/internal/version"
 
-	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// Serve starts the MCP server on stdio.
-// The mcp-go library handles SIGTERM/SIGINT for graceful shutdown.
-func Serve(accessToken string) error {
+// DefaultPollInterval is how often the server refreshes usage for resource subscribers
+const DefaultPollInterval = 60 * time.Second
+
+// Server is an MCP server exposing usage tools and resources
+type Server struct {
+	mcp      *server.MCPServer
+	getUsage UsageFunc
+	opts     options
+	poller   *poller
+	health   *health
+}
+
+type options struct {
+	pollInterval time.Duration
+	historyPath  string
+	pollUsage    UsageFunc
+	noRecord     bool
+	maxDuration  time.Duration
+	readyCheck   func() bool
+}
+
+// Option configures a Server
+type Option func(*options)
+
+// WithPollInterval sets how often usage is refreshed to drive resource-updated
+// notifications. Zero disables polling.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// WithHistoryPath sets the history database backing usage://history and
+// the history and forecast tools. Polled usage is also recorded there.
+// Empty disables them.
+func WithHistoryPath(path string) Option {
+	return func(o *options) {
+		o.historyPath = path
+	}
+}
+
+// WithPollUsage sets how the poller fetches usage, e.g. bypassing a cache
+// the tools read through. By default it uses the server's UsageFunc.
+func WithPollUsage(getUsage UsageFunc) Option {
+	return func(o *options) {
+		o.pollUsage = getUsage
+	}
+}
+
+// WithRecording sets whether polled usage is recorded to the history
+// database. Disable it when the poll UsageFunc records history itself.
+func WithRecording(record bool) Option {
+	return func(o *options) {
+		o.noRecord = !record
+	}
+}
+
+// WithMaxRequestDuration times out HTTP requests after d, except the SSE
+// stream itself. Zero disables the limit.
+func WithMaxRequestDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.maxDuration = d
+	}
+}
+
+// WithReadyCheck reports the server ready when check passes, e.g. on a
+// fresh cache entry, even if it hasn't fetched usage recently itself
+func WithReadyCheck(check func() bool) Option {
+	return func(o *options) {
+		o.readyCheck = check
+	}
+}
+
+// NewServer creates an MCP server exposing usage tools backed by getUsage
+func NewServer(getUsage UsageFunc, opts ...Option) *Server {
+	o := options{pollInterval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	health := newHealth(o.pollInterval, o.readyCheck)
+	getUsage = health.track(getUsage)
+
 	s := server.NewMCPServer(
 		"claude-limits",
 		version.Version,
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 	)
 
-	// Define the get_usage tool
-	usageTool := mcp.NewTool("get_usage",
-		mcp.WithDescription("Get current Claude.ai usage for your Pro/Max subscription"),
-	)
+	registerTools(s, getUsage)
+	registerResources(s, getUsage, o.historyPath)
+	if o.historyPath != "" {
+		registerHistoryTools(s, getUsage, o.historyPath)
+	}
+
+	pollUsage := getUsage
+	if o.pollUsage != nil {
+		pollUsage = health.track(o.pollUsage)
+	}
+	p := newPoller(s, pollUsage, o.historyPath)
+	p.record = !o.noRecord
+
+	return &Server{
+		mcp:      s,
+		getUsage: getUsage,
+		opts:     o,
+		poller:   p,
+		health:   health,
+	}
+}
 
-	// Create API client
-	client := api.NewClient(accessToken)
+// SetPollInterval changes how often usage is polled while serving, e.g. after
+// the config is reloaded. Zero pauses polling.
+func (s *Server) SetPollInterval(d time.Duration) {
+	s.health.interval.Store(int64(d))
+	s.poller.setInterval(d)
+}
 
-	// Add the tool with its handler
-	s.AddTool(usageTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		usage, err := client.GetUsage()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get usage: %w", err)
-		}
+// MCPServer returns the underlying mcp-go server
+func (s *Server) MCPServer() *server.MCPServer {
+	return s.mcp
+}
 
-		json, err := usage.ToJSON()
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize usage: %w", err)
-		}
+// ServeStdio serves on stdin/stdout until ctx is cancelled or stdin closes,
+// polling for usage changes in the background
+func (s *Server) ServeStdio(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		return mcp.NewToolResultText(json), nil
-	})
+	go s.poller.run(ctx, s.opts.pollInterval)
 
-	// Start the server on stdio (library handles signal-based shutdown)
-	return server.ServeStdio(s)
+	return server.NewStdioServer(s.mcp).Listen(ctx, os.Stdin, os.Stdout)
 }
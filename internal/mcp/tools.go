@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/fuzzy"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// UsageFunc fetches current usage
+type UsageFunc func(ctx context.Context) (*models.Usage, error)
+
+// windowResult is the structured form of a single rate-limit window
+type windowResult struct {
+	Window          string     `json:"window"`
+	Utilization     float64    `json:"utilization"`
+	Remaining       float64    `json:"remaining"`
+	ResetsAt        *time.Time `json:"resets_at,omitempty"`
+	ResetsInSeconds int64      `json:"resets_in_seconds,omitempty"`
+}
+
+// resetResult is the structured form of a window's reset time
+type resetResult struct {
+	Window          string    `json:"window"`
+	ResetsAt        time.Time `json:"resets_at"`
+	ResetsInSeconds int64     `json:"resets_in_seconds"`
+}
+
+// queryResult is the structured result of a fuzzy field query
+type queryResult struct {
+	Query string      `json:"query"`
+	Field string      `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// registerTools adds all usage tools to the server
+func registerTools(s *server.MCPServer, getUsage UsageFunc) {
+	s.AddTool(mcp.NewTool("get_usage",
+		mcp.WithDescription("Get current Claude.ai usage for your Pro/Max subscription"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), usageHandler(getUsage))
+
+	s.AddTool(mcp.NewTool("get_five_hour",
+		mcp.WithDescription("Get the rolling 5-hour usage window: utilization, remaining percentage, and reset time"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), windowHandler(getUsage, models.WindowFiveHour))
+
+	s.AddTool(mcp.NewTool("get_weekly",
+		mcp.WithDescription("Get the 7-day usage window: utilization, remaining percentage, and reset time"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), windowHandler(getUsage, models.WindowSevenDay))
+
+	s.AddTool(mcp.NewTool("get_reset_times",
+		mcp.WithDescription("Get when each usage window resets"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), resetTimesHandler(getUsage))
+
+	s.AddTool(mcp.NewTool("query_usage",
+		mcp.WithDescription("Look up a single usage field by fuzzy name, e.g. 'five_util' or 'opus_reset'"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Field name or fuzzy query")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), queryHandler(getUsage))
+}
+
+func usageHandler(getUsage UsageFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+
+		json, err := usage.ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize usage: %w", err)
+		}
+
+		return mcp.NewToolResultText(json), nil
+	}
+}
+
+func windowHandler(getUsage UsageFunc, name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+
+		w := usage.Window(name)
+		if w == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("usage response has no %s window", name)), nil
+		}
+
+		result := windowResult{
+			Window:          name,
+			Utilization:     w.Utilization,
+			Remaining:       w.Remaining(),
+			ResetsAt:        w.ResetsAt,
+			ResetsInSeconds: int64(w.ResetsIn(time.Now()).Seconds()),
+		}
+		return structuredResult(fmt.Sprintf("%s: %.0f%% used", name, w.Utilization), "usage://"+name, result)
+	}
+}
+
+func resetTimesHandler(getUsage UsageFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+
+		now := time.Now()
+		resets := []resetResult{}
+		for _, w := range usage.Windows() {
+			if w.ResetsAt == nil {
+				continue
+			}
+			resets = append(resets, resetResult{
+				Window:          w.Name,
+				ResetsAt:        *w.ResetsAt,
+				ResetsInSeconds: int64(w.ResetsIn(now).Seconds()),
+			})
+		}
+		return structuredResult(fmt.Sprintf("%d window reset time(s)", len(resets)), "usage://reset-times", resets)
+	}
+}
+
+func queryHandler(getUsage UsageFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query, _ := request.Params.Arguments["query"].(string)
+		if query == "" {
+			return mcp.NewToolResultError("query is required"), nil
+		}
+
+		usage, err := getUsage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+
+		var data map[string]interface{}
+		if err := json.Unmarshal(usage.Raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to parse usage data: %w", err)
+		}
+
+		match, err := fuzzy.FindBestMatch(fuzzy.FlattenData(data, ""), query)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result := queryResult{Query: query, Field: match.Path, Value: match.Value}
+		return structuredResult(fmt.Sprintf("%s = %v", match.Path, match.Value), "usage://query", result)
+	}
+}
+
+// structuredResult returns a short text summary plus the result as an
+// embedded application/json resource, so clients can parse it reliably
+func structuredResult(summary, uri string, v interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result: %w", err)
+	}
+	return mcp.NewToolResultResource(summary, mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}), nil
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const usageJSON = `{
+  "five_hour": {"utilization": 45, "resets_at": "2030-01-01T14:30:00Z"},
+  "seven_day": {"utilization": 23, "resets_at": "2030-01-05T08:00:00Z"},
+  "seven_day_opus": {"utilization": 10, "resets_at": null}
+}`
+
+func fakeUsage(t *testing.T) UsageFunc {
+	t.Helper()
+	var usage models.Usage
+	if err := json.Unmarshal([]byte(usageJSON), &usage); err != nil {
+		t.Fatalf("unmarshal usage: %v", err)
+	}
+	return func(ctx context.Context) (*models.Usage, error) {
+		return &usage, nil
+	}
+}
+
+func callTool(t *testing.T, h server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	result, err := h(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return result
+}
+
+// resourceJSON decodes the embedded application/json resource of a result
+func resourceJSON(t *testing.T, result *mcp.CallToolResult, v any) {
+	t.Helper()
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content items, got %d", len(result.Content))
+	}
+	embedded, ok := result.Content[1].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("content[1] is %T, want EmbeddedResource", result.Content[1])
+	}
+	contents, ok := embedded.Resource.(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("resource is %T, want TextResourceContents", embedded.Resource)
+	}
+	if contents.MIMEType != "application/json" {
+		t.Errorf("MIMEType = %q, want application/json", contents.MIMEType)
+	}
+	if err := json.Unmarshal([]byte(contents.Text), v); err != nil {
+		t.Fatalf("unmarshal resource: %v", err)
+	}
+}
+
+func TestWindowHandler(t *testing.T) {
+	result := callTool(t, windowHandler(fakeUsage(t), models.WindowFiveHour), nil)
+
+	var got windowResult
+	resourceJSON(t, result, &got)
+	if got.Window != models.WindowFiveHour || got.Utilization != 45 || got.Remaining != 55 {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	if got.ResetsAt == nil || got.ResetsInSeconds <= 0 {
+		t.Errorf("expected future reset time, got %+v", got)
+	}
+}
+
+func TestWindowHandlerMissingWindow(t *testing.T) {
+	result := callTool(t, windowHandler(fakeUsage(t), models.WindowSevenDaySonnet), nil)
+	if !result.IsError {
+		t.Error("expected tool error for missing window")
+	}
+}
+
+func TestResetTimesHandler(t *testing.T) {
+	result := callTool(t, resetTimesHandler(fakeUsage(t)), nil)
+
+	var got []resetResult
+	resourceJSON(t, result, &got)
+	// seven_day_opus has no reset time and is skipped
+	if len(got) != 2 {
+		t.Fatalf("expected 2 reset times, got %+v", got)
+	}
+	if got[0].Window != models.WindowFiveHour || got[1].Window != models.WindowSevenDay {
+		t.Errorf("unexpected windows: %+v", got)
+	}
+}
+
+func TestQueryHandler(t *testing.T) {
+	result := callTool(t, queryHandler(fakeUsage(t)), map[string]any{"query": "opus_util"})
+
+	var got queryResult
+	resourceJSON(t, result, &got)
+	if got.Field != "seven_day_opus_utilization" || got.Value != float64(10) {
+		t.Errorf("unexpected result: %+v", got)
+	}
+}
+
+func TestQueryHandlerMissingQuery(t *testing.T) {
+	result := callTool(t, queryHandler(fakeUsage(t)), nil)
+	if !result.IsError {
+		t.Error("expected tool error for missing query")
+	}
+}
+
+func TestUsageHandlerFetchError(t *testing.T) {
+	failing := func(ctx context.Context) (*models.Usage, error) {
+		return nil, errors.New("boom")
+	}
+	var req mcp.CallToolRequest
+	_, err := usageHandler(failing)(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected wrapped fetch error, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+// AccountProfile is the authenticated account, as reported by the OAuth
+// profile endpoint
+type AccountProfile struct {
+	Account struct {
+		UUID         string `json:"uuid"`
+		Email        string `json:"email_address"`
+		FullName     string `json:"full_name"`
+		DisplayName  string `json:"display_name"`
+		HasClaudeMax bool   `json:"has_claude_max"`
+		HasClaudePro bool   `json:"has_claude_pro"`
+	} `json:"account"`
+	Organization struct {
+		UUID          string `json:"uuid"`
+		Name          string `json:"name"`
+		Type          string `json:"organization_type"`
+		BillingType   string `json:"billing_type"`
+		RateLimitTier string `json:"rate_limit_tier"`
+	} `json:"organization"`
+}
+
+// Name returns the account's display name, falling back to its full name
+func (p *AccountProfile) Name() string {
+	if p.Account.DisplayName != "" {
+		return p.Account.DisplayName
+	}
+	return p.Account.FullName
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RateLimit is one limit reported in anthropic-ratelimit-* response headers,
+// such as "requests" or "unified-5h". Headers are sent by some deployments
+// only, and may describe limits the JSON body doesn't.
+type RateLimit struct {
+	Name      string     `json:"name"`
+	Limit     *int64     `json:"limit,omitempty"`
+	Remaining *int64     `json:"remaining,omitempty"`
+	Reset     *time.Time `json:"reset,omitempty"`
+	// Other holds the limit's remaining headers by field, e.g. "status" or
+	// "utilization"
+	Other map[string]string `json:"other,omitempty"`
+}
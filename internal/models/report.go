@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UsageReportBucket is one time bucket of the Admin API messages usage report
+type UsageReportBucket struct {
+	StartingAt time.Time           `json:"starting_at"`
+	EndingAt   time.Time           `json:"ending_at"`
+	Results    []UsageReportResult `json:"results"`
+}
+
+// UsageReportResult is the token usage of one group in a bucket. The
+// grouping fields are set only when the report is grouped by them.
+type UsageReportResult struct {
+	UncachedInputTokens  int64 `json:"uncached_input_tokens"`
+	CacheReadInputTokens int64 `json:"cache_read_input_tokens"`
+	CacheCreation        struct {
+		Ephemeral1hInputTokens int64 `json:"ephemeral_1h_input_tokens"`
+		Ephemeral5mInputTokens int64 `json:"ephemeral_5m_input_tokens"`
+	} `json:"cache_creation"`
+	OutputTokens int64 `json:"output_tokens"`
+
+	APIKeyID    *string `json:"api_key_id"`
+	WorkspaceID *string `json:"workspace_id"`
+	Model       *string `json:"model"`
+	ServiceTier *string `json:"service_tier"`
+}
+
+// CacheCreationInputTokens returns the tokens written to the cache, for
+// either cache lifetime
+func (r *UsageReportResult) CacheCreationInputTokens() int64 {
+	return r.CacheCreation.Ephemeral1hInputTokens + r.CacheCreation.Ephemeral5mInputTokens
+}
+
+// CostReportBucket is one day of the Admin API cost report
+type CostReportBucket struct {
+	StartingAt time.Time          `json:"starting_at"`
+	EndingAt   time.Time          `json:"ending_at"`
+	Results    []CostReportResult `json:"results"`
+}
+
+// CostReportResult is the cost of one group in a bucket. The grouping
+// fields are set only when the report is grouped by them.
+type CostReportResult struct {
+	Currency string `json:"currency"`
+	Amount   Amount `json:"amount"`
+
+	WorkspaceID *string `json:"workspace_id"`
+	Description *string `json:"description"`
+	CostType    *string `json:"cost_type"`
+	Model       *string `json:"model"`
+	TokenType   *string `json:"token_type"`
+}
+
+// ClaudeCodeRecord is one user's or API key's Claude Code usage on one day,
+// from the Admin API Claude Code usage report
+type ClaudeCodeRecord struct {
+	Date  time.Time `json:"date"`
+	Actor struct {
+		Type       string `json:"type"`
+		Email      string `json:"email_address"`
+		APIKeyName string `json:"api_key_name"`
+	} `json:"actor"`
+	ModelBreakdown []ClaudeCodeModelUsage `json:"model_breakdown"`
+}
+
+// ActorName returns the user's email, or the API key's name
+func (r *ClaudeCodeRecord) ActorName() string {
+	if r.Actor.Email != "" {
+		return r.Actor.Email
+	}
+	return r.Actor.APIKeyName
+}
+
+// ClaudeCodeModelUsage is the usage of one model in a ClaudeCodeRecord
+type ClaudeCodeModelUsage struct {
+	Model  string `json:"model"`
+	Tokens struct {
+		Input         int64 `json:"input"`
+		Output        int64 `json:"output"`
+		CacheRead     int64 `json:"cache_read"`
+		CacheCreation int64 `json:"cache_creation"`
+	} `json:"tokens"`
+	EstimatedCost struct {
+		Currency string `json:"currency"`
+		Amount   Amount `json:"amount"`
+	} `json:"estimated_cost"`
+}
+
+// Amount is money in the currency's smallest unit, such as cents. The API
+// sends it as a decimal string or a number.
+type Amount float64
+
+// UnmarshalJSON accepts "12.5" as well as 12.5
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	if n == "" {
+		*a = 0
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	*a = Amount(f)
+	return nil
+}
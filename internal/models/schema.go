@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// FieldExtraUsage describes paid usage beyond the subscription's limits
+const FieldExtraUsage = "extra_usage"
+
+// windowFields are the fields of a window object
+var windowFields = []string{"utilization", "resets_at"}
+
+// knownFields are the top-level response fields claude-limits understands,
+// with the fields understood within each
+var knownFields = map[string][]string{
+	WindowFiveHour:          windowFields,
+	WindowSevenDay:          windowFields,
+	WindowSevenDayOpus:      windowFields,
+	WindowSevenDaySonnet:    windowFields,
+	WindowSevenDayOAuthApps: windowFields,
+	FieldExtraUsage:         {"is_enabled", "monthly_limit", "used_credits", "utilization"},
+}
+
+// IsKnownField reports whether name is a top-level response field
+// claude-limits understands
+func IsKnownField(name string) bool {
+	_, ok := knownFields[name]
+	return ok
+}
+
+// UnknownFields returns the response fields claude-limits doesn't
+// recognize, sorted: unknown top-level fields, unknown fields of known
+// ones as paths like "five_hour.limit", and known windows whose value
+// isn't a window. Null values are ignored.
+func (u *Usage) UnknownFields() []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(u.Raw, &fields); err != nil {
+		return nil
+	}
+
+	var unknown []string
+	for name, raw := range fields {
+		if isNull(raw) {
+			continue
+		}
+		known, ok := knownFields[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, ok := u.windowPtrs()[name]; ok && u.Window(name) == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		for field, value := range object {
+			if !slices.Contains(known, field) && !isNull(value) {
+				unknown = append(unknown, name+"."+field)
+			}
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+func isNull(raw json.RawMessage) bool {
+	return string(raw) == "null"
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Select returns a copy of u pruned to the fields at include paths, minus
+// those at exclude paths. Paths are dot-separated field names, e.g.
+// "five_hour" or "five_hour.resets_at". An empty include keeps every field,
+// and paths that don't exist are ignored, so a field the API drops doesn't
+// break a script.
+func (u *Usage) Select(include, exclude []string) (*Usage, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return u, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(u.Raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse usage data: %w", err)
+	}
+
+	if len(include) > 0 {
+		kept := make(map[string]interface{})
+		for _, path := range include {
+			copyPath(data, kept, splitPath(path))
+		}
+		data = kept
+	}
+	for _, path := range exclude {
+		deletePath(data, splitPath(path))
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode usage data: %w", err)
+	}
+	var selected Usage
+	if err := json.Unmarshal(raw, &selected); err != nil {
+		return nil, err
+	}
+	// Rate limits aren't fields of the body, so only an include drops them
+	if len(include) == 0 {
+		selected.RateLimits = u.RateLimits
+	}
+	return &selected, nil
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimSpace(path), ".")
+}
+
+// copyPath copies the value at path in src into dst, creating the objects
+// along the way
+func copyPath(src, dst map[string]interface{}, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	child, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	next, ok := dst[path[0]].(map[string]interface{})
+	if !ok {
+		next = make(map[string]interface{})
+	}
+	copyPath(child, next, path[1:])
+	if len(next) > 0 {
+		dst[path[0]] = next
+	}
+}
+
+// deletePath removes the value at path
+func deletePath(data map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(data, path[0])
+		return
+	}
+	if child, ok := data[path[0]].(map[string]interface{}); ok {
+		deletePath(child, path[1:])
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	raw := `{"five_hour":{"utilization":45,"resets_at":null},"seven_day":{"utilization":23,"resets_at":null},"extra":{"a":1}}`
+	var usage Usage
+	if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             string
+	}{
+		{"nothing", nil, nil, raw},
+		{"whole window", []string{"five_hour"}, nil, `{"five_hour":{"utilization":45,"resets_at":null}}`},
+		{"nested field", []string{"five_hour.utilization", "seven_day.utilization"}, nil, `{"five_hour":{"utilization":45},"seven_day":{"utilization":23}}`},
+		{"missing field", []string{"five_hour", "nope", "extra.nope.deeper"}, nil, `{"five_hour":{"utilization":45,"resets_at":null}}`},
+		{"exclude", nil, []string{"extra", "seven_day.resets_at"}, `{"five_hour":{"utilization":45,"resets_at":null},"seven_day":{"utilization":23}}`},
+		{"both", []string{"five_hour", "seven_day"}, []string{"five_hour"}, `{"seven_day":{"utilization":23,"resets_at":null}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := usage.Select(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			var gotData, wantData interface{}
+			_ = json.Unmarshal(got.Raw, &gotData)
+			_ = json.Unmarshal([]byte(tt.want), &wantData)
+			if !reflect.DeepEqual(gotData, wantData) {
+				t.Errorf("Select(%v, %v) = %s, want %s", tt.include, tt.exclude, got.Raw, tt.want)
+			}
+		})
+	}
+
+	// Typed windows follow the selection
+	selected, _ := usage.Select([]string{"seven_day"}, nil)
+	if selected.FiveHour != nil || selected.SevenDay == nil {
+		t.Errorf("typed windows = %+v, want only SevenDay", selected.Windows())
+	}
+}
@@ -2,22 +2,140 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 )
 
+// Window names as they appear in the API response
+const (
+	WindowFiveHour          = "five_hour"
+	WindowSevenDay          = "seven_day"
+	WindowSevenDayOpus      = "seven_day_opus"
+	WindowSevenDaySonnet    = "seven_day_sonnet"
+	WindowSevenDayOAuthApps = "seven_day_oauth_apps"
+)
+
+// Window is a single rate-limit window (e.g., the rolling 5-hour limit)
+type Window struct {
+	// Utilization is the percentage of the window's limit consumed (0-100)
+	Utilization float64 `json:"utilization"`
+	// ResetsAt is when the window resets, if known
+	ResetsAt *time.Time `json:"resets_at"`
+}
+
+// Remaining returns the percentage of the window's limit still available
+func (w *Window) Remaining() float64 {
+	if w.Utilization >= 100 {
+		return 0
+	}
+	return 100 - w.Utilization
+}
+
+// ResetsIn returns the time until the window resets, or zero if unknown or past
+func (w *Window) ResetsIn(now time.Time) time.Duration {
+	if w.ResetsAt == nil || !w.ResetsAt.After(now) {
+		return 0
+	}
+	return w.ResetsAt.Sub(now)
+}
+
+// NamedWindow pairs a Window with its API field name
+type NamedWindow struct {
+	Name string
+	*Window
+}
+
 // Usage represents the usage data from Claude.ai API.
-// The API structure varies, so we preserve the raw JSON and parse flexibly.
+// Known windows are parsed into typed fields; the raw JSON is preserved
+// so that unknown or newly added fields remain available for output.
 type Usage struct {
+	FiveHour          *Window `json:"five_hour,omitempty"`
+	SevenDay          *Window `json:"seven_day,omitempty"`
+	SevenDayOpus      *Window `json:"seven_day_opus,omitempty"`
+	SevenDaySonnet    *Window `json:"seven_day_sonnet,omitempty"`
+	SevenDayOAuthApps *Window `json:"seven_day_oauth_apps,omitempty"`
+
 	// Raw JSON response for output and inspection
 	Raw json.RawMessage `json:"-"`
+
+	// RateLimits are from the response's anthropic-ratelimit-* headers, if any
+	RateLimits []RateLimit `json:"-"`
 }
 
-// UnmarshalJSON captures the raw JSON for later use
+// UnmarshalJSON captures the raw JSON and parses known windows.
+// Typed parsing is best-effort and per window: if the API changes a
+// window's shape, that typed field is left nil rather than failing, and
+// Raw is still populated.
 func (u *Usage) UnmarshalJSON(data []byte) error {
 	u.Raw = make(json.RawMessage, len(data))
 	copy(u.Raw, data)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	for name, dst := range u.windowPtrs() {
+		var w *Window
+		if raw, ok := fields[name]; ok && json.Unmarshal(raw, &w) == nil {
+			*dst = w
+		}
+	}
+	return nil
+}
+
+// windowPtrs maps each window name to its typed field
+func (u *Usage) windowPtrs() map[string]**Window {
+	return map[string]**Window{
+		WindowFiveHour:          &u.FiveHour,
+		WindowSevenDay:          &u.SevenDay,
+		WindowSevenDayOpus:      &u.SevenDayOpus,
+		WindowSevenDaySonnet:    &u.SevenDaySonnet,
+		WindowSevenDayOAuthApps: &u.SevenDayOAuthApps,
+	}
+}
+
+// Windows returns all windows present in the response, in a stable order
+func (u *Usage) Windows() []NamedWindow {
+	candidates := []NamedWindow{
+		{WindowFiveHour, u.FiveHour},
+		{WindowSevenDay, u.SevenDay},
+		{WindowSevenDayOpus, u.SevenDayOpus},
+		{WindowSevenDaySonnet, u.SevenDaySonnet},
+		{WindowSevenDayOAuthApps, u.SevenDayOAuthApps},
+	}
+
+	windows := make([]NamedWindow, 0, len(candidates))
+	for _, w := range candidates {
+		if w.Window != nil {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// Window returns the named window, or nil if it is absent
+func (u *Usage) Window(name string) *Window {
+	for _, w := range u.Windows() {
+		if w.Name == name {
+			return w.Window
+		}
+	}
 	return nil
 }
 
+// MostConstrainedWindow returns the window with the highest utilization.
+// Returns false if the response contains no known windows.
+func (u *Usage) MostConstrainedWindow() (NamedWindow, bool) {
+	var most NamedWindow
+	found := false
+	for _, w := range u.Windows() {
+		if !found || w.Utilization > most.Utilization {
+			most = w
+			found = true
+		}
+	}
+	return most, found
+}
+
 // ToJSON returns the usage as a formatted JSON string
 func (u *Usage) ToJSON() (string, error) {
 	if u.Raw == nil {
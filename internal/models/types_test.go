@@ -0,0 +1,150 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+const sampleUsage = `{
+  "five_hour": {"utilization": 62.0, "resets_at": "2025-01-15T14:30:00.123456+00:00"},
+  "seven_day": {"utilization": 34.0, "resets_at": "2025-01-20T08:00:00+00:00"},
+  "seven_day_opus": {"utilization": 81.5, "resets_at": null},
+  "seven_day_oauth_apps": null,
+  "extra_field": {"something": 1}
+}`
+
+func TestUnmarshalTypedFields(t *testing.T) {
+	var u Usage
+	if err := json.Unmarshal([]byte(sampleUsage), &u); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if u.FiveHour == nil || u.FiveHour.Utilization != 62 {
+		t.Errorf("FiveHour = %+v, want utilization 62", u.FiveHour)
+	}
+	want := time.Date(2025, 1, 15, 14, 30, 0, 123456000, time.UTC)
+	if u.FiveHour.ResetsAt == nil || !u.FiveHour.ResetsAt.Equal(want) {
+		t.Errorf("FiveHour.ResetsAt = %v, want %v", u.FiveHour.ResetsAt, want)
+	}
+	if u.SevenDayOpus == nil || u.SevenDayOpus.ResetsAt != nil {
+		t.Errorf("SevenDayOpus = %+v, want nil ResetsAt", u.SevenDayOpus)
+	}
+	if u.SevenDayOAuthApps != nil {
+		t.Errorf("SevenDayOAuthApps = %+v, want nil", u.SevenDayOAuthApps)
+	}
+	if string(u.Raw) != sampleUsage {
+		t.Error("Raw JSON not preserved")
+	}
+}
+
+func TestUnmarshalToleratesShapeChanges(t *testing.T) {
+	var u Usage
+	raw := `{"five_hour": "not an object"}`
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatalf("Unmarshal should tolerate unexpected shapes, got %v", err)
+	}
+	if u.FiveHour != nil {
+		t.Errorf("FiveHour = %+v, want nil", u.FiveHour)
+	}
+	if string(u.Raw) != raw {
+		t.Error("Raw JSON not preserved")
+	}
+}
+
+func TestUnmarshalKeepsOtherWindows(t *testing.T) {
+	var u Usage
+	if err := json.Unmarshal([]byte(`{"five_hour": "not an object", "seven_day": {"utilization": 23}}`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.FiveHour != nil || u.SevenDay == nil || u.SevenDay.Utilization != 23 {
+		t.Errorf("FiveHour = %+v, SevenDay = %+v, want only seven_day parsed", u.FiveHour, u.SevenDay)
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	var u Usage
+	raw := `{
+		"five_hour": {"utilization": 45, "resets_at": null, "limit": 100},
+		"seven_day": "not an object",
+		"seven_day_oauth_apps": null,
+		"extra_usage": {"is_enabled": false, "monthly_limit": null},
+		"plan": "max",
+		"future": null
+	}`
+	if err := json.Unmarshal([]byte(raw), &u); err != nil {
+		t.Fatal(err)
+	}
+	got := u.UnknownFields()
+	want := []string{"five_hour.limit", "plan", "seven_day"}
+	if !slices.Equal(got, want) {
+		t.Errorf("UnknownFields() = %v, want %v", got, want)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	var u Usage
+	_ = json.Unmarshal([]byte(sampleUsage), &u)
+
+	windows := u.Windows()
+	names := make([]string, len(windows))
+	for i, w := range windows {
+		names[i] = w.Name
+	}
+	expected := []string{WindowFiveHour, WindowSevenDay, WindowSevenDayOpus}
+	if len(names) != len(expected) {
+		t.Fatalf("Windows() = %v, want %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Windows()[%d] = %s, want %s", i, names[i], expected[i])
+		}
+	}
+
+	if u.Window(WindowSevenDay) != u.SevenDay {
+		t.Error("Window(seven_day) should return SevenDay")
+	}
+	if u.Window("missing") != nil {
+		t.Error("Window(missing) should return nil")
+	}
+}
+
+func TestMostConstrainedWindow(t *testing.T) {
+	var u Usage
+	_ = json.Unmarshal([]byte(sampleUsage), &u)
+
+	w, ok := u.MostConstrainedWindow()
+	if !ok {
+		t.Fatal("MostConstrainedWindow returned false")
+	}
+	if w.Name != WindowSevenDayOpus {
+		t.Errorf("MostConstrainedWindow = %s, want %s", w.Name, WindowSevenDayOpus)
+	}
+
+	var empty Usage
+	if _, ok := empty.MostConstrainedWindow(); ok {
+		t.Error("MostConstrainedWindow on empty usage should return false")
+	}
+}
+
+func TestWindowRemainingAndResetsIn(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	resets := now.Add(2*time.Hour + 14*time.Minute)
+
+	w := &Window{Utilization: 62, ResetsAt: &resets}
+	if w.Remaining() != 38 {
+		t.Errorf("Remaining() = %v, want 38", w.Remaining())
+	}
+	if w.ResetsIn(now) != 2*time.Hour+14*time.Minute {
+		t.Errorf("ResetsIn() = %v, want 2h14m", w.ResetsIn(now))
+	}
+
+	over := &Window{Utilization: 120}
+	if over.Remaining() != 0 {
+		t.Errorf("Remaining() over limit = %v, want 0", over.Remaining())
+	}
+	if over.ResetsIn(now) != 0 {
+		t.Errorf("ResetsIn() with unknown reset = %v, want 0", over.ResetsIn(now))
+	}
+}
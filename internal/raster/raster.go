@@ -0,0 +1,125 @@
+// Package raster draws text and rectangles onto images with a built-in
+// bitmap font, so PNGs can be rendered without font files.
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Glyph size in logical pixels
+const (
+	GlyphWidth  = 5
+	GlyphHeight = 7
+	// Advance is the width each character takes, including the gap after it
+	Advance = GlyphWidth + 1
+)
+
+// Canvas is an image drawn in logical pixels, each Scale device pixels
+// square, so the bitmap font stays crisp at larger sizes
+type Canvas struct {
+	img   *image.RGBA
+	scale int
+}
+
+// New returns a transparent canvas of width by height logical pixels
+func New(width, height, scale int) *Canvas {
+	scale = max(scale, 1)
+	return &Canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, width*scale, height*scale)),
+		scale: scale,
+	}
+}
+
+// Image returns the canvas's image
+func (c *Canvas) Image() *image.RGBA {
+	return c.img
+}
+
+// Fill paints a rectangle
+func (c *Canvas) Fill(x, y, width, height int, col color.Color) {
+	s := c.scale
+	r := image.Rect(x*s, y*s, (x+width)*s, (y+height)*s).Intersect(c.img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			c.img.Set(px, py, col)
+		}
+	}
+}
+
+// Text draws s with its top left corner at x, y and returns the x after
+// it. Characters outside printable ASCII are drawn as '?'.
+func (c *Canvas) Text(x, y int, s string, col color.Color) int {
+	for _, r := range s {
+		glyph, ok := glyphs[r]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for row, bits := range glyph {
+			for bit := 0; bit < GlyphWidth; bit++ {
+				if bits&(1<<(GlyphWidth-1-bit)) != 0 {
+					c.Fill(x+bit, y+row, 1, 1, col)
+				}
+			}
+		}
+		x += Advance
+	}
+	return x
+}
+
+// RoundCorners clears the canvas outside corners of the given radius
+func (c *Canvas) RoundCorners(radius int) {
+	b := c.img.Bounds()
+	r := radius * c.scale
+	for dy := 0; dy < r; dy++ {
+		for dx := 0; dx < r; dx++ {
+			// Distance from the corner circle's center, measured to the pixel's center
+			fx, fy := float64(r-dx)-0.5, float64(r-dy)-0.5
+			if fx*fx+fy*fy <= float64(r*r) {
+				continue
+			}
+			for _, p := range []image.Point{
+				{b.Min.X + dx, b.Min.Y + dy},
+				{b.Max.X - 1 - dx, b.Min.Y + dy},
+				{b.Min.X + dx, b.Max.Y - 1 - dy},
+				{b.Max.X - 1 - dx, b.Max.Y - 1 - dy},
+			} {
+				c.img.Set(p.X, p.Y, color.Transparent)
+			}
+		}
+	}
+}
+
+// WritePNG encodes the canvas as a PNG
+func (c *Canvas) WritePNG(w io.Writer) error {
+	return png.Encode(w, c.img)
+}
+
+// TextWidth returns the width of s in logical pixels, without the gap
+// after the last character
+func TextWidth(s string) int {
+	n := utf8.RuneCountInString(s)
+	if n == 0 {
+		return 0
+	}
+	return n*Advance - 1
+}
+
+// ParseHex parses a color in "#rgb" or "#rrggbb" form
+func ParseHex(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: use #rgb or #rrggbb", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
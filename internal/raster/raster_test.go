@@ -0,0 +1,87 @@
+package raster
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	c := New(20, 10, 2)
+	end := c.Text(1, 1, "I.", white)
+	if end != 1+2*Advance {
+		t.Errorf("Text returned x = %d, want %d", end, 1+2*Advance)
+	}
+
+	img := c.Image()
+	if got := img.Bounds().Dx(); got != 40 {
+		t.Errorf("width = %d device pixels, want 40", got)
+	}
+	// 'I' has a full top bar from column 1 to 3; each logical pixel is 2x2
+	for _, p := range [][2]int{{4, 2}, {5, 3}, {8, 2}} {
+		if img.RGBAAt(p[0], p[1]) != white {
+			t.Errorf("pixel %v not drawn", p)
+		}
+	}
+	for _, p := range [][2]int{{2, 2}, {10, 2}, {0, 0}} {
+		if img.RGBAAt(p[0], p[1]).A != 0 {
+			t.Errorf("pixel %v drawn, want transparent", p)
+		}
+	}
+}
+
+func TestGlyphs(t *testing.T) {
+	for r := rune(' '); r <= '~'; r++ {
+		if _, ok := glyphs[r]; !ok {
+			t.Errorf("no glyph for %q", r)
+		}
+	}
+}
+
+func TestRoundCorners(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	c := New(10, 10, 1)
+	c.Fill(0, 0, 10, 10, red)
+	c.RoundCorners(3)
+
+	img := c.Image()
+	for _, p := range [][2]int{{0, 0}, {9, 0}, {0, 9}, {9, 9}} {
+		if img.RGBAAt(p[0], p[1]).A != 0 {
+			t.Errorf("corner %v not cleared", p)
+		}
+	}
+	for _, p := range [][2]int{{2, 2}, {5, 0}, {0, 5}} {
+		if img.RGBAAt(p[0], p[1]) != red {
+			t.Errorf("pixel %v cleared", p)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf); err != nil {
+		t.Fatalf("WritePNG failed: %v", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("PNG doesn't decode: %v", err)
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := map[string]color.RGBA{
+		"#4c1":    {0x44, 0xcc, 0x11, 0xff},
+		"#e05d44": {0xe0, 0x5d, 0x44, 0xff},
+		"555555":  {0x55, 0x55, 0x55, 0xff},
+	}
+	for in, want := range tests {
+		got, err := ParseHex(in)
+		if err != nil || got != want {
+			t.Errorf("ParseHex(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "#12", "#ggg", "#1234567"} {
+		if _, err := ParseHex(in); err == nil {
+			t.Errorf("ParseHex(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package redact masks credentials and account identifiers in text bound
+// for logs, error messages, traces, and diagnostic bundles.
+package redact
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+// Mask replaces each redacted value
+const Mask = "[REDACTED]"
+
+// patterns match secrets and identifiers. Where a pattern has a group, only
+// the text after it is masked, keeping the key that says what was there.
+var patterns = []*regexp.Regexp{
+	// Anthropic OAuth, API, admin, and session keys: sk-ant-oat01-..., sk-ant-sid01-...
+	regexp.MustCompile(`sk-ant-[A-Za-z0-9_-]+`),
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`),
+	// Cookie headers and sessionKey cookies
+	regexp.MustCompile(`(?i)((?:set-)?cookie:\s*)[^\r\n]+`),
+	regexp.MustCompile(`(?i)(sessionKey=)[^;\s"&]+`),
+	// Credentials in URL query strings and userinfo
+	regexp.MustCompile(`(?i)([?&](?:access_token|refresh_token|token|key|api_key|code|session)=)[^&\s"]+`),
+	regexp.MustCompile(`(://[^/\s:@]+:)[^@/\s]+(@)`),
+	// Slack and Discord webhook URLs carry their secret in the path
+	regexp.MustCompile(`(/services/T[A-Za-z0-9]+/B[A-Za-z0-9]+/)[A-Za-z0-9]+`),
+	regexp.MustCompile(`(/api/webhooks/[0-9]+/)[A-Za-z0-9_-]+`),
+	// Organization and account UUIDs
+	regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`),
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+}
+
+// String masks credentials, organization and account IDs, and email
+// addresses in s
+func String(s string) string {
+	for _, p := range patterns {
+		s = p.ReplaceAllStringFunc(s, func(match string) string {
+			sub := p.FindStringSubmatch(match)
+			switch len(sub) {
+			case 2:
+				return sub[1] + Mask
+			case 3:
+				return sub[1] + Mask + sub[2]
+			}
+			return Mask
+		})
+	}
+	return s
+}
+
+// redactedError reports an error's message redacted, keeping its chain
+// for errors.Is and errors.As
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return String(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Error wraps err so its message is redacted. Its chain is unchanged, so
+// sentinel checks and exit codes still apply.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+// ReplaceAttr redacts log messages and string and error attribute values,
+// for slog.HandlerOptions
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	switch v := a.Value.Any().(type) {
+	case string:
+		a.Value = slog.StringValue(String(v))
+	case error:
+		a.Value = slog.StringValue(String(v.Error()))
+	}
+	return a
+}
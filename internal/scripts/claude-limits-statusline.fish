@@ -0,0 +1,126 @@
+#!/usr/bin/env fish
+# Claude Code status line showing Claude.ai usage limits (fish)
+
+# Read JSON from stdin (Claude Code status line protocol)
+set -l input (cat | string collect)
+
+# Extract a numeric JSON field; fish has no JSON parser and jq may be missing
+function json_number --argument-names json key
+    string match -rg '"'$key'"\s*:\s*([0-9]+)' $json
+end
+
+# Calculate context utilization from stdin if available
+set -l stdin_context
+set -l context_size (json_number "$input" context_window_size)
+if string match -rq '"current_usage"\s*:\s*\{' "$input"; and test -n "$context_size"; and test "$context_size" -gt 0
+    set -l current_tokens 0
+    for key in input_tokens output_tokens cache_creation_input_tokens cache_read_input_tokens
+        set -l tokens (json_number "$input" $key)
+        if test -n "$tokens"
+            set current_tokens (math $current_tokens + $tokens)
+        end
+    end
+    set stdin_context (math -s0 "$current_tokens * 100 / $context_size")
+end
+
+# ANSI color codes
+set -g RED \e'[31m'
+set -g YELLOW \e'[33m'
+set -g GREEN \e'[32m'
+set -g RESET \e'[0m'
+
+# Color thresholds (percent)
+set -g WARNING {{.Warning}}
+set -g CRITICAL {{.Critical}}
+
+# Time format (strftime syntax, default from the configured format preset)
+# Use "+%H:%M" for 24-hour, "+%I:%M %p" for 12-hour with AM/PM
+set -l time_format "{{if .Clock24}}+%H:%M{{else}}+%I:%M %p{{end}}"
+set -q CLAUDE_LIMITS_TIME_FORMAT; and set time_format $CLAUDE_LIMITS_TIME_FORMAT
+# Date+time format for weekly reset (includes day)
+set -l datetime_format "{{if .Clock24}}+%a %H:%M{{else}}+%a %I:%M %p{{end}}"
+set -q CLAUDE_LIMITS_DATETIME_FORMAT; and set datetime_format $CLAUDE_LIMITS_DATETIME_FORMAT
+
+# Colorize a percentage value based on thresholds
+function colorize --argument-names value
+    if test "$value" = "?"
+        echo $value
+        return
+    end
+    # Extract numeric part (remove any decimal)
+    set -l num (string split -m1 . -- $value)[1]
+    if test "$num" -ge $CRITICAL
+        echo "$RED$value$RESET"
+    else if test "$num" -ge $WARNING
+        echo "$YELLOW$value$RESET"
+    else
+        echo "$GREEN$value$RESET"
+    end
+end
+
+# Format an ISO timestamp to local time
+function format_time --argument-names iso_time format
+    if test -z "$iso_time"; or test "$iso_time" = "?"
+        echo "?"
+        return
+    end
+
+    # Try GNU date first (Linux), then BSD date (macOS)
+    if date --version >/dev/null 2>&1
+        date -d "$iso_time" "$format" 2>/dev/null; or echo "?"
+    else
+        # BSD date (macOS) - convert ISO 8601 to epoch then format
+        set -l epoch (date -j -f "%Y-%m-%dT%H:%M:%S%z" (string replace Z +0000 -- $iso_time) '+%s' 2>/dev/null)
+        or set epoch (date -j -f "%Y-%m-%dT%H:%M:%SZ" "$iso_time" '+%s' 2>/dev/null)
+        if test -n "$epoch"
+            date -j -f '%s' "$epoch" "$format" 2>/dev/null; or echo "?"
+        else
+            echo "?"
+        end
+    end
+end
+
+# Find claude-limits binary
+set -l claude_limits $CLAUDE_LIMITS_PATH
+if test -z "$claude_limits"
+{{- if .Binary}}
+    set claude_limits "{{.Binary}}"
+{{- else}}
+    set claude_limits (command -v claude-limits 2>/dev/null)
+{{- end}}
+end
+if test -z "$claude_limits"
+    echo "claude-limits: not found"
+    exit 1
+end
+
+# Cache TTL in seconds (empty for claude-limits' default)
+set -l cache_args {{with .CacheTTL}}--cache {{.}}{{end}}
+
+# Get utilization values and reset times (using specific queries to avoid ambiguity)
+set -l five_hour ($claude_limits $cache_args five_hour_utilization 2>/dev/null)
+set -l weekly ($claude_limits $cache_args seven_day_utilization 2>/dev/null)
+# Use context from stdin if available, otherwise try claude-limits
+set -l context $stdin_context
+if test -z "$context"
+    set context ($claude_limits $cache_args context_utilization 2>/dev/null)
+end
+set -l five_hour_reset ($claude_limits $cache_args five_hour_reset 2>/dev/null)
+set -l weekly_reset ($claude_limits $cache_args seven_day_reset 2>/dev/null)
+
+# Default to "?" if not available
+test -n "$five_hour"; or set five_hour "?"
+test -n "$weekly"; or set weekly "?"
+test -n "$context"; or set context "?"
+
+# Format reset times
+set -l five_hour_reset_local (format_time "$five_hour_reset" "$time_format")
+set -l weekly_reset_local (format_time "$weekly_reset" "$datetime_format")
+
+# Colorize values
+set -l five_hour_c (colorize $five_hour)
+set -l weekly_c (colorize $weekly)
+set -l context_c (colorize $context)
+
+# Output the status line
+echo "5h: $five_hour_c% @ $five_hour_reset_local | wk: $weekly_c% @ $weekly_reset_local | ctx: $context_c%"
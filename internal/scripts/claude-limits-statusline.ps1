@@ -31,11 +31,15 @@ $YELLOW = "`e[33m"
 $GREEN = "`e[32m"
 $RESET = "`e[0m"
 
-# Time format (.NET format strings, default to 12-hour format)
+# Time format (.NET format strings, default from the configured format preset)
 # Use "HH:mm" for 24-hour, "h:mm tt" for 12-hour with AM/PM
-$TIME_FORMAT = if ($env:CLAUDE_LIMITS_TIME_FORMAT) { $env:CLAUDE_LIMITS_TIME_FORMAT } else { "h:mm tt" }
+$TIME_FORMAT = if ($env:CLAUDE_LIMITS_TIME_FORMAT) { $env:CLAUDE_LIMITS_TIME_FORMAT } else { "{{if .Clock24}}HH:mm{{else}}h:mm tt{{end}}" }
 # Date+time format for weekly reset (includes day)
-$DATETIME_FORMAT = if ($env:CLAUDE_LIMITS_DATETIME_FORMAT) { $env:CLAUDE_LIMITS_DATETIME_FORMAT } else { "ddd h:mm tt" }
+$DATETIME_FORMAT = if ($env:CLAUDE_LIMITS_DATETIME_FORMAT) { $env:CLAUDE_LIMITS_DATETIME_FORMAT } else { "{{if .Clock24}}ddd HH:mm{{else}}ddd h:mm tt{{end}}" }
+
+# Color thresholds (percent)
+$WARNING = {{.Warning}}
+$CRITICAL = {{.Critical}}
 
 # Colorize a percentage value based on thresholds
 function Colorize {
@@ -44,9 +48,9 @@ function Colorize {
         return "?"
     }
     $num = [int][math]::Floor([double]$Value)
-    if ($num -ge 95) {
+    if ($num -ge $CRITICAL) {
         return "${RED}${Value}${RESET}"
-    } elseif ($num -ge 80) {
+    } elseif ($num -ge $WARNING) {
         return "${YELLOW}${Value}${RESET}"
     } else {
         return "${GREEN}${Value}${RESET}"
@@ -74,7 +78,11 @@ function Format-Time {
 $CLAUDE_LIMITS = if ($env:CLAUDE_LIMITS_PATH) {
     $env:CLAUDE_LIMITS_PATH
 } else {
+{{- if .Binary}}
+    "{{.Binary}}"
+{{- else}}
     Get-Command claude-limits -ErrorAction SilentlyContinue | Select-Object -ExpandProperty Source
+{{- end}}
 }
 
 if (-not $CLAUDE_LIMITS) {
@@ -82,15 +90,18 @@ if (-not $CLAUDE_LIMITS) {
     exit 1
 }
 
+# Cache TTL in seconds (empty for claude-limits' default)
+$CACHE_ARGS = @({{with .CacheTTL}}"--cache", "{{.}}"{{end}})
+
 # Get utilization values and reset times (using specific queries to avoid ambiguity)
 try {
-    $FIVE_HOUR = & $CLAUDE_LIMITS five_hour_utilization 2>$null
+    $FIVE_HOUR = & $CLAUDE_LIMITS @CACHE_ARGS five_hour_utilization 2>$null
 } catch {
     $FIVE_HOUR = $null
 }
 
 try {
-    $WEEKLY = & $CLAUDE_LIMITS seven_day_utilization 2>$null
+    $WEEKLY = & $CLAUDE_LIMITS @CACHE_ARGS seven_day_utilization 2>$null
 } catch {
     $WEEKLY = $null
 }
@@ -99,20 +110,20 @@ try {
 $CONTEXT = $StdinContext
 if (-not $CONTEXT) {
     try {
-        $CONTEXT = & $CLAUDE_LIMITS context_utilization 2>$null
+        $CONTEXT = & $CLAUDE_LIMITS @CACHE_ARGS context_utilization 2>$null
     } catch {
         $CONTEXT = $null
     }
 }
 
 try {
-    $FIVE_HOUR_RESET = & $CLAUDE_LIMITS five_hour_reset 2>$null
+    $FIVE_HOUR_RESET = & $CLAUDE_LIMITS @CACHE_ARGS five_hour_reset 2>$null
 } catch {
     $FIVE_HOUR_RESET = $null
 }
 
 try {
-    $WEEKLY_RESET = & $CLAUDE_LIMITS seven_day_reset 2>$null
+    $WEEKLY_RESET = & $CLAUDE_LIMITS @CACHE_ARGS seven_day_reset 2>$null
 } catch {
     $WEEKLY_RESET = $null
 }
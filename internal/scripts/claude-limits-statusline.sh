@@ -36,11 +36,15 @@ YELLOW='\033[33m'
 GREEN='\033[32m'
 RESET='\033[0m'
 
-# Time format (strftime syntax, default to 12-hour format)
+# Time format (strftime syntax, default from the configured format preset)
 # Use "+%H:%M" for 24-hour, "+%I:%M %p" for 12-hour with AM/PM
-TIME_FORMAT="${CLAUDE_LIMITS_TIME_FORMAT:-+%I:%M %p}"
+TIME_FORMAT="${CLAUDE_LIMITS_TIME_FORMAT:-{{if .Clock24}}+%H:%M{{else}}+%I:%M %p{{end}}}"
 # Date+time format for weekly reset (includes day)
-DATETIME_FORMAT="${CLAUDE_LIMITS_DATETIME_FORMAT:-+%a %I:%M %p}"
+DATETIME_FORMAT="${CLAUDE_LIMITS_DATETIME_FORMAT:-{{if .Clock24}}+%a %H:%M{{else}}+%a %I:%M %p{{end}}}"
+
+# Color thresholds (percent)
+WARNING={{.Warning}}
+CRITICAL={{.Critical}}
 
 # Colorize a percentage value based on thresholds
 colorize() {
@@ -51,9 +55,9 @@ colorize() {
     fi
     # Extract numeric part (remove any decimal)
     local num="${value%.*}"
-    if [[ "$num" -ge 95 ]]; then
+    if [[ "$num" -ge $CRITICAL ]]; then
         echo -e "${RED}${value}${RESET}"
-    elif [[ "$num" -ge 80 ]]; then
+    elif [[ "$num" -ge $WARNING ]]; then
         echo -e "${YELLOW}${value}${RESET}"
     else
         echo -e "${GREEN}${value}${RESET}"
@@ -88,19 +92,22 @@ format_time() {
 }
 
 # Find claude-limits binary
-CLAUDE_LIMITS="${CLAUDE_LIMITS_PATH:-$(command -v claude-limits 2>/dev/null)}"
+CLAUDE_LIMITS="${CLAUDE_LIMITS_PATH:-{{if .Binary}}{{.Binary}}{{else}}$(command -v claude-limits 2>/dev/null){{end}}}"
 if [[ -z "$CLAUDE_LIMITS" ]]; then
     echo "claude-limits: not found"
     exit 1
 fi
 
+# Cache TTL in seconds (empty for claude-limits' default)
+CACHE_ARGS="{{with .CacheTTL}}--cache {{.}}{{end}}"
+
 # Get utilization values and reset times (using specific queries to avoid ambiguity)
-FIVE_HOUR=$($CLAUDE_LIMITS five_hour_utilization 2>/dev/null)
-WEEKLY=$($CLAUDE_LIMITS seven_day_utilization 2>/dev/null)
+FIVE_HOUR=$($CLAUDE_LIMITS $CACHE_ARGS five_hour_utilization 2>/dev/null)
+WEEKLY=$($CLAUDE_LIMITS $CACHE_ARGS seven_day_utilization 2>/dev/null)
 # Use context from stdin if available, otherwise try claude-limits
-CONTEXT="${STDIN_CONTEXT:-$($CLAUDE_LIMITS context_utilization 2>/dev/null)}"
-FIVE_HOUR_RESET=$($CLAUDE_LIMITS five_hour_reset 2>/dev/null)
-WEEKLY_RESET=$($CLAUDE_LIMITS seven_day_reset 2>/dev/null)
+CONTEXT="${STDIN_CONTEXT:-$($CLAUDE_LIMITS $CACHE_ARGS context_utilization 2>/dev/null)}"
+FIVE_HOUR_RESET=$($CLAUDE_LIMITS $CACHE_ARGS five_hour_reset 2>/dev/null)
+WEEKLY_RESET=$($CLAUDE_LIMITS $CACHE_ARGS seven_day_reset 2>/dev/null)
 
 # Default to "?" if not available
 FIVE_HOUR=${FIVE_HOUR:-"?"}
@@ -0,0 +1,55 @@
+#!/bin/bash
+# tmux status segment showing Claude.ai usage limits
+#
+# Add to ~/.tmux.conf:
+#   set -g status-right '#(~/.local/bin/claude-limits-tmux.sh)'
+#   set -g status-interval 60
+#
+# Usage is cached for CLAUDE_LIMITS_TMUX_CACHE seconds (default 300, or --cache
+# when installed), so every client and status refresh shares one API request. If a refresh fails, the
+# last segment is shown dimmed instead.
+
+CACHE_TTL="${CLAUDE_LIMITS_TMUX_CACHE:-{{with .CacheTTL}}{{.}}{{else}}300{{end}}}"
+WARNING="${CLAUDE_LIMITS_TMUX_WARNING:-{{.Warning}}}"
+CRITICAL="${CLAUDE_LIMITS_TMUX_CRITICAL:-{{.Critical}}}"
+
+STATE_DIR="${XDG_CACHE_HOME:-$HOME/.cache}/claude-limits"
+LAST_SEGMENT="$STATE_DIR/tmux-segment"
+
+# Find claude-limits binary
+CLAUDE_LIMITS="${CLAUDE_LIMITS_PATH:-{{if .Binary}}{{.Binary}}{{else}}$(command -v claude-limits 2>/dev/null){{end}}}"
+if [[ -z "$CLAUDE_LIMITS" ]]; then
+    echo "#[fg=colour244]claude-limits: not found#[default]"
+    exit 0
+fi
+
+LINE=$("$CLAUDE_LIMITS" --format compact --no-color --cache "$CACHE_TTL" 2>/dev/null)
+if [[ -z "$LINE" ]]; then
+    if [[ -r "$LAST_SEGMENT" ]]; then
+        echo "#[dim]$(cat "$LAST_SEGMENT")#[default]"
+    else
+        echo "#[fg=colour244]claude: ?#[default]"
+    fi
+    exit 0
+fi
+
+# Wrap each percentage in a tmux style by threshold
+SEGMENT=""
+REST="$LINE"
+while [[ "$REST" =~ ([0-9]+)% ]]; do
+    MATCH="${BASH_REMATCH[0]}"
+    NUM="${BASH_REMATCH[1]}"
+    if ((NUM >= CRITICAL)); then
+        COLOR="red"
+    elif ((NUM >= WARNING)); then
+        COLOR="yellow"
+    else
+        COLOR="green"
+    fi
+    SEGMENT+="${REST%%"$MATCH"*}#[fg=${COLOR}]${MATCH}#[default]"
+    REST="${REST#*"$MATCH"}"
+done
+SEGMENT+="$REST"
+
+mkdir -p "$STATE_DIR" 2>/dev/null && printf '%s\n' "$LINE" > "$LAST_SEGMENT" 2>/dev/null
+echo "$SEGMENT"
@@ -1,7 +1,10 @@
 package scripts
 
 import (
+	"bytes"
 	_ "embed"
+	"fmt"
+	"text/template"
 )
 
 //go:embed claude-limits-statusline.sh
@@ -10,12 +13,46 @@ var bashScript []byte
 //go:embed claude-limits-statusline.ps1
 var powershellScript []byte
 
+//go:embed claude-limits-statusline.fish
+var fishScript []byte
+
+//go:embed claude-limits-statusline.nu
+var nushellScript []byte
+
+//go:embed claude-limits-tmux.sh
+var tmuxScript []byte
+
+// Vars are substituted into a script's template when it's installed, so the
+// script matches the user's config without hand-editing
+type Vars struct {
+	// Binary is the claude-limits path; empty finds it on PATH at run time
+	Binary string
+	// CacheTTL is passed as --cache, in seconds; 0 keeps the script's default
+	CacheTTL int
+	// Warning and Critical are the utilization color thresholds (percent)
+	Warning  int
+	Critical int
+	// Clock24 shows reset times on a 24-hour clock
+	Clock24 bool
+}
+
+// DefaultVars returns the values used without a config
+func DefaultVars() Vars {
+	return Vars{Warning: 80, Critical: 95}
+}
+
 // Script represents an embedded script
 type Script struct {
 	Name        string
 	Filename    string
 	Description string
-	Content     []byte
+	// Content is the script as a text/template over Vars
+	Content []byte
+	// Executable scripts are installed with mode 0755 on Unix
+	Executable bool
+	// StatusLine marks Claude Code status line scripts, which installing
+	// configures in Claude Code's settings
+	StatusLine bool
 }
 
 // Available scripts
@@ -25,13 +62,62 @@ var Available = map[string]Script{
 		Filename:    "claude-limits-statusline.sh",
 		Description: "Bash status line script for Claude Code",
 		Content:     bashScript,
+		Executable:  true,
+		StatusLine:  true,
 	},
 	"powershell": {
 		Name:        "powershell",
 		Filename:    "claude-limits-statusline.ps1",
 		Description: "PowerShell status line script for Claude Code",
 		Content:     powershellScript,
+		StatusLine:  true,
+	},
+	"fish": {
+		Name:        "fish",
+		Filename:    "claude-limits-statusline.fish",
+		Description: "Fish status line script for Claude Code (no jq needed)",
+		Content:     fishScript,
+		Executable:  true,
+		StatusLine:  true,
+	},
+	"nushell": {
+		Name:        "nushell",
+		Filename:    "claude-limits-statusline.nu",
+		Description: "Nushell status line script for Claude Code",
+		Content:     nushellScript,
+		Executable:  true,
+		StatusLine:  true,
 	},
+	"tmux": {
+		Name:        "tmux",
+		Filename:    "claude-limits-tmux.sh",
+		Description: "tmux status bar segment",
+		Content:     tmuxScript,
+		Executable:  true,
+	},
+}
+
+// Render returns the script with vars substituted
+func (s Script) Render(vars Vars) ([]byte, error) {
+	tmpl, err := template.New(s.Filename).Option("missingkey=error").Parse(string(s.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse script %s: %w", s.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("failed to render script %s: %w", s.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Matches reports whether content is an installed copy of the script, by its
+// opening lines (shebang and description), which rendering leaves unchanged
+func (s Script) Matches(content []byte) bool {
+	lines := bytes.SplitAfterN(s.Content, []byte("\n"), 3)
+	if len(lines) < 3 {
+		return bytes.Equal(content, s.Content)
+	}
+	return bytes.HasPrefix(content, s.Content[:len(lines[0])+len(lines[1])])
 }
 
 // Get returns a script by name, or nil if not found
@@ -0,0 +1,66 @@
+package scripts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	for _, name := range List() {
+		t.Run(name, func(t *testing.T) {
+			s := Get(name)
+			out, err := s.Render(DefaultVars())
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if bytes.Contains(out, []byte("{{")) {
+				t.Error("rendered script still contains template actions")
+			}
+			if !s.Matches(out) {
+				t.Error("rendered script should match its embedded script")
+			}
+		})
+	}
+}
+
+func TestRenderVars(t *testing.T) {
+	vars := Vars{Binary: "/opt/bin/claude-limits", CacheTTL: 120, Warning: 70, Critical: 90, Clock24: true}
+	out, err := Get("bash").Render(vars)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	script := string(out)
+	for _, want := range []string{
+		`CLAUDE_LIMITS="${CLAUDE_LIMITS_PATH:-/opt/bin/claude-limits}"`,
+		`CACHE_ARGS="--cache 120"`,
+		"WARNING=70",
+		"CRITICAL=90",
+		`TIME_FORMAT="${CLAUDE_LIMITS_TIME_FORMAT:-+%H:%M}"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("rendered script lacks %q", want)
+		}
+	}
+
+	// Without a binary, the script looks on PATH
+	out, _ = Get("bash").Render(DefaultVars())
+	if !bytes.Contains(out, []byte(`$(command -v claude-limits 2>/dev/null)`)) || !bytes.Contains(out, []byte(`CACHE_ARGS=""`)) {
+		t.Error("default render should find claude-limits on PATH with the default cache")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	bash, tmux := Get("bash"), Get("tmux")
+	out, err := bash.Render(DefaultVars())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if tmux.Matches(out) {
+		t.Error("bash script should not match the tmux script")
+	}
+	if bash.Matches([]byte("#!/bin/bash\necho hi\n")) {
+		t.Error("an unrelated script should not match")
+	}
+}
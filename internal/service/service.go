@@ -0,0 +1,293 @@
+// Package service generates user-level service definitions that keep
+// claude-limits polling in the background: systemd units, launchd agents,
+// and Windows scheduled tasks.
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is a service manager
+type Kind string
+
+// Supported service managers
+const (
+	Systemd       Kind = "systemd"
+	Launchd       Kind = "launchd"
+	ScheduledTask Kind = "scheduled-task"
+)
+
+// Names the service is installed under
+const (
+	// UnitName is the systemd unit name, without .service or .timer
+	UnitName = "claude-limits"
+	// Label is the launchd job label, which also names its plist
+	Label = "io.github.benjaminabbitt.claude-limits"
+	// TaskName is the Windows scheduled task name
+	TaskName = "claude-limits"
+)
+
+// Default returns the service manager of the current platform
+func Default() Kind {
+	switch runtime.GOOS {
+	case "darwin":
+		return Launchd
+	case "windows":
+		return ScheduledTask
+	}
+	return Systemd
+}
+
+// Spec describes what the service runs
+type Spec struct {
+	// Binary is the absolute path of the claude-limits executable
+	Binary string
+	// Args follow the binary, e.g. ["daemon", "--interval", "60"]
+	Args []string
+	// Interval runs Args on a schedule, for commands that exit after one
+	// poll. Zero keeps Args running, restarting it if it fails, for the
+	// daemon.
+	Interval time.Duration
+}
+
+// File is a file a plan writes or removes
+type File struct {
+	Path    string
+	Content string
+}
+
+// Plan is what installing or uninstalling a service involves: commands to
+// run first, files to write or remove, then commands to run after
+type Plan struct {
+	Before [][]string
+	Files  []File
+	After  [][]string
+}
+
+// Install returns the plan for installing spec with a service manager
+func Install(kind Kind, spec Spec) (Plan, error) {
+	switch kind {
+	case Systemd:
+		return systemdInstall(spec)
+	case Launchd:
+		return launchdInstall(spec)
+	case ScheduledTask:
+		return scheduledTaskInstall(spec), nil
+	}
+	return Plan{}, fmt.Errorf("unknown service manager %q", kind)
+}
+
+// Uninstall returns the plan for removing the service from a service
+// manager. Only files that exist are included.
+func Uninstall(kind Kind) (Plan, error) {
+	switch kind {
+	case Systemd:
+		return systemdUninstall()
+	case Launchd:
+		return launchdUninstall()
+	case ScheduledTask:
+		return Plan{Before: [][]string{{"schtasks", "/Delete", "/TN", TaskName, "/F"}}}, nil
+	}
+	return Plan{}, fmt.Errorf("unknown service manager %q", kind)
+}
+
+// systemdDir returns the user unit directory
+func systemdDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+func systemdInstall(spec Spec) (Plan, error) {
+	dir, err := systemdDir()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var unit strings.Builder
+	unit.WriteString("# Installed by claude-limits install daemon\n")
+	unit.WriteString("[Unit]\n")
+	unit.WriteString("Description=Poll Claude.ai usage limits\n")
+	unit.WriteString("Wants=network-online.target\n")
+	unit.WriteString("After=network-online.target\n\n")
+	unit.WriteString("[Service]\n")
+	fmt.Fprintf(&unit, "ExecStart=%s\n", systemdCommand(spec))
+	if spec.Interval > 0 {
+		unit.WriteString("Type=oneshot\n")
+	} else {
+		unit.WriteString("Restart=on-failure\n")
+		unit.WriteString("RestartSec=30\n\n")
+		unit.WriteString("[Install]\n")
+		unit.WriteString("WantedBy=default.target\n")
+	}
+
+	service := File{Path: filepath.Join(dir, UnitName+".service"), Content: unit.String()}
+	if spec.Interval <= 0 {
+		return Plan{
+			Files: []File{service},
+			After: [][]string{
+				{"systemctl", "--user", "daemon-reload"},
+				{"systemctl", "--user", "enable", "--now", UnitName + ".service"},
+			},
+		}, nil
+	}
+
+	var timer strings.Builder
+	timer.WriteString("# Installed by claude-limits install daemon\n")
+	timer.WriteString("[Unit]\n")
+	timer.WriteString("Description=Poll Claude.ai usage limits on a schedule\n\n")
+	timer.WriteString("[Timer]\n")
+	timer.WriteString("OnStartupSec=1min\n")
+	fmt.Fprintf(&timer, "OnUnitActiveSec=%ds\n", int(spec.Interval.Seconds()))
+	timer.WriteString("Persistent=true\n\n")
+	timer.WriteString("[Install]\n")
+	timer.WriteString("WantedBy=timers.target\n")
+
+	return Plan{
+		Files: []File{service, {Path: filepath.Join(dir, UnitName+".timer"), Content: timer.String()}},
+		After: [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", UnitName + ".timer"},
+		},
+	}, nil
+}
+
+func systemdUninstall() (Plan, error) {
+	dir, err := systemdDir()
+	if err != nil {
+		return Plan{}, err
+	}
+	var plan Plan
+	for _, name := range []string{UnitName + ".timer", UnitName + ".service"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		plan.Before = append(plan.Before, []string{"systemctl", "--user", "disable", "--now", name})
+		plan.Files = append(plan.Files, File{Path: path})
+	}
+	if len(plan.Files) > 0 {
+		plan.After = [][]string{{"systemctl", "--user", "daemon-reload"}}
+	}
+	return plan, nil
+}
+
+// systemdCommand quotes a command line for ExecStart
+func systemdCommand(spec Spec) string {
+	words := append([]string{spec.Binary}, spec.Args...)
+	for i, w := range words {
+		// Specifiers and variables are expanded even inside quotes
+		w = strings.NewReplacer("%", "%%", "$", "$$").Replace(w)
+		if w == "" || strings.ContainsAny(w, " \t\"'\\;") {
+			w = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(w) + `"`
+		}
+		words[i] = w
+	}
+	return strings.Join(words, " ")
+}
+
+// launchdPath returns the agent's plist path
+func launchdPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", Label+".plist"), nil
+}
+
+func launchdInstall(spec Spec) (Plan, error) {
+	path, err := launchdPath()
+	if err != nil {
+		return Plan{}, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Plan{}, err
+	}
+	logPath := filepath.Join(home, "Library", "Logs", "claude-limits.log")
+
+	var plist strings.Builder
+	plist.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	plist.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	plist.WriteString(`<!-- Installed by claude-limits install daemon -->` + "\n")
+	plist.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	plistString(&plist, "Label", Label)
+	plist.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{spec.Binary}, spec.Args...) {
+		fmt.Fprintf(&plist, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	plist.WriteString("\t</array>\n")
+	plist.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	if spec.Interval > 0 {
+		fmt.Fprintf(&plist, "\t<key>StartInterval</key>\n\t<integer>%d</integer>\n", int(spec.Interval.Seconds()))
+	} else {
+		plist.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	}
+	plistString(&plist, "StandardOutPath", logPath)
+	plistString(&plist, "StandardErrorPath", logPath)
+	plist.WriteString("</dict>\n</plist>\n")
+
+	return Plan{
+		Files: []File{{Path: path, Content: plist.String()}},
+		After: [][]string{{"launchctl", "load", "-w", path}},
+	}, nil
+}
+
+func launchdUninstall() (Plan, error) {
+	path, err := launchdPath()
+	if err != nil {
+		return Plan{}, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return Plan{}, nil
+	}
+	return Plan{
+		Before: [][]string{{"launchctl", "unload", "-w", path}},
+		Files:  []File{{Path: path}},
+	}, nil
+}
+
+func plistString(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "\t<key>%s</key>\n\t<string>%s</string>\n", key, xmlEscape(value))
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+// scheduledTaskInstall creates a task at logon for the daemon, or every
+// Interval (in whole minutes, at least one) otherwise
+func scheduledTaskInstall(spec Spec) Plan {
+	words := append([]string{spec.Binary}, spec.Args...)
+	for i, w := range words {
+		if w == "" || strings.ContainsAny(w, " \t") {
+			words[i] = `"` + w + `"`
+		}
+	}
+
+	create := []string{"schtasks", "/Create", "/TN", TaskName, "/TR", strings.Join(words, " "), "/RL", "LIMITED", "/F"}
+	if spec.Interval > 0 {
+		minutes := max(int(spec.Interval.Minutes()), 1)
+		create = append(create, "/SC", "MINUTE", "/MO", strconv.Itoa(minutes))
+	} else {
+		create = append(create, "/SC", "ONLOGON")
+	}
+
+	plan := Plan{After: [][]string{create}}
+	if spec.Interval <= 0 {
+		// ONLOGON tasks first run at the next logon; start this one now
+		plan.After = append(plan.After, []string{"schtasks", "/Run", "/TN", TaskName})
+	}
+	return plan
+}
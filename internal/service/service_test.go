@@ -0,0 +1,172 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystemdDaemon(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	plan, err := Install(Systemd, Spec{Binary: "/usr/bin/claude-limits", Args: []string{"daemon", "--interval", "60"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 1 {
+		t.Fatalf("files = %d, want 1", len(plan.Files))
+	}
+	f := plan.Files[0]
+	if want := filepath.Join(dir, "systemd", "user", "claude-limits.service"); f.Path != want {
+		t.Errorf("path = %q, want %q", f.Path, want)
+	}
+	for _, want := range []string{
+		"ExecStart=/usr/bin/claude-limits daemon --interval 60\n",
+		"Restart=on-failure\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(f.Content, want) {
+			t.Errorf("unit missing %q:\n%s", want, f.Content)
+		}
+	}
+	if last := plan.After[len(plan.After)-1]; strings.Join(last, " ") != "systemctl --user enable --now claude-limits.service" {
+		t.Errorf("last command = %v", last)
+	}
+}
+
+func TestSystemdTimer(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	plan, err := Install(Systemd, Spec{Binary: "/usr/bin/claude-limits", Args: []string{"notify"}, Interval: 5 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 2 {
+		t.Fatalf("files = %d, want 2", len(plan.Files))
+	}
+	svc, timer := plan.Files[0], plan.Files[1]
+	if !strings.Contains(svc.Content, "Type=oneshot\n") || strings.Contains(svc.Content, "[Install]") {
+		t.Errorf("timer service should be oneshot without [Install]:\n%s", svc.Content)
+	}
+	if !strings.HasSuffix(timer.Path, "claude-limits.timer") || !strings.Contains(timer.Content, "OnUnitActiveSec=300s\n") {
+		t.Errorf("timer %s:\n%s", timer.Path, timer.Content)
+	}
+	if last := plan.After[len(plan.After)-1]; last[len(last)-1] != "claude-limits.timer" {
+		t.Errorf("should enable the timer, got %v", last)
+	}
+}
+
+func TestSystemdCommand(t *testing.T) {
+	got := systemdCommand(Spec{Binary: "/opt/my tools/claude-limits", Args: []string{"daemon", "--profile", "50%$HOME", `a"b`}})
+	want := `"/opt/my tools/claude-limits" daemon --profile 50%%$$HOME "a\"b"`
+	if got != want {
+		t.Errorf("systemdCommand = %s, want %s", got, want)
+	}
+}
+
+func TestLaunchd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	plan, err := Install(Launchd, Spec{Binary: "/usr/local/bin/claude-limits", Args: []string{"daemon", "--profile", "r&d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := plan.Files[0]
+	if want := filepath.Join(home, "Library", "LaunchAgents", Label+".plist"); f.Path != want {
+		t.Errorf("path = %q, want %q", f.Path, want)
+	}
+	for _, want := range []string{
+		"<string>" + Label + "</string>",
+		"<string>/usr/local/bin/claude-limits</string>",
+		"<string>r&amp;d</string>",
+		"<key>KeepAlive</key>",
+	} {
+		if !strings.Contains(f.Content, want) {
+			t.Errorf("plist missing %q:\n%s", want, f.Content)
+		}
+	}
+	if strings.Contains(f.Content, "StartInterval") {
+		t.Error("daemon plist should not have StartInterval")
+	}
+
+	plan, err = Install(Launchd, Spec{Binary: "/usr/local/bin/claude-limits", Args: []string{"notify"}, Interval: 2 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan.Files[0].Content, "<key>StartInterval</key>\n\t<integer>120</integer>") {
+		t.Errorf("timer plist missing StartInterval:\n%s", plan.Files[0].Content)
+	}
+}
+
+func TestScheduledTask(t *testing.T) {
+	plan, err := Install(ScheduledTask, Spec{Binary: `C:\Program Files\claude-limits.exe`, Args: []string{"notify"}, Interval: 90 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(plan.After[0], "|")
+	want := `schtasks|/Create|/TN|claude-limits|/TR|"C:\Program Files\claude-limits.exe" notify|/RL|LIMITED|/F|/SC|MINUTE|/MO|1`
+	if got != want {
+		t.Errorf("create = %s, want %s", got, want)
+	}
+	if len(plan.After) != 1 {
+		t.Errorf("scheduled task should not be run immediately: %v", plan.After)
+	}
+
+	plan, err = Install(ScheduledTask, Spec{Binary: `C:\claude-limits.exe`, Args: []string{"daemon"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(plan.After[0], " "), "/SC ONLOGON") || len(plan.After) != 2 {
+		t.Errorf("daemon task should run at logon and now: %v", plan.After)
+	}
+}
+
+func TestUninstallOnlyExisting(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	plan, err := Uninstall(Systemd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Before)+len(plan.Files)+len(plan.After) != 0 {
+		t.Errorf("nothing installed, got %+v", plan)
+	}
+
+	unitDir := filepath.Join(dir, "systemd", "user")
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(unitDir, "claude-limits.service"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = Uninstall(Systemd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 1 || !strings.HasSuffix(plan.Files[0].Path, "claude-limits.service") {
+		t.Errorf("files = %+v, want only the service", plan.Files)
+	}
+	if len(plan.After) != 1 {
+		t.Errorf("after = %v, want daemon-reload", plan.After)
+	}
+
+	plan, err = Uninstall(Launchd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 0 {
+		t.Errorf("no plist installed, got %+v", plan.Files)
+	}
+}
+
+func TestUnknownKind(t *testing.T) {
+	if _, err := Install("upstart", Spec{}); err == nil {
+		t.Error("expected an error for an unknown service manager")
+	}
+}
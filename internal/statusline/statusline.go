@@ -0,0 +1,98 @@
+// Package statusline renders a compact one-line usage summary for Claude Code's status line.
+package statusline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// unknown is shown in place of values that aren't available
+const unknown = "?"
+
+// Input is the subset of the JSON Claude Code writes to a status line
+// command's stdin that we use
+type Input struct {
+	ContextWindow *struct {
+		ContextWindowSize int64 `json:"context_window_size"`
+		CurrentUsage      *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"current_usage"`
+	} `json:"context_window"`
+}
+
+// ReadInput parses Claude Code's status line JSON. Empty or malformed input
+// yields an empty Input rather than an error, since the status line should
+// render whatever it can.
+func ReadInput(r io.Reader) Input {
+	var in Input
+	data, err := io.ReadAll(r)
+	if err != nil || len(data) == 0 {
+		return in
+	}
+	_ = json.Unmarshal(data, &in)
+	return in
+}
+
+// ContextUtilization returns the percentage of the context window in use.
+// Returns false if stdin didn't include context window data.
+func (in Input) ContextUtilization() (float64, bool) {
+	cw := in.ContextWindow
+	if cw == nil || cw.CurrentUsage == nil || cw.ContextWindowSize <= 0 {
+		return 0, false
+	}
+	u := cw.CurrentUsage
+	tokens := u.InputTokens + u.OutputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return float64(tokens*100) / float64(cw.ContextWindowSize), true
+}
+
+// Render builds the status line, e.g. "5h: 45% @ 2:30 PM | wk: 23% @ Tue 8:00 AM | ctx: 67%".
+// usage may be nil if it couldn't be fetched; missing values render as "?".
+func Render(usage *models.Usage, in Input, colors format.Colors, formats format.Formats) string {
+	var fiveHour, sevenDay *models.Window
+	if usage != nil {
+		fiveHour, sevenDay = usage.FiveHour, usage.SevenDay
+	}
+
+	parts := []string{
+		"5h: " + renderWindow(fiveHour, models.WindowFiveHour, formats.Time, colors, formats),
+		"wk: " + renderWindow(sevenDay, models.WindowSevenDay, "Mon "+formats.Time, colors, formats),
+	}
+
+	ctx := unknown
+	if v, ok := in.ContextUtilization(); ok {
+		ctx = colorize(v, "", colors)
+	}
+	parts = append(parts, "ctx: "+ctx+"%")
+
+	return strings.Join(parts, " | ")
+}
+
+// renderWindow formats a window as "45% @ 2:30 PM", or "45% in 2h 14m" with
+// relative times
+func renderWindow(w *models.Window, name, layout string, colors format.Colors, formats format.Formats) string {
+	sep := " @ "
+	if formats.Relative {
+		sep = " "
+	}
+	if w == nil {
+		return unknown + "%" + sep + unknown
+	}
+	reset := unknown
+	if w.ResetsAt != nil {
+		reset = format.ResetTime(*w.ResetsAt, layout, formats)
+	}
+	return fmt.Sprintf("%s%%%s%s", colorize(w.Utilization, name, colors), sep, reset)
+}
+
+// colorize formats a whole-number percentage with its window's threshold color
+func colorize(v float64, window string, colors format.Colors) string {
+	return fmt.Sprintf("%s%d%s", colors.UtilizationColor(window, v), int64(v), colors.Reset)
+}
@@ -0,0 +1,93 @@
+package statusline
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/format"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+const stdinJSON = `{
+  "session_id": "abc",
+  "context_window": {
+    "context_window_size": 200000,
+    "current_usage": {
+      "input_tokens": 100000,
+      "output_tokens": 20000,
+      "cache_creation_input_tokens": 10000,
+      "cache_read_input_tokens": 4000
+    }
+  }
+}`
+
+func TestReadInputContextUtilization(t *testing.T) {
+	in := ReadInput(strings.NewReader(stdinJSON))
+	v, ok := in.ContextUtilization()
+	if !ok {
+		t.Fatal("ContextUtilization returned false")
+	}
+	if v != 67 {
+		t.Errorf("ContextUtilization = %v, want 67", v)
+	}
+}
+
+func TestReadInputEmptyOrInvalid(t *testing.T) {
+	for _, input := range []string{"", "not json", `{"context_window": {"context_window_size": 0}}`} {
+		in := ReadInput(strings.NewReader(input))
+		if _, ok := in.ContextUtilization(); ok {
+			t.Errorf("ContextUtilization for %q should be unavailable", input)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	fiveReset := time.Date(2025, 1, 15, 14, 30, 0, 0, time.Local)
+	weekReset := time.Date(2025, 1, 21, 8, 0, 0, 0, time.Local)
+	raw, _ := json.Marshal(map[string]interface{}{
+		"five_hour": map[string]interface{}{"utilization": 45.0, "resets_at": fiveReset},
+		"seven_day": map[string]interface{}{"utilization": 23.0, "resets_at": weekReset},
+	})
+	var usage models.Usage
+	if err := json.Unmarshal(raw, &usage); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	in := ReadInput(strings.NewReader(stdinJSON))
+	got := Render(&usage, in, format.Colors{}, format.DefaultFormats())
+	want := "5h: 45% @ 2:30 PM | wk: 23% @ Tue 8:00 AM | ctx: 67%"
+	if got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMissingData(t *testing.T) {
+	got := Render(nil, Input{}, format.Colors{}, format.DefaultFormats())
+	want := "5h: ?% @ ? | wk: ?% @ ? | ctx: ?%"
+	if got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderRelative(t *testing.T) {
+	formats := format.DefaultFormats()
+	formats.Relative = true
+
+	got := Render(nil, Input{}, format.Colors{}, formats)
+	want := "5h: ?% ? | wk: ?% ? | ctx: ?%"
+	if got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderColors(t *testing.T) {
+	var usage models.Usage
+	_ = json.Unmarshal([]byte(`{"five_hour": {"utilization": 96}}`), &usage)
+
+	got := Render(&usage, Input{}, format.NewANSIColors(), format.DefaultFormats())
+	if !strings.Contains(got, format.Red+"96"+format.Reset) {
+		t.Errorf("Render should color critical utilization red, got %q", got)
+	}
+}
@@ -0,0 +1,391 @@
+// Package telemetry exports traces and metrics to an OpenTelemetry collector
+// over OTLP/HTTP with JSON encoding.
+//
+// It covers the little claude-limits reports: spans around API requests and
+// gauges for utilization. Spans and data points are buffered until Flush,
+// which posts them to the collector's /v1/traces and /v1/metrics endpoints.
+// A nil *Exporter is valid and records nothing, so callers needn't check
+// whether telemetry is enabled.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/version"
+)
+
+// Environment variables read by FromEnv
+const (
+	// EndpointEnv is the collector's OTLP/HTTP base URL, e.g. http://localhost:4318
+	EndpointEnv = "CLAUDE_LIMITS_OTEL_ENDPOINT"
+	// HeadersEnv is the standard OTLP header list, e.g. "api-key=secret,team=ml"
+	HeadersEnv = "OTEL_EXPORTER_OTLP_HEADERS"
+	// ServiceNameEnv overrides the service.name resource attribute
+	ServiceNameEnv = "OTEL_SERVICE_NAME"
+)
+
+// DefaultServiceName is the service.name resource attribute unless overridden
+const DefaultServiceName = "claude-limits"
+
+// exportTimeout bounds each post to the collector
+const exportTimeout = 10 * time.Second
+
+// scopeName identifies the instrumentation in exported data
+const scopeName = "github.com/benjaminabbitt/claude-limits"
+
+// Attr is a span or data point attribute. Value may be a string, bool, int,
+// int64, or float64.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// Exporter buffers spans and gauge readings and posts them to a collector
+type Exporter struct {
+	endpoint   string
+	headers    map[string]string
+	service    string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	spans  []*Span
+	points []point
+}
+
+// point is one gauge reading
+type point struct {
+	name, unit, description string
+	value                   float64
+	time                    time.Time
+	attrs                   []Attr
+}
+
+// New creates an exporter posting to the OTLP/HTTP base URL endpoint
+func New(endpoint string) *Exporter {
+	return &Exporter{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		headers:    make(map[string]string),
+		service:    DefaultServiceName,
+		httpClient: &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// FromEnv creates an exporter from CLAUDE_LIMITS_OTEL_ENDPOINT, with headers
+// from OTEL_EXPORTER_OTLP_HEADERS (comma-separated key=value pairs,
+// URL-encoded as the OpenTelemetry spec requires) and the service name from
+// OTEL_SERVICE_NAME. Returns nil if no endpoint is set.
+func FromEnv() *Exporter {
+	endpoint := os.Getenv(EndpointEnv)
+	if endpoint == "" {
+		return nil
+	}
+
+	e := New(endpoint)
+	if name := os.Getenv(ServiceNameEnv); name != "" {
+		e.service = name
+	}
+	for _, pair := range strings.Split(os.Getenv(HeadersEnv), ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		e.headers[unescape(strings.TrimSpace(key))] = unescape(strings.TrimSpace(value))
+	}
+	return e
+}
+
+// unescape decodes a percent-encoded header key or value, keeping it as
+// given if it isn't valid encoding
+func unescape(s string) string {
+	if decoded, err := url.PathUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// Span is a timed operation. Its methods are no-ops on a nil Span.
+type Span struct {
+	exporter *Exporter
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	client   bool
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	err      error
+}
+
+// Start begins a span, a child of the span in ctx if there is one, and
+// returns a context carrying it. On a nil Exporter it returns ctx and a nil Span.
+func (e *Exporter) Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return e.start(ctx, name, false, attrs)
+}
+
+// StartClient is like Start for a span around an outgoing request
+func (e *Exporter) StartClient(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return e.start(ctx, name, true, attrs)
+}
+
+func (e *Exporter) start(ctx context.Context, name string, client bool, attrs []Attr) (context.Context, *Span) {
+	if e == nil {
+		return ctx, nil
+	}
+
+	s := &Span{
+		exporter: e,
+		spanID:   randomHex(8),
+		name:     name,
+		client:   client,
+		start:    time.Now(),
+		attrs:    attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		s.traceID = randomHex(16)
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttr adds attributes to the span
+func (s *Span) SetAttr(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End finishes the span, marking it failed if err is non-nil, and queues it
+// for export
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+
+	s.exporter.mu.Lock()
+	s.exporter.spans = append(s.exporter.spans, s)
+	s.exporter.mu.Unlock()
+}
+
+// Gauge records a gauge reading, e.g. Gauge("claude_limits.utilization", "%",
+// "...", 42, Attr{"window", "five_hour"})
+func (e *Exporter) Gauge(name, unit, description string, value float64, attrs ...Attr) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.points = append(e.points, point{name, unit, description, value, time.Now(), attrs})
+	e.mu.Unlock()
+}
+
+// Flush posts buffered spans and gauge readings to the collector. Buffers
+// are cleared even if posting fails, so a down collector can't grow memory.
+func (e *Exporter) Flush(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	spans, points := e.spans, e.points
+	e.spans, e.points = nil, nil
+	e.mu.Unlock()
+
+	var errs []error
+	if len(spans) > 0 {
+		errs = append(errs, e.post(ctx, "/v1/traces", e.tracesPayload(spans)))
+	}
+	if len(points) > 0 {
+		errs = append(errs, e.post(ctx, "/v1/metrics", e.metricsPayload(points)))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Exporter) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("telemetry export failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry export to %s returned status %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP JSON encoding; see opentelemetry-proto's trace and metrics protos.
+// 64-bit integers are strings, as the protobuf JSON mapping requires.
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	out := make([]otlpAttr, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch x := a.Value.(type) {
+		case string:
+			v.StringValue = &x
+		case bool:
+			v.BoolValue = &x
+		case int:
+			s := strconv.Itoa(x)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(x, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &x
+		default:
+			s := fmt.Sprint(x)
+			v.StringValue = &s
+		}
+		out = append(out, otlpAttr{Key: a.Key, Value: v})
+	}
+	return out
+}
+
+func (e *Exporter) resource() map[string]interface{} {
+	return map[string]interface{}{
+		"attributes": encodeAttrs([]Attr{
+			{"service.name", e.service},
+			{"service.version", version.Version},
+		}),
+	}
+}
+
+var scope = map[string]string{"name": scopeName, "version": version.Version}
+
+// Span kinds and status codes from the OTLP trace proto
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+func (e *Exporter) tracesPayload(spans []*Span) interface{} {
+	encoded := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		kind := spanKindInternal
+		if s.client {
+			kind = spanKindClient
+		}
+		status := map[string]interface{}{"code": statusCodeOK}
+		if s.err != nil {
+			status = map[string]interface{}{"code": statusCodeError, "message": s.err.Error()}
+		}
+
+		span := map[string]interface{}{
+			"traceId":           s.traceID,
+			"spanId":            s.spanID,
+			"name":              s.name,
+			"kind":              kind,
+			"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.end.UnixNano(), 10),
+			"attributes":        encodeAttrs(s.attrs),
+			"status":            status,
+		}
+		if s.parentID != "" {
+			span["parentSpanId"] = s.parentID
+		}
+		encoded = append(encoded, span)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource":   e.resource(),
+			"scopeSpans": []interface{}{map[string]interface{}{"scope": scope, "spans": encoded}},
+		}},
+	}
+}
+
+func (e *Exporter) metricsPayload(points []point) interface{} {
+	// One metric per name, with a data point per reading
+	var order []string
+	byName := make(map[string]map[string]interface{})
+	for _, p := range points {
+		m, ok := byName[p.name]
+		if !ok {
+			m = map[string]interface{}{
+				"name":        p.name,
+				"unit":        p.unit,
+				"description": p.description,
+				"gauge":       map[string]interface{}{"dataPoints": []interface{}{}},
+			}
+			byName[p.name] = m
+			order = append(order, p.name)
+		}
+		gauge := m["gauge"].(map[string]interface{})
+		gauge["dataPoints"] = append(gauge["dataPoints"].([]interface{}), map[string]interface{}{
+			"timeUnixNano": strconv.FormatInt(p.time.UnixNano(), 10),
+			"asDouble":     p.value,
+			"attributes":   encodeAttrs(p.attrs),
+		})
+	}
+
+	metrics := make([]interface{}, 0, len(order))
+	for _, name := range order {
+		metrics = append(metrics, byName[name])
+	}
+
+	return map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource":     e.resource(),
+			"scopeMetrics": []interface{}{map[string]interface{}{"scope": scope, "metrics": metrics}},
+		}},
+	}
+}
+
+// randomHex returns n random bytes as hex, for trace and span IDs
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
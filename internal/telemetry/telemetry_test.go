@@ -0,0 +1,186 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collector records the JSON bodies posted to each OTLP path
+type collector struct {
+	mu      sync.Mutex
+	bodies  map[string]map[string]interface{}
+	headers http.Header
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	t.Helper()
+	c := &collector{bodies: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid JSON posted to %s: %v", r.URL.Path, err)
+		}
+		c.mu.Lock()
+		c.bodies[r.URL.Path] = body
+		c.headers = r.Header.Clone()
+		c.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+// spans extracts the spans from a traces payload
+func spans(t *testing.T, body map[string]interface{}) []map[string]interface{} {
+	t.Helper()
+	rs := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	ss := rs["scopeSpans"].([]interface{})[0].(map[string]interface{})
+	var out []map[string]interface{}
+	for _, s := range ss["spans"].([]interface{}) {
+		out = append(out, s.(map[string]interface{}))
+	}
+	return out
+}
+
+func TestSpans(t *testing.T) {
+	c, server := newCollector(t)
+	e := New(server.URL)
+
+	ctx, parent := e.Start(context.Background(), "parent")
+	_, child := e.StartClient(ctx, "child", Attr{"http.request.method", "GET"})
+	child.SetAttr(Attr{"http.response.status_code", 503})
+	child.End(errors.New("service unavailable"))
+	parent.End(nil)
+
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	got := spans(t, c.bodies["/v1/traces"])
+	if len(got) != 2 {
+		t.Fatalf("got %d spans, want 2", len(got))
+	}
+	childSpan, parentSpan := got[0], got[1]
+
+	if childSpan["traceId"] != parentSpan["traceId"] {
+		t.Error("child should share the parent's trace ID")
+	}
+	if childSpan["parentSpanId"] != parentSpan["spanId"] {
+		t.Error("child should point to its parent span")
+	}
+	if _, ok := parentSpan["parentSpanId"]; ok {
+		t.Error("root span should have no parent")
+	}
+	if childSpan["kind"] != float64(spanKindClient) || parentSpan["kind"] != float64(spanKindInternal) {
+		t.Errorf("span kinds = %v, %v", childSpan["kind"], parentSpan["kind"])
+	}
+
+	status := childSpan["status"].(map[string]interface{})
+	if status["code"] != float64(statusCodeError) || status["message"] != "service unavailable" {
+		t.Errorf("child status = %v", status)
+	}
+
+	attrs := childSpan["attributes"].([]interface{})
+	code := attrs[1].(map[string]interface{})["value"].(map[string]interface{})
+	if code["intValue"] != "503" {
+		t.Errorf("status code attribute = %v, want intValue 503", code)
+	}
+}
+
+func TestGauges(t *testing.T) {
+	c, server := newCollector(t)
+	e := New(server.URL)
+
+	e.Gauge("claude_limits.utilization", "%", "Utilization", 45, Attr{"window", "five_hour"})
+	e.Gauge("claude_limits.utilization", "%", "Utilization", 23, Attr{"window", "seven_day"})
+
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if _, ok := c.bodies["/v1/traces"]; ok {
+		t.Error("no traces should be posted without spans")
+	}
+
+	rm := c.bodies["/v1/metrics"]["resourceMetrics"].([]interface{})[0].(map[string]interface{})
+	sm := rm["scopeMetrics"].([]interface{})[0].(map[string]interface{})
+	metrics := sm["metrics"].([]interface{})
+	if len(metrics) != 1 {
+		t.Fatalf("got %d metrics, want readings grouped into 1", len(metrics))
+	}
+	points := metrics[0].(map[string]interface{})["gauge"].(map[string]interface{})["dataPoints"].([]interface{})
+	if len(points) != 2 || points[0].(map[string]interface{})["asDouble"] != float64(45) {
+		t.Errorf("data points = %v", points)
+	}
+
+	// Buffers are cleared by Flush
+	c.bodies = make(map[string]map[string]interface{})
+	if err := e.Flush(context.Background()); err != nil || len(c.bodies) != 0 {
+		t.Errorf("second Flush posted %v, err %v", c.bodies, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	if FromEnv() != nil {
+		t.Error("FromEnv should return nil without an endpoint")
+	}
+
+	c, server := newCollector(t)
+	t.Setenv(EndpointEnv, server.URL+"/")
+	t.Setenv(HeadersEnv, "api-key=secret, team=ml,bogus,Authorization=Basic%20eHl6,x-bad=100%")
+	t.Setenv(ServiceNameEnv, "limits-prod")
+
+	e := FromEnv()
+	e.Gauge("g", "1", "", 1)
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if c.headers.Get("api-key") != "secret" || c.headers.Get("team") != "ml" {
+		t.Errorf("headers = %v", c.headers)
+	}
+	// Values are URL-decoded, or kept as given if they aren't valid encoding
+	if got := c.headers.Get("Authorization"); got != "Basic eHl6" {
+		t.Errorf("Authorization = %q, want the decoded value", got)
+	}
+	if got := c.headers.Get("x-bad"); got != "100%" {
+		t.Errorf("x-bad = %q, want it unchanged", got)
+	}
+
+	rm := c.bodies["/v1/metrics"]["resourceMetrics"].([]interface{})[0].(map[string]interface{})
+	attrs := rm["resource"].(map[string]interface{})["attributes"].([]interface{})
+	name := attrs[0].(map[string]interface{})["value"].(map[string]interface{})["stringValue"]
+	if name != "limits-prod" {
+		t.Errorf("service.name = %v", name)
+	}
+}
+
+func TestNilExporter(t *testing.T) {
+	var e *Exporter
+	ctx, span := e.Start(context.Background(), "op")
+	span.SetAttr(Attr{"k", "v"})
+	span.End(nil)
+	e.Gauge("g", "1", "", 1)
+	if ctx == nil || span != nil {
+		t.Error("nil exporter should return the context and a nil span")
+	}
+	if err := e.Flush(context.Background()); err != nil {
+		t.Errorf("Flush on nil exporter = %v", err)
+	}
+}
+
+func TestFlushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	e := New(server.URL)
+	e.Gauge("g", "1", "", 1)
+	if err := e.Flush(context.Background()); err == nil {
+		t.Error("Flush should report a rejected export")
+	}
+}
@@ -0,0 +1,260 @@
+// Package testsupport records API responses to fixture files and replays
+// them, so integrations can be tested without credentials or a network.
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FixtureVersion is the fixture format written by Recorder
+const FixtureVersion = 1
+
+// Fixture is a recorded sequence of API responses
+type Fixture struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response it got. Only what's needed to
+// replay the response is kept; request headers, and with them credentials,
+// never are.
+type Interaction struct {
+	// Method defaults to GET
+	Method string `json:"method,omitempty"`
+	// Path is the request's path and query, e.g. "/api/oauth/usage"
+	Path string `json:"path"`
+	// Status defaults to 200
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	// Body holds a JSON response as JSON, so fixtures are easy to read and
+	// edit; any other response is kept in Text
+	Body json.RawMessage `json:"body,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+func (i Interaction) method() string {
+	if i.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(i.Method)
+}
+
+func (i Interaction) status() int {
+	if i.Status == 0 {
+		return http.StatusOK
+	}
+	return i.Status
+}
+
+func (i Interaction) body() []byte {
+	if len(i.Body) > 0 {
+		return i.Body
+	}
+	return []byte(i.Text)
+}
+
+// skippedHeaders aren't recorded: cookies are credentials, and the length
+// is recomputed on replay
+var skippedHeaders = map[string]bool{
+	"Set-Cookie":     true,
+	"Content-Length": true,
+}
+
+// LoadFixture reads a fixture file. Any other JSON document, such as a
+// saved usage response, is taken as the body of a single response to GET
+// defaultPath.
+func LoadFixture(path, defaultPath string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if _, ok := doc["interactions"]; !ok && defaultPath != "" {
+		return &Fixture{
+			Version:      FixtureVersion,
+			Interactions: []Interaction{{Path: defaultPath, Body: data}},
+		}, nil
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if f.Version > FixtureVersion {
+		return nil, fmt.Errorf("fixture %s is version %d; this build reads up to %d", path, f.Version, FixtureVersion)
+	}
+	if len(f.Interactions) == 0 {
+		return nil, fmt.Errorf("fixture %s has no interactions", path)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to path, readable by the owner only
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Recorder is an http.RoundTripper that saves every response it passes on
+// to a fixture file. The file is rewritten after each response, so a
+// recording is complete however the program exits.
+type Recorder struct {
+	next http.RoundTripper
+	path string
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder records responses from next to path, replacing the file
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, path: path, fixture: Fixture{Version: FixtureVersion}}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	i := Interaction{Method: req.Method, Path: req.URL.RequestURI(), Status: resp.StatusCode}
+	if req.Method == http.MethodGet {
+		i.Method = ""
+	}
+	for key, values := range resp.Header {
+		if skippedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		if i.Header == nil {
+			i.Header = make(http.Header)
+		}
+		i.Header[key] = values
+	}
+	if json.Valid(body) {
+		i.Body = body
+	} else {
+		i.Text = string(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Interactions = append(r.fixture.Interactions, i)
+	if err := r.fixture.Save(r.path); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer serves the responses of a fixture in place of the network, as an
+// http.RoundTripper or an http.Handler.
+//
+// A request gets the next response recorded for its method and path and
+// query, or failing that for its method and path alone, since queries often
+// carry timestamps. Once a request's responses run out, the last one
+// repeats. Requests nothing was recorded for get a 404.
+type Replayer struct {
+	mu      sync.Mutex
+	fixture *Fixture
+	served  map[string]int
+}
+
+// NewReplayer replays the responses in f
+func NewReplayer(f *Fixture) *Replayer {
+	return &Replayer{fixture: f, served: make(map[string]int)}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	status, header, body := r.respond(req)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	status, header, body := r.respond(req)
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func (r *Replayer) respond(req *http.Request) (int, http.Header, []byte) {
+	i, ok := r.next(req.Method, req.URL.RequestURI(), req.URL.Path)
+	if !ok {
+		msg, _ := json.Marshal(fmt.Sprintf("no recorded response for %s %s", req.Method, req.URL.RequestURI()))
+		header := http.Header{"Content-Type": {"application/json"}}
+		return http.StatusNotFound, header, []byte(`{"error":{"type":"not_found_error","message":` + string(msg) + `}}`)
+	}
+	header := i.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return i.status(), header, i.body()
+}
+
+// next picks the response for a request, preferring an exact match
+func (r *Replayer) next(method, uri, path string) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, exact := range []bool{true, false} {
+		var matches []Interaction
+		for _, i := range r.fixture.Interactions {
+			if i.method() != method {
+				continue
+			}
+			recorded, _, _ := strings.Cut(i.Path, "?")
+			if (exact && i.Path == uri) || (!exact && recorded == path) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		key := method + " " + uri
+		if !exact {
+			key = method + " " + path + "?*"
+		}
+		n := r.served[key]
+		r.served[key]++
+		return matches[min(n, len(matches)-1)], true
+	}
+	return Interaction{}, false
+}
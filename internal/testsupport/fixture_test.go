@@ -0,0 +1,144 @@
+package testsupport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string, http.Header) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), resp.Header
+}
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("Anthropic-Ratelimit-Requests-Remaining", "9")
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("<html>bad gateway</html>"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"five_hour":{"utilization":45}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recording := &http.Client{Transport: NewRecorder(path, nil)}
+	if status, body, _ := get(t, recording, server.URL+"/api/oauth/usage"); status != 200 || !strings.Contains(body, "45") {
+		t.Fatalf("recorded response = %d %q", status, body)
+	}
+	get(t, recording, server.URL+"/down")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"secret-token", "session=abc"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("fixture contains %q:\n%s", leaked, data)
+		}
+	}
+
+	f, err := LoadFixture(path, "")
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	replaying := &http.Client{Transport: NewReplayer(f)}
+
+	status, body, header := get(t, replaying, "http://replay.invalid/api/oauth/usage")
+	if status != 200 || !strings.Contains(body, `"utilization": 45`) {
+		t.Errorf("replayed usage = %d %q", status, body)
+	}
+	if header.Get("Anthropic-Ratelimit-Requests-Remaining") != "9" {
+		t.Errorf("replayed headers = %v", header)
+	}
+
+	if status, body, _ := get(t, replaying, "http://replay.invalid/down"); status != http.StatusBadGateway || body != "<html>bad gateway</html>" {
+		t.Errorf("replayed error = %d %q", status, body)
+	}
+}
+
+func TestReplayerSequence(t *testing.T) {
+	replayer := NewReplayer(&Fixture{Interactions: []Interaction{
+		{Path: "/usage?day=1", Body: []byte(`1`)},
+		{Path: "/usage", Body: []byte(`2`)},
+		{Path: "/usage", Status: http.StatusTooManyRequests, Body: []byte(`3`)},
+	}})
+	server := httptest.NewServer(replayer)
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/usage?day=1", 200, "1"},
+		{"/usage?day=1", 200, "1"}, // last response repeats
+		{"/usage", 200, "2"},
+		{"/usage", 429, "3"},
+		{"/usage", 429, "3"},
+		{"/usage?day=2", 200, "1"}, // falls back to the path alone
+		{"/other", 404, "no recorded response for GET /other"},
+	}
+	for _, tt := range tests {
+		status, body, _ := get(t, server.Client(), server.URL+tt.path)
+		if status != tt.status || !strings.Contains(body, tt.body) {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, status, body, tt.status, tt.body)
+		}
+	}
+}
+
+func TestLoadFixtureErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"empty.json":  `{"version":1,"interactions":[]}`,
+		"array.json":  `[1, 2]`,
+		"future.json": `{"version":99,"interactions":[{"path":"/"}]}`,
+		"bad.json":    `not json`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFixture(path, "/usage"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := LoadFixture(filepath.Join(dir, "missing.json"), ""); err == nil {
+		t.Error("missing file: expected error")
+	}
+}
+
+func TestLoadFixtureBareDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	if err := os.WriteFile(path, []byte(`{"five_hour":{"utilization":45}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFixture(path, "/api/oauth/usage")
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	if len(f.Interactions) != 1 || f.Interactions[0].Path != "/api/oauth/usage" || string(f.Interactions[0].Body) != `{"five_hour":{"utilization":45}}` {
+		t.Errorf("fixture = %+v", f)
+	}
+
+	if _, err := LoadFixture(path, ""); err == nil {
+		t.Error("expected error without a default path")
+	}
+}
@@ -0,0 +1,127 @@
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultErrorStatuses are the failures a MockServer simulates when none
+// are given
+var DefaultErrorStatuses = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
+// shutdownTimeout bounds how long Serve waits for requests in flight
+const shutdownTimeout = 5 * time.Second
+
+// MockServer emulates the API by replaying a fixture, optionally slowly or
+// with simulated failures, for developing against CLAUDE_API_BASE_URL
+type MockServer struct {
+	replayer   *Replayer
+	latency    time.Duration
+	errorRate  float64
+	statuses   []int
+	retryAfter time.Duration
+	// roll returns a number in [0, 1); replaced in tests
+	roll func() float64
+}
+
+// MockOption configures a MockServer
+type MockOption func(*MockServer)
+
+// WithLatency delays every response by d
+func WithLatency(d time.Duration) MockOption {
+	return func(m *MockServer) {
+		if d > 0 {
+			m.latency = d
+		}
+	}
+}
+
+// WithErrors fails a fraction rate (0 to 1) of requests with one of
+// statuses, picked at random, or DefaultErrorStatuses if none are given
+func WithErrors(rate float64, statuses ...int) MockOption {
+	return func(m *MockServer) {
+		m.errorRate = min(max(rate, 0), 1)
+		if len(statuses) > 0 {
+			m.statuses = statuses
+		}
+	}
+}
+
+// WithRetryAfter sets the Retry-After header of simulated 429 and 503
+// responses (default: 1s). Zero leaves it out.
+func WithRetryAfter(d time.Duration) MockOption {
+	return func(m *MockServer) {
+		if d >= 0 {
+			m.retryAfter = d
+		}
+	}
+}
+
+// NewMockServer serves the responses in f
+func NewMockServer(f *Fixture, opts ...MockOption) *MockServer {
+	m := &MockServer{
+		replayer:   NewReplayer(f),
+		statuses:   DefaultErrorStatuses,
+		retryAfter: time.Second,
+		roll:       rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.latency > 0 {
+		select {
+		case <-time.After(m.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if m.errorRate > 0 && m.roll() < m.errorRate {
+		status := m.statuses[int(m.roll()*float64(len(m.statuses)))]
+		if m.retryAfter > 0 && (status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(m.retryAfter.Round(time.Second)/time.Second)))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"error":{"type":"simulated_error","message":"simulated %d %s"}}`, status, http.StatusText(status))
+		return
+	}
+
+	m.replayer.ServeHTTP(w, r)
+}
+
+// Serve serves requests on ln until ctx is done
+func (m *MockServer) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{Handler: m, ReadHeaderTimeout: 10 * time.Second}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package testsupport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var usageFixture = &Fixture{Interactions: []Interaction{{Path: "/api/oauth/usage", Body: []byte(`{"five_hour":{"utilization":45}}`)}}}
+
+func TestMockServerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []MockOption
+		rolls      []float64
+		status     int
+		retryAfter string
+	}{
+		{"no errors", nil, nil, http.StatusOK, ""},
+		{"roll above rate", []MockOption{WithErrors(0.5)}, []float64{0.7}, http.StatusOK, ""},
+		{"429", []MockOption{WithErrors(0.5)}, []float64{0.2, 0.1}, http.StatusTooManyRequests, "1"},
+		{"503", []MockOption{WithErrors(0.5)}, []float64{0.2, 0.9}, http.StatusServiceUnavailable, "1"},
+		{"custom status", []MockOption{WithErrors(1, 500)}, []float64{0.2, 0.9}, http.StatusInternalServerError, ""},
+		{"retry after", []MockOption{WithErrors(1, 429), WithRetryAfter(30 * time.Second)}, []float64{0, 0}, http.StatusTooManyRequests, "30"},
+		{"no retry after", []MockOption{WithErrors(1, 429), WithRetryAfter(0)}, []float64{0, 0}, http.StatusTooManyRequests, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockServer(usageFixture, tt.opts...)
+			rolls := tt.rolls
+			m.roll = func() float64 {
+				r := rolls[0]
+				rolls = rolls[1:]
+				return r
+			}
+
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/oauth/usage", nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			if tt.status == http.StatusOK && !strings.Contains(w.Body.String(), "45") {
+				t.Errorf("body = %s, want the fixture's", w.Body)
+			}
+		})
+	}
+}
+
+func TestMockServerLatency(t *testing.T) {
+	m := NewMockServer(usageFixture, WithLatency(50*time.Millisecond))
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/oauth/usage", nil))
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("responded after %s, want at least 50ms", elapsed)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d", w.Code)
+	}
+}
+
+func TestMockServerServe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		errc <- NewMockServer(usageFixture).Serve(ctx, ln)
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/api/oauth/usage")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-errc; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+// Package transcripts reads Claude Code session transcripts to total token
+// usage per session and project.
+//
+// Claude Code writes one JSONL file per session under
+// ~/.claude/projects/<project>/ (or under CLAUDE_CONFIG_DIR), with an entry per message. Assistant entries
+// carry the model and the token usage of the API request behind them.
+package transcripts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/claudecode"
+)
+
+// maxLineSize bounds a single transcript entry; tool results can be large
+const maxLineSize = 16 * 1024 * 1024
+
+// Tokens are token counts, split the way the API bills them
+type Tokens struct {
+	Input         int64 `json:"input_tokens"`
+	Output        int64 `json:"output_tokens"`
+	CacheCreation int64 `json:"cache_creation_input_tokens"`
+	CacheRead     int64 `json:"cache_read_input_tokens"`
+}
+
+// Total returns the sum of all token counts
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output + t.CacheCreation + t.CacheRead
+}
+
+func (t *Tokens) add(o Tokens) {
+	t.Input += o.Input
+	t.Output += o.Output
+	t.CacheCreation += o.CacheCreation
+	t.CacheRead += o.CacheRead
+}
+
+// Session is the token usage of one Claude Code session
+type Session struct {
+	ID       string    `json:"id"`
+	Project  string    `json:"project"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Messages int       `json:"messages"`
+	Models   []string  `json:"models"`
+	Tokens   Tokens    `json:"tokens"`
+}
+
+// Project is the token usage of every session in a working directory
+type Project struct {
+	Path       string    `json:"path"`
+	Sessions   int       `json:"sessions"`
+	Messages   int       `json:"messages"`
+	LastActive time.Time `json:"last_active"`
+	Tokens     Tokens    `json:"tokens"`
+}
+
+// entry is the subset of a transcript line used for accounting
+type entry struct {
+	Type      string    `json:"type"`
+	SessionID string    `json:"sessionId"`
+	Cwd       string    `json:"cwd"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId"`
+	Message   struct {
+		ID    string  `json:"id"`
+		Model string  `json:"model"`
+		Usage *Tokens `json:"usage"`
+	} `json:"message"`
+}
+
+// DefaultDir returns the directory Claude Code keeps transcripts in, under
+// its config directory (see claudecode.Dir)
+func DefaultDir() string {
+	dir := claudecode.Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "projects")
+}
+
+// Read totals the token usage of messages sent at or after since in every
+// transcript under dir, one Session per session ID, most recent first.
+// A missing dir yields no sessions.
+func Read(dir string, since time.Time) ([]Session, error) {
+	r := newReader(since)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		// Files untouched since the cutoff can't hold newer messages
+		if info, err := d.Info(); err == nil && info.ModTime().Before(since) {
+			return nil
+		}
+		return r.readFile(path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcripts: %w", err)
+	}
+
+	return r.sessions(), nil
+}
+
+// reader accumulates sessions across transcript files
+type reader struct {
+	since time.Time
+	// seen holds message and request IDs already counted. Claude Code writes
+	// an entry per content block of a response, each repeating its usage, and
+	// resumed sessions copy earlier messages into a new file.
+	seen   map[string]bool
+	byID   map[string]*Session
+	models map[string]map[string]bool
+}
+
+func newReader(since time.Time) *reader {
+	return &reader{
+		since:  since,
+		seen:   make(map[string]bool),
+		byID:   make(map[string]*Session),
+		models: make(map[string]map[string]bool),
+	}
+}
+
+func (r *reader) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fallback := session{
+		id:      strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		project: filepath.Base(filepath.Dir(path)),
+	}
+	return r.read(f, fallback)
+}
+
+// session identifies entries that lack a session ID or working directory
+type session struct {
+	id, project string
+}
+
+// read accumulates the entries of one transcript. Malformed lines are
+// skipped, since a session being written may end in a partial line.
+func (r *reader) read(in io.Reader, fallback session) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Type != "assistant" || e.Message.Usage == nil || e.Timestamp.Before(r.since) {
+			continue
+		}
+
+		if key := e.Message.ID + "/" + e.RequestID; key != "/" {
+			if r.seen[key] {
+				continue
+			}
+			r.seen[key] = true
+		}
+
+		r.add(e, fallback)
+	}
+	return scanner.Err()
+}
+
+func (r *reader) add(e entry, fallback session) {
+	id := e.SessionID
+	if id == "" {
+		id = fallback.id
+	}
+
+	s, ok := r.byID[id]
+	if !ok {
+		s = &Session{ID: id, Project: fallback.project, Start: e.Timestamp}
+		r.byID[id] = s
+		r.models[id] = make(map[string]bool)
+	}
+	if e.Cwd != "" {
+		s.Project = e.Cwd
+	}
+	if e.Timestamp.Before(s.Start) {
+		s.Start = e.Timestamp
+	}
+	if e.Timestamp.After(s.End) {
+		s.End = e.Timestamp
+	}
+	// Synthetic messages (e.g., API errors) aren't billed
+	if e.Message.Model != "" && e.Message.Model != "<synthetic>" {
+		r.models[id][e.Message.Model] = true
+	}
+	s.Messages++
+	s.Tokens.add(*e.Message.Usage)
+}
+
+func (r *reader) sessions() []Session {
+	sessions := make([]Session, 0, len(r.byID))
+	for id, s := range r.byID {
+		for model := range r.models[id] {
+			s.Models = append(s.Models, model)
+		}
+		sort.Strings(s.Models)
+		sessions = append(sessions, *s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].End.After(sessions[j].End)
+	})
+	return sessions
+}
+
+// Projects groups sessions by project, largest token total first
+func Projects(sessions []Session) []Project {
+	byPath := make(map[string]*Project)
+	for _, s := range sessions {
+		p, ok := byPath[s.Project]
+		if !ok {
+			p = &Project{Path: s.Project}
+			byPath[s.Project] = p
+		}
+		p.Sessions++
+		p.Messages += s.Messages
+		p.Tokens.add(s.Tokens)
+		if s.End.After(p.LastActive) {
+			p.LastActive = s.End
+		}
+	}
+
+	projects := make([]Project, 0, len(byPath))
+	for _, p := range byPath {
+		projects = append(projects, *p)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if ti, tj := projects[i].Tokens.Total(), projects[j].Tokens.Total(); ti != tj {
+			return ti > tj
+		}
+		return projects[i].Path < projects[j].Path
+	})
+	return projects
+}
+
+// Total sums the tokens of every session
+func Total(sessions []Session) Tokens {
+	var t Tokens
+	for _, s := range sessions {
+		t.add(s.Tokens)
+	}
+	return t
+}
@@ -0,0 +1,124 @@
+package transcripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const sessionA = `{"type":"user","sessionId":"a","cwd":"/work/api","timestamp":"2030-01-01T10:00:00Z","message":{"role":"user","content":"hi"}}
+{"type":"assistant","sessionId":"a","cwd":"/work/api","timestamp":"2030-01-01T10:00:05Z","requestId":"req_1","message":{"id":"msg_1","model":"claude-opus-4","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}}}
+{"type":"assistant","sessionId":"a","cwd":"/work/api","timestamp":"2030-01-01T10:00:06Z","requestId":"req_1","message":{"id":"msg_1","model":"claude-opus-4","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}}}
+{"type":"assistant","sessionId":"a","cwd":"/work/api","timestamp":"2030-01-01T11:00:00Z","requestId":"req_2","message":{"id":"msg_2","model":"claude-sonnet-4","usage":{"input_tokens":5,"output_tokens":5}}}
+{"type":"assistant","sessionId":"a","cwd":"/work/api","timestamp":"2030-01-01T11:00:01Z","requestId":"req_3","message":{"id":"msg_3","model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}
+not json
+`
+
+const sessionB = `{"type":"assistant","sessionId":"b","cwd":"/work/web","timestamp":"2029-12-01T09:00:00Z","requestId":"req_old","message":{"id":"msg_old","model":"claude-opus-4","usage":{"input_tokens":999,"output_tokens":999}}}
+{"type":"assistant","sessionId":"b","cwd":"/work/web","timestamp":"2030-01-02T09:00:00Z","requestId":"req_4","message":{"id":"msg_4","model":"claude-opus-4","usage":{"input_tokens":1,"output_tokens":2}}}
+`
+
+// sessionAResumed is a resumed session whose file repeats a message from session a
+const sessionAResumed = `{"type":"assistant","sessionId":"c","cwd":"/work/api","timestamp":"2030-01-01T10:00:05Z","requestId":"req_1","message":{"id":"msg_1","model":"claude-opus-4","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}}}
+{"type":"assistant","sessionId":"c","cwd":"/work/api","timestamp":"2030-01-03T08:00:00Z","requestId":"req_5","message":{"id":"msg_5","model":"claude-opus-4","usage":{"input_tokens":3,"output_tokens":4}}}
+`
+
+func writeTranscripts(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"-work-api/a.jsonl":   sessionA,
+		"-work-web/b.jsonl":   sessionB,
+		"-work-api/c.jsonl":   sessionAResumed,
+		"-work-api/notes.txt": "ignored",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		// Read skips files last modified before its cutoff
+		modified := time.Date(2030, 1, 4, 0, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRead(t *testing.T) {
+	dir := writeTranscripts(t)
+	since := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sessions, err := Read(dir, since)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3: %+v", len(sessions), sessions)
+	}
+
+	// Most recent first
+	if sessions[0].ID != "c" || sessions[1].ID != "b" || sessions[2].ID != "a" {
+		t.Errorf("session order = %s, %s, %s", sessions[0].ID, sessions[1].ID, sessions[2].ID)
+	}
+
+	a := sessions[2]
+	if a.Project != "/work/api" {
+		t.Errorf("project = %q", a.Project)
+	}
+	// msg_1 counts once despite repeating within a and again in c
+	want := Tokens{Input: 15, Output: 25, CacheCreation: 100, CacheRead: 1000}
+	if a.Tokens != want {
+		t.Errorf("tokens = %+v, want %+v", a.Tokens, want)
+	}
+	if a.Messages != 3 {
+		t.Errorf("messages = %d, want 3", a.Messages)
+	}
+	if len(a.Models) != 2 || a.Models[0] != "claude-opus-4" || a.Models[1] != "claude-sonnet-4" {
+		t.Errorf("models = %v", a.Models)
+	}
+
+	// Messages before since are excluded
+	if b := sessions[1]; b.Tokens.Total() != 3 {
+		t.Errorf("session b total = %d, want 3", b.Tokens.Total())
+	}
+}
+
+func TestReadMissingDir(t *testing.T) {
+	sessions, err := Read(filepath.Join(t.TempDir(), "missing"), time.Time{})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("got %d sessions, want none", len(sessions))
+	}
+}
+
+func TestProjects(t *testing.T) {
+	sessions, err := Read(writeTranscripts(t), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	projects := Projects(sessions)
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+
+	api := projects[0]
+	if api.Path != "/work/api" || api.Sessions != 2 || api.Tokens.Total() != 1140+7 {
+		t.Errorf("api project = %+v", api)
+	}
+	if !api.LastActive.Equal(time.Date(2030, 1, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("last active = %v", api.LastActive)
+	}
+
+	if total := Total(sessions).Total(); total != 1140+7+3 {
+		t.Errorf("Total = %d", total)
+	}
+}
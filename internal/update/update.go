@@ -0,0 +1,199 @@
+// Package update checks for newer claude-limits releases on GitHub.
+//
+// Checks are rate limited: the latest version is saved with the time it was
+// checked, and GitHub is asked again only once CheckInterval has passed,
+// whether or not the last check succeeded.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/version"
+)
+
+// ReleasesURL is the GitHub API endpoint for the latest release
+const ReleasesURL = "https://api.github.com/repos/benjaminabbitt/claude-limits/releases/latest"
+
+// ReleasesPage is where users download the latest release
+const ReleasesPage = "https://github.com/benjaminabbitt/claude-limits/releases/latest"
+
+// CheckInterval is how long a check's result is reused
+const CheckInterval = 24 * time.Hour
+
+// requestTimeout bounds the request to GitHub
+const requestTimeout = 5 * time.Second
+
+// state is the last check's result, saved between runs
+type state struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest,omitempty"`
+}
+
+// Checker looks up the latest release, caching the result
+type Checker struct {
+	path       string
+	url        string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// Option configures a Checker
+type Option func(*Checker)
+
+// WithURL queries url instead of ReleasesURL
+func WithURL(url string) Option {
+	return func(c *Checker) {
+		c.url = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to query GitHub
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Checker) {
+		c.httpClient = client
+	}
+}
+
+// New creates a Checker that saves its state at path
+func New(path string, opts ...Option) *Checker {
+	c := &Checker{
+		path:       path,
+		url:        ReleasesURL,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// StatePath returns the default path of the check's saved state
+func StatePath() string {
+	return filepath.Join(cache.DefaultDir(), "update.json")
+}
+
+// Latest returns the latest release version, e.g. "v1.4.0": the saved one if
+// checked within CheckInterval, otherwise from GitHub. A failed check is
+// saved too, so it isn't retried until the interval passes.
+func (c *Checker) Latest(ctx context.Context) (string, error) {
+	s := c.load()
+	if c.now().Sub(s.CheckedAt) < CheckInterval {
+		return s.Latest, nil
+	}
+
+	latest, err := c.fetch(ctx)
+	s.CheckedAt = c.now()
+	if err == nil {
+		s.Latest = latest
+	}
+	c.save(s)
+	if err != nil {
+		return "", err
+	}
+	return latest, nil
+}
+
+func (c *Checker) load() state {
+	var s state
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return s
+	}
+	_ = json.Unmarshal(data, &s)
+	return s
+}
+
+// save writes the state, ignoring errors: a check that can't be saved is
+// simply repeated next time
+func (c *Checker) save(s state) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	if err := cache.EnsureDir(filepath.Dir(c.path)); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path, data, cache.FileMode)
+}
+
+func (c *Checker) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "claude-limits/"+version.Version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("release check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("release check returned status %d", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("release has no tag")
+	}
+	return release.TagName, nil
+}
+
+// Newer reports whether version latest is newer than current. Versions are
+// compared as major.minor.patch, with an optional "v" prefix; anything else,
+// such as a "dev" build, is never reported as outdated.
+func Newer(latest, current string) bool {
+	l, ok := parse(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parse(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parse splits a version into major, minor, and patch, ignoring any
+// pre-release or build suffix
+func parse(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
@@ -0,0 +1,86 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"1.2.0", "v1.2.0", false},
+		{"v1.2.0", "v1.2.1", false},
+		{"v2.0.0-rc.1", "v1.9.0", true},
+		{"v1.3", "v1.2.5", true},
+		{"v1.2.0", "dev", false},
+		{"nightly", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestLatestRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"tag_name": "v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "update.json")
+	c := New(path, WithURL(server.URL))
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		latest, err := c.Latest(context.Background())
+		if err != nil || latest != "v1.4.0" {
+			t.Fatalf("Latest() = %q, %v", latest, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests within the interval, want 1", requests)
+	}
+
+	// A new checker reads the saved state
+	again := New(path, WithURL(server.URL))
+	again.now = func() time.Time { return now.Add(time.Hour) }
+	if latest, _ := again.Latest(context.Background()); latest != "v1.4.0" || requests != 1 {
+		t.Errorf("saved Latest() = %q after %d requests", latest, requests)
+	}
+
+	// Once the interval passes, GitHub is asked again
+	again.now = func() time.Time { return now.Add(CheckInterval) }
+	if _, err := again.Latest(context.Background()); err != nil || requests != 2 {
+		t.Errorf("expired Latest() err %v after %d requests, want 2", err, requests)
+	}
+}
+
+func TestLatestFailureIsSaved(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := New(filepath.Join(t.TempDir(), "update.json"), WithURL(server.URL))
+	if _, err := c.Latest(context.Background()); err == nil {
+		t.Fatal("Latest should report a rejected request")
+	}
+	if latest, err := c.Latest(context.Background()); err != nil || latest != "" || requests != 1 {
+		t.Errorf("retry Latest() = %q, %v after %d requests, want no new request", latest, err, requests)
+	}
+}
@@ -0,0 +1,177 @@
+// Package usecase holds the fetch logic shared by every front-end: the CLI,
+// the MCP server, the daemon, and the public library.
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+)
+
+// CredentialsFunc resolves the credentials to fetch with. It's called on
+// every fetch that reaches the API, so a refreshed token is picked up.
+type CredentialsFunc func(ctx context.Context) (*auth.Credentials, error)
+
+// ClientFunc creates an API client for an access token
+type ClientFunc func(accessToken string) (*api.Client, error)
+
+// Refresh is how a fetch treats a fresh cache entry
+type Refresh int
+
+const (
+	// RefreshStale serves a fresh cache entry, fetching only once it expires
+	RefreshStale Refresh = iota
+	// RefreshAlways fetches from the API and writes the cache, so pollers
+	// keep it warm for other consumers
+	RefreshAlways
+)
+
+// UsageService fetches usage from the cache when fresh, otherwise from the
+// API, revalidating an expired cache entry with a conditional request
+type UsageService struct {
+	credentials CredentialsFunc
+	newClient   ClientFunc
+	cache       *cache.Cache
+	ttl         time.Duration
+	refresh     Refresh
+	noRevalid   bool
+	historyPath string
+}
+
+// Option configures a UsageService
+type Option func(*UsageService)
+
+// WithClient sets how API clients are created (default: api.NewClient with
+// no options)
+func WithClient(newClient ClientFunc) Option {
+	return func(s *UsageService) {
+		s.newClient = newClient
+	}
+}
+
+// WithCache serves responses younger than ttl from c, and writes fetched
+// ones to it. With a zero ttl, only RefreshAlways uses the cache, to keep
+// it warm. Caching is disabled by default.
+func WithCache(c *cache.Cache, ttl time.Duration) Option {
+	return func(s *UsageService) {
+		s.cache = c
+		s.ttl = ttl
+	}
+}
+
+// WithRefresh sets how fetches treat a fresh cache entry (default:
+// RefreshStale)
+func WithRefresh(r Refresh) Option {
+	return func(s *UsageService) {
+		s.refresh = r
+	}
+}
+
+// WithRevalidation sets whether an expired cache entry is revalidated
+// (default: true). Disable it when the response body itself is needed.
+func WithRevalidation(revalidate bool) Option {
+	return func(s *UsageService) {
+		s.noRevalid = !revalidate
+	}
+}
+
+// WithHistory records usage fetched from the API to the history database
+// at path. Failures are logged rather than failing the fetch.
+func WithHistory(path string) Option {
+	return func(s *UsageService) {
+		s.historyPath = path
+	}
+}
+
+// NewUsageService creates a UsageService fetching with credentials
+func NewUsageService(credentials CredentialsFunc, opts ...Option) *UsageService {
+	s := &UsageService{
+		credentials: credentials,
+		newClient: func(accessToken string) (*api.Client, error) {
+			return api.NewClient(accessToken), nil
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Usage returns current usage. Cancelling ctx aborts the request and any
+// pending retries.
+func (s *UsageService) Usage(ctx context.Context) (*models.Usage, error) {
+	useCache := s.cache != nil && (s.ttl > 0 || s.refresh == RefreshAlways)
+	if useCache && s.ttl > 0 && s.refresh == RefreshStale {
+		if cached, err := s.cache.Read(s.ttl); err == nil {
+			slog.Debug("using cached data")
+			return cached, nil
+		}
+	}
+
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("using Claude Code credentials", "subscription", creds.SubscriptionType)
+	if creds.IsExpired() {
+		slog.Debug("access token may be expired")
+	}
+	client, err := s.newClient(creds.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revalidate an expired entry, so an unchanged response costs a 304
+	// rather than a full download
+	var stale *cache.Data
+	var validators api.Validators
+	if useCache && !s.noRevalid {
+		if stale, _ = s.cache.ReadStale(); stale != nil {
+			validators = api.Validators{ETag: stale.ETag, LastModified: stale.LastModified}
+		}
+	}
+
+	usage, fresh, err := client.GetUsageIfModified(ctx, validators)
+	if apierrors.Is(err, apierrors.ErrNotModified) {
+		slog.Debug("usage not modified; reusing cached data")
+		usage, err = s.cache.Usage(stale)
+		fresh = validators
+	}
+	if err != nil {
+		return nil, err
+	}
+	if unknown := usage.UnknownFields(); len(unknown) > 0 {
+		slog.Debug("usage response has unrecognized fields; the API may have changed", "fields", unknown)
+	}
+
+	// A failed cache write shouldn't fail a successful fetch
+	if useCache {
+		if err := s.cache.WriteValidated(usage, fresh.ETag, fresh.LastModified); err != nil {
+			slog.Debug("failed to write cache", "error", err)
+		}
+	}
+	if s.historyPath != "" {
+		s.recordHistory(usage)
+	}
+	return usage, nil
+}
+
+func (s *UsageService) recordHistory(usage *models.Usage) {
+	store, err := history.Open(s.historyPath)
+	if err != nil {
+		slog.Debug("failed to open history", "error", err)
+		return
+	}
+	defer store.Close()
+
+	if err := store.Record(usage, time.Now()); err != nil {
+		slog.Debug("failed to record history", "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	"github.com/benjaminabbitt/claude-limits/internal/history"
+)
+
+// newTestService serves usage from a test server, counting requests and
+// recording each one's bearer token
+func newTestService(t *testing.T, tokens *[]string, opts ...Option) *UsageService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*tokens = append(*tokens, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 62}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	n := 0
+	credentials := func(ctx context.Context) (*auth.Credentials, error) {
+		n++
+		return &auth.Credentials{AccessToken: "token-" + strconv.Itoa(n)}, nil
+	}
+	client := WithClient(func(accessToken string) (*api.Client, error) {
+		return api.NewClient(accessToken, api.WithBaseURL(server.URL)), nil
+	})
+	return NewUsageService(credentials, append([]Option{client}, opts...)...)
+}
+
+func TestUsageReloadsCredentials(t *testing.T) {
+	var tokens []string
+	s := newTestService(t, &tokens)
+
+	for i := 0; i < 2; i++ {
+		usage, err := s.Usage(context.Background())
+		if err != nil {
+			t.Fatalf("Usage: %v", err)
+		}
+		if usage.FiveHour == nil || usage.FiveHour.Utilization != 62 {
+			t.Errorf("FiveHour = %+v, want utilization 62", usage.FiveHour)
+		}
+	}
+	if len(tokens) != 2 || tokens[0] != "Bearer token-1" || tokens[1] != "Bearer token-2" {
+		t.Errorf("tokens = %v, want each fetch to load credentials", tokens)
+	}
+}
+
+func TestUsageCache(t *testing.T) {
+	var tokens []string
+	c := cache.New(false, cache.WithDir(t.TempDir()))
+	s := newTestService(t, &tokens, WithCache(c, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.Usage(context.Background()); err != nil {
+			t.Fatalf("Usage: %v", err)
+		}
+	}
+	if len(tokens) != 1 {
+		t.Errorf("server hit %d times, want 1", len(tokens))
+	}
+}
+
+func TestUsageRefreshAlways(t *testing.T) {
+	var tokens []string
+	c := cache.New(false, cache.WithDir(t.TempDir()))
+	s := newTestService(t, &tokens, WithCache(c, 0), WithRefresh(RefreshAlways))
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Usage(context.Background()); err != nil {
+			t.Fatalf("Usage: %v", err)
+		}
+	}
+	if len(tokens) != 2 {
+		t.Errorf("server hit %d times, want 2", len(tokens))
+	}
+	// The cache is kept warm for other consumers
+	if _, err := c.Read(time.Minute); err != nil {
+		t.Errorf("cache not written: %v", err)
+	}
+}
+
+func TestUsageRecordsHistory(t *testing.T) {
+	var tokens []string
+	path := filepath.Join(t.TempDir(), "history.db")
+	c := cache.New(false, cache.WithDir(t.TempDir()))
+	s := newTestService(t, &tokens, WithCache(c, time.Minute), WithHistory(path))
+
+	// Only the fetch is recorded, not the cache hit
+	for i := 0; i < 2; i++ {
+		if _, err := s.Usage(context.Background()); err != nil {
+			t.Fatalf("Usage: %v", err)
+		}
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	snapshots, err := store.Query(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("recorded %d snapshots, want 1", len(snapshots))
+	}
+}
@@ -0,0 +1,239 @@
+// Package claudelimits is a library for querying Claude.ai Pro/Max usage limits.
+//
+// It wraps the same API client, credential loading, and cache used by the
+// claude-limits CLI:
+//
+//	client, err := claudelimits.New(claudelimits.WithCache(30 * time.Second))
+//	if err != nil {
+//		return err
+//	}
+//	usage, err := client.Usage(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	if w := usage.FiveHour; w != nil {
+//		fmt.Printf("5h: %.0f%%\n", w.Utilization)
+//	}
+package claudelimits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/benjaminabbitt/claude-limits/internal/api"
+	"github.com/benjaminabbitt/claude-limits/internal/auth"
+	"github.com/benjaminabbitt/claude-limits/internal/cache"
+	apierrors "github.com/benjaminabbitt/claude-limits/internal/errors"
+	"github.com/benjaminabbitt/claude-limits/internal/models"
+	"github.com/benjaminabbitt/claude-limits/internal/usecase"
+)
+
+// Usage is a usage response with typed windows and the raw JSON preserved
+type Usage = models.Usage
+
+// Window is a single rate-limit window
+type Window = models.Window
+
+// NamedWindow pairs a Window with its API field name
+type NamedWindow = models.NamedWindow
+
+// Credentials are the OAuth credentials written by Claude Code
+type Credentials = auth.Credentials
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy = api.RetryPolicy
+
+// APIError is a failed API request: its status, and the error type and
+// message from the response body
+type APIError = apierrors.APIError
+
+// Errors returned by Client, to check with errors.Is. Failed requests match
+// the sentinel for their error type or status, e.g. a rejected token
+// matches ErrAuthRequired.
+var (
+	ErrAuthRequired        = apierrors.ErrAuthRequired
+	ErrCredentialsNotFound = apierrors.ErrCredentialsNotFound
+	ErrPermissionDenied    = apierrors.ErrPermissionDenied
+	ErrRateLimited         = apierrors.ErrRateLimited
+	ErrOverloaded          = apierrors.ErrOverloaded
+	ErrRequestFailed       = apierrors.ErrRequestFailed
+)
+
+// Window names as they appear in the API response
+const (
+	WindowFiveHour          = models.WindowFiveHour
+	WindowSevenDay          = models.WindowSevenDay
+	WindowSevenDayOpus      = models.WindowSevenDayOpus
+	WindowSevenDaySonnet    = models.WindowSevenDaySonnet
+	WindowSevenDayOAuthApps = models.WindowSevenDayOAuthApps
+)
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return api.DefaultRetryPolicy()
+}
+
+// DefaultCredentialsPath returns the default path to Claude Code credentials
+func DefaultCredentialsPath() string {
+	return auth.DefaultCredentialsPath()
+}
+
+// LoadCredentials reads Claude Code OAuth credentials from path.
+// If path is empty, uses DefaultCredentialsPath.
+func LoadCredentials(path string) (*Credentials, error) {
+	return auth.Load(path)
+}
+
+// options collects settings applied by Option
+type options struct {
+	accessToken     string
+	credentialsPath string
+	baseURL         string
+	httpClient      *http.Client
+	timeout         time.Duration
+	retryPolicy     *RetryPolicy
+	cacheTTL        time.Duration
+	cacheDir        string
+	profile         string
+}
+
+// Option configures a Client
+type Option func(*options)
+
+// WithAccessToken uses the given OAuth access token instead of loading
+// credentials. With WithCache, it also needs WithProfile or WithCacheDir, so
+// the token's usage isn't mixed up with the CLI's cached account.
+func WithAccessToken(token string) Option {
+	return func(o *options) {
+		o.accessToken = token
+	}
+}
+
+// WithCredentialsPath loads credentials from a non-default path
+func WithCredentialsPath(path string) Option {
+	return func(o *options) {
+		o.credentialsPath = path
+	}
+}
+
+// WithBaseURL sets a custom API base URL
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the time limit for each request attempt (default 30s)
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried. Zero backoffs keep
+// their defaults. See DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &p
+	}
+}
+
+// WithCache enables the on-disk cache shared with the CLI. Responses younger
+// than ttl are served from the cache. Caching is disabled by default.
+//
+// The cache is keyed by profile, not account: use WithProfile or
+// WithCacheDir to keep another account's usage apart from the CLI's.
+func WithCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+	}
+}
+
+// WithCacheDir stores the cache under dir instead of the platform default
+func WithCacheDir(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = dir
+	}
+}
+
+// WithProfile keys the cache by profile name, matching the CLI's --profile
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// Client fetches usage for a single account
+type Client struct {
+	usage *usecase.UsageService
+}
+
+// New creates a Client. Unless WithAccessToken is given, credentials are
+// loaded from Claude Code's credentials file, and reloaded on each request
+// so a token Claude Code refreshes is picked up.
+func New(opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// The default cache entry belongs to whichever account the CLI uses
+	if o.accessToken != "" && o.cacheTTL > 0 && o.profile == "" && o.cacheDir == "" {
+		return nil, errors.New("WithCache with WithAccessToken needs WithProfile or WithCacheDir, to keep the token's usage apart from the CLI's cache")
+	}
+
+	credentials := func(ctx context.Context) (*auth.Credentials, error) {
+		if o.accessToken != "" {
+			return &auth.Credentials{AccessToken: o.accessToken}, nil
+		}
+		return auth.Load(o.credentialsPath)
+	}
+	// Fail fast without credentials
+	if _, err := credentials(context.Background()); err != nil {
+		return nil, err
+	}
+
+	var apiOpts []api.ClientOption
+	if o.baseURL != "" {
+		apiOpts = append(apiOpts, api.WithBaseURL(o.baseURL))
+	}
+	if o.httpClient != nil {
+		apiOpts = append(apiOpts, api.WithHTTPClient(o.httpClient))
+	}
+	if o.timeout > 0 {
+		apiOpts = append(apiOpts, api.WithTimeout(o.timeout))
+	}
+	if o.retryPolicy != nil {
+		apiOpts = append(apiOpts, api.WithRetryPolicy(*o.retryPolicy))
+	}
+	usageOpts := []usecase.Option{
+		usecase.WithClient(func(accessToken string) (*api.Client, error) {
+			return api.NewClient(accessToken, apiOpts...), nil
+		}),
+	}
+
+	if o.cacheTTL > 0 {
+		cacheOpts := []cache.Option{cache.WithProfile(o.profile)}
+		if o.cacheDir != "" {
+			cacheOpts = append(cacheOpts, cache.WithDir(o.cacheDir))
+		}
+		usageOpts = append(usageOpts, usecase.WithCache(cache.New(false, cacheOpts...), o.cacheTTL))
+	}
+
+	return &Client{usage: usecase.NewUsageService(credentials, usageOpts...)}, nil
+}
+
+// Usage returns current usage, served from the cache when enabled and fresh.
+// Cancelling ctx aborts the request and any pending retries.
+func (c *Client) Usage(ctx context.Context) (*Usage, error) {
+	return c.usage.Usage(ctx)
+}
@@ -0,0 +1,203 @@
+package claudelimits
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, hits *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 62}, "seven_day": {"utilization": 34}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUsage(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+
+	c, err := New(WithAccessToken("token"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	usage, err := c.Usage(context.Background())
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if usage.FiveHour == nil || usage.FiveHour.Utilization != 62 {
+		t.Errorf("FiveHour = %+v, want utilization 62", usage.FiveHour)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "authentication_error", "message": "invalid token"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := New(WithAccessToken("token"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = c.Usage(context.Background())
+	if !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("err = %v, want ErrAuthRequired", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "authentication_error" {
+		t.Errorf("err = %#v, want an APIError of type authentication_error", err)
+	}
+}
+
+func TestUsageWithCache(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+
+	c, err := New(
+		WithAccessToken("token"),
+		WithBaseURL(server.URL),
+		WithCache(time.Minute),
+		WithCacheDir(t.TempDir()),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Usage(context.Background()); err != nil {
+			t.Fatalf("Usage failed: %v", err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (subsequent calls should be cached)", hits)
+	}
+}
+
+func TestUsageWithSubSecondCache(t *testing.T) {
+	hits := 0
+	server := newTestServer(t, &hits)
+
+	c, err := New(
+		WithAccessToken("token"),
+		WithBaseURL(server.URL),
+		WithCache(500*time.Millisecond),
+		WithCacheDir(t.TempDir()),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Usage(context.Background()); err != nil {
+			t.Fatalf("Usage failed: %v", err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (a sub-second TTL still caches)", hits)
+	}
+}
+
+func TestNewRejectsSharedCacheForAccessToken(t *testing.T) {
+	// The default cache holds the CLI's account, which the token may not
+	// belong to
+	if _, err := New(WithAccessToken("token"), WithCache(time.Minute)); err == nil {
+		t.Error("New should reject WithCache and WithAccessToken without WithProfile or WithCacheDir")
+	}
+	if _, err := New(WithAccessToken("token"), WithCache(time.Minute), WithProfile("other")); err != nil {
+		t.Errorf("New with WithProfile failed: %v", err)
+	}
+	if _, err := New(WithAccessToken("token"), WithCache(time.Minute), WithCacheDir(t.TempDir())); err != nil {
+		t.Errorf("New with WithCacheDir failed: %v", err)
+	}
+}
+
+func TestNewLoadsCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".credentials.json")
+	content := `{"claudeAiOauth": {"accessToken": "file-token", "expiresAt": 0}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write credentials: %v", err)
+	}
+
+	if _, err := New(WithCredentialsPath(path)); err != nil {
+		t.Errorf("New with credentials file failed: %v", err)
+	}
+	if _, err := New(WithCredentialsPath(filepath.Join(t.TempDir(), "missing.json"))); err == nil {
+		t.Error("New should fail when credentials are missing")
+	}
+}
+
+func TestUsageWithRetryPolicy(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = 1
+	policy.InitialBackoff = time.Millisecond
+	c, err := New(WithAccessToken("token"), WithBaseURL(server.URL), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := c.Usage(context.Background()); err == nil {
+		t.Fatal("expected error from failing server")
+	}
+	if hits != 2 {
+		t.Errorf("server hit %d times, want 2 (1 attempt + 1 retry)", hits)
+	}
+}
+
+func TestUsageRevalidatesExpiredCache(t *testing.T) {
+	full, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"five_hour": {"utilization": 62}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	// A nanosecond TTL expires before the next call, so every call
+	// revalidates
+	c, err := New(
+		WithAccessToken("token"),
+		WithBaseURL(server.URL),
+		WithCache(time.Nanosecond),
+		WithCacheDir(t.TempDir()),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		usage, err := c.Usage(context.Background())
+		if err != nil {
+			t.Fatalf("Usage failed: %v", err)
+		}
+		if usage.FiveHour == nil || usage.FiveHour.Utilization != 62 {
+			t.Errorf("FiveHour = %+v, want utilization 62", usage.FiveHour)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Errorf("full = %d, not modified = %d, want 1 and 2", full, notModified)
+	}
+}